@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCmd downloads a backup archive of every event
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export every event to an archive",
+	Long:  "Export every incident and planned maintenance, deleted ones included, to a versioned NDJSON archive (requires auth)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		trace("Executing export command (file: %s)", file)
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("GET", "/api/v1/admin/export", nil)
+		if err != nil {
+			return fmt.Errorf("failed to export events: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("export failed: %s", string(body))
+		}
+
+		if file == "" || file == "-" {
+			_, err := os.Stdout.Write(body)
+			return err
+		}
+		if err := os.WriteFile(file, body, 0600); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		fmt.Printf("Events exported to %s\n", file)
+		return nil
+	},
+}
+
+// importCmd restores events from a backup archive
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import events from an archive",
+	Long: `Import an archive produced by export (requires auth).
+In merge mode (default) missing events are created and differing ones are reported as conflicts.
+In replace mode the server is made to match the archive: differing events are overwritten and
+events missing from the archive are moved to the trash.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mode, _ := cmd.Flags().GetString("mode")
+		trace("Executing import command (file: %s, mode: %s)", args[0], mode)
+
+		var archive []byte
+		var err error
+		if args[0] == "-" {
+			archive, err = io.ReadAll(os.Stdin)
+		} else {
+			archive, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		client := getAPIClient()
+		resp, err := client.makeRawRequest("POST", "/api/v1/admin/import?mode="+url.QueryEscape(mode), "application/x-ndjson", archive)
+		if err != nil {
+			return fmt.Errorf("failed to import events: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("import failed: %s", string(body))
+		}
+
+		if outputFormat != "" {
+			return outputData(body, "Import Report")
+		}
+
+		var report struct {
+			Mode                string            `json:"mode"`
+			Incidents           importKindSummary `json:"incidents"`
+			PlannedMaintenances importKindSummary `json:"planned_maintenances"`
+		}
+		if err := json.Unmarshal(body, &report); err != nil {
+			return fmt.Errorf("invalid JSON response: %w", err)
+		}
+		fmt.Printf("Archive imported (%s)\n", report.Mode)
+		report.Incidents.print("Incidents")
+		report.PlannedMaintenances.print("Maintenances")
+		return nil
+	},
+}
+
+// importKindSummary is the import report of one kind of events
+type importKindSummary struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Unchanged int `json:"unchanged"`
+	Conflicts []struct {
+		GUID   string `json:"guid"`
+		Reason string `json:"reason"`
+	} `json:"conflicts"`
+}
+
+func (s importKindSummary) print(title string) {
+	fmt.Printf("%s: %d created, %d updated, %d deleted, %d unchanged, %d conflicts\n",
+		title, s.Created, s.Updated, s.Deleted, s.Unchanged, len(s.Conflicts))
+	for _, conflict := range s.Conflicts {
+		fmt.Printf("  ! %s %s\n", conflict.GUID, conflict.Reason)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+
+	exportCmd.Flags().StringP("file", "f", "", "Write the archive to this file instead of stdout")
+	importCmd.Flags().String("mode", "merge", "Import mode (merge|replace)")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 )
@@ -28,18 +29,24 @@ func NewAPIClient(baseURL, username, password string) *APIClient {
 	}
 }
 
-// makeRequest makes an HTTP request with basic auth
+// makeRequest makes an HTTP request with basic auth, sending body as JSON
 func (c *APIClient) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
-	var requestBodyString string
+	if body == nil {
+		return c.makeRawRequest(method, endpoint, "", nil)
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return c.makeRawRequest(method, endpoint, "application/json", jsonBody)
+}
 
+// makeRawRequest makes an HTTP request with basic auth, sending body as is with the given content type
+func (c *APIClient) makeRawRequest(method, endpoint, contentType string, body []byte) (*http.Response, error) {
+	var reqBody io.Reader
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonBody)
-		requestBodyString = string(jsonBody)
+		reqBody = bytes.NewReader(body)
 	}
 
 	url := c.BaseURL + endpoint
@@ -49,8 +56,8 @@ func (c *APIClient) makeRequest(method, endpoint string, body interface{}) (*htt
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	if c.Username != "" && c.Password != "" {
@@ -58,7 +65,7 @@ func (c *APIClient) makeRequest(method, endpoint string, body interface{}) (*htt
 	}
 
 	// Detailed REQUEST trace
-	traceRequest(method, url, req.Header, requestBodyString)
+	traceRequest(method, url, req.Header, string(body))
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -81,6 +88,37 @@ func (c *APIClient) makeRequest(method, endpoint string, body interface{}) (*htt
 	return resp, nil
 }
 
+// withSelector adds a label selector to a listing endpoint, the endpoint is returned as is without selector
+func withSelector(endpoint, selector string) string {
+	if selector == "" {
+		return endpoint
+	}
+	return endpoint + "?selector=" + url.QueryEscape(selector)
+}
+
+// postAction calls an action endpoint such as /api/v1/incidents/{id}/resolve with an optional public message
+// and returns the updated event
+func (c *APIClient) postAction(endpoint, message string) ([]byte, error) {
+	var body interface{}
+	if message != "" {
+		body = map[string]string{"message": message}
+	}
+	resp, err := c.makeRequest("POST", endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("%s", string(respBody))
+	}
+	return respBody, nil
+}
+
 // trace outputs trace information if tracing is enabled
 func trace(format string, args ...interface{}) {
 	if os.Getenv("CLARITI_TRACE_ENABLED") != "true" {
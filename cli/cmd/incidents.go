@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"time"
@@ -36,8 +35,10 @@ var incidentListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		trace("Executing incident list command")
 
+		selector, _ := cmd.Flags().GetString("selector")
+
 		client := getAPIClient()
-		resp, err := client.makeRequest("GET", "/api/v1/incidents", nil)
+		resp, err := client.makeRequest("GET", withSelector("/api/v1/incidents", selector), nil)
 		if err != nil {
 			return fmt.Errorf("failed to list incidents: %w", err)
 		}
@@ -97,7 +98,11 @@ var incidentCreateCmd = &cobra.Command{
 		title, _ := cmd.Flags().GetString("title")
 		description, _ := cmd.Flags().GetString("description")
 		severity, _ := cmd.Flags().GetString("severity")
-		componentID, _ := cmd.Flags().GetString("component")
+		componentIDs, _ := cmd.Flags().GetStringSlice("component")
+		impacts, _ := cmd.Flags().GetStringToString("impact")
+		labels, _ := cmd.Flags().GetStringToString("label")
+		status, _ := cmd.Flags().GetString("status")
+		message, _ := cmd.Flags().GetString("message")
 
 		if title == "" {
 			return fmt.Errorf("title is required")
@@ -109,14 +114,26 @@ var incidentCreateCmd = &cobra.Command{
 			"start_effective": time.Now().Format(time.RFC3339), // Default to now for incidents
 			"criticality":     severity,                        // Use severity directly as criticality
 		}
+		if status != "" {
+			incident["status"] = status
+		}
+		if message != "" {
+			incident["message"] = message
+		}
 
-		// Add component if specified (server expects array of strings)
-		if componentID != "" {
-			incident["components"] = []string{componentID}
+		// Add components if specified (server expects array of strings)
+		if len(componentIDs) > 0 {
+			incident["components"] = componentIDs
 		} else {
 			// Server validation requires at least one component
 			incident["components"] = []string{"general"}
 		}
+		if len(impacts) > 0 {
+			incident["component_criticalities"] = impacts
+		}
+		if len(labels) > 0 {
+			incident["labels"] = labels
+		}
 
 		client := getAPIClient()
 		resp, err := client.makeRequest("POST", "/api/v1/incidents", incident)
@@ -142,7 +159,7 @@ var incidentCreateCmd = &cobra.Command{
 var incidentUpdateCmd = &cobra.Command{
 	Use:   "update [incident-id]",
 	Short: "Update an incident",
-	Long:  "Update an existing incident",
+	Long:  "Update an existing incident, --status moves it through its lifecycle with the public --message",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		incidentID := args[0]
@@ -152,8 +169,23 @@ var incidentUpdateCmd = &cobra.Command{
 		description, _ := cmd.Flags().GetString("description")
 		severity, _ := cmd.Flags().GetString("severity")
 		status, _ := cmd.Flags().GetString("status")
+		message, _ := cmd.Flags().GetString("message")
 		componentID, _ := cmd.Flags().GetString("component")
 
+		// The lifecycle status changes through its own endpoint
+		if status != "" {
+			if message == "" {
+				return fmt.Errorf("--message is required with --status")
+			}
+			body, err := transitionIncident(incidentID, status, message)
+			if err != nil {
+				return err
+			}
+			if title == "" && description == "" && severity == "" && componentID == "" {
+				return outputData(body, "Incident Updated")
+			}
+		}
+
 		// Build update object with only non-empty fields
 		update := make(map[string]interface{})
 		if title != "" {
@@ -165,9 +197,6 @@ var incidentUpdateCmd = &cobra.Command{
 		if severity != "" {
 			update["severity"] = severity
 		}
-		if status != "" {
-			update["status"] = status
-		}
 		if componentID != "" {
 			update["component_id"] = componentID
 		}
@@ -196,11 +225,98 @@ var incidentUpdateCmd = &cobra.Command{
 	},
 }
 
+// transitionIncident moves an incident to a lifecycle status and returns the updated incident
+func transitionIncident(incidentID, status, message string) ([]byte, error) {
+	client := getAPIClient()
+	resp, err := client.makeRequest("POST", "/api/v1/incidents/"+incidentID+"/transitions", map[string]string{
+		"status":  status,
+		"message": message,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to change incident status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("change incident status failed: %s", string(body))
+	}
+	return body, nil
+}
+
+// incidentTimelineCmd shows the lifecycle timeline of an incident
+var incidentTimelineCmd = &cobra.Command{
+	Use:   "timeline [incident-id]",
+	Short: "Show the lifecycle timeline of an incident",
+	Long:  "Show every status an incident went through, when, by whom and with which public message",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident timeline command for ID: %s", incidentID)
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("GET", "/api/v1/incidents/"+incidentID+"/transitions", nil)
+		if err != nil {
+			return fmt.Errorf("failed to get incident timeline: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("get incident timeline failed: %s", string(body))
+		}
+
+		return outputData(body, "Incident Timeline")
+	},
+}
+
+// incidentPostCmd posts a public update on an incident
+var incidentPostCmd = &cobra.Command{
+	Use:   "post [incident-id]",
+	Short: "Post an update on an incident",
+	Long:  "Add a timestamped public update to an incident, without changing its description",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident post command for ID: %s", incidentID)
+
+		message, _ := cmd.Flags().GetString("message")
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("POST", "/api/v1/incidents/"+incidentID+"/updates", map[string]string{
+			"message": message,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to post incident update: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 201 {
+			return fmt.Errorf("post incident update failed: %s", string(body))
+		}
+
+		return outputData(body, "Incident Update Posted")
+	},
+}
+
 // incidentDeleteCmd deletes an incident
 var incidentDeleteCmd = &cobra.Command{
 	Use:   "delete [incident-id]",
 	Short: "Delete an incident",
-	Long:  "Delete an existing incident by ID",
+	Long:  "Delete an existing incident by ID, it stays in the trash until restored or purged",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		incidentID := args[0]
@@ -218,93 +334,133 @@ var incidentDeleteCmd = &cobra.Command{
 			return fmt.Errorf("delete incident failed: %s", string(body))
 		}
 
-		fmt.Printf("Incident %s deleted successfully\n", incidentID)
+		fmt.Printf("Incident %s moved to the trash, undo with: incident restore %s\n", incidentID, incidentID)
 		return nil
 	},
 }
 
-// incidentStopCmd stops/resolves an incident (sets effective end time)
+// incidentStopCmd resolves an incident
 var incidentStopCmd = &cobra.Command{
 	Use:   "stop [incident-id]",
-	Short: "Stop/resolve a critical incident",
-	Long:  "Stop/resolve a critical incident by setting the effective end time to now",
+	Short: "Stop/resolve an incident",
+	Long:  "Resolve an incident now, with an optional public --message",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		incidentID := args[0]
 		trace("Executing incident stop command for ID: %s", incidentID)
 
-		client := getAPIClient()
+		message, _ := cmd.Flags().GetString("message")
 
-		// First, get the current incident data
-		resp, err := client.makeRequest("GET", "/api/v1/incidents/"+incidentID, nil)
+		body, err := getAPIClient().postAction("/api/v1/incidents/"+incidentID+"/resolve", message)
 		if err != nil {
-			return fmt.Errorf("failed to get incident: %w", err)
+			return fmt.Errorf("resolve incident failed: %w", err)
 		}
-		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
+		fmt.Printf("Incident %s resolved successfully\n", incidentID)
+		return outputData(body, "Incident Resolved")
+	},
+}
+
+// incidentAcknowledgeCmd records that the current user took charge of an incident
+var incidentAcknowledgeCmd = &cobra.Command{
+	Use:   "acknowledge [incident-id]",
+	Short: "Acknowledge an incident",
+	Long:  "Record that you took charge of an open incident",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident acknowledge command for ID: %s", incidentID)
+
+		body, err := getAPIClient().postAction("/api/v1/incidents/"+incidentID+"/acknowledge", "")
 		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
+			return fmt.Errorf("acknowledge incident failed: %w", err)
 		}
 
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("get incident failed: %s", string(body))
+		fmt.Printf("Incident %s acknowledged successfully\n", incidentID)
+		return outputData(body, "Incident Acknowledged")
+	},
+}
+
+// incidentReopenCmd reopens a resolved incident
+var incidentReopenCmd = &cobra.Command{
+	Use:   "reopen [incident-id]",
+	Short: "Reopen a resolved incident",
+	Long:  "Move a resolved incident back to investigating, with an optional public --message",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident reopen command for ID: %s", incidentID)
+
+		message, _ := cmd.Flags().GetString("message")
+
+		body, err := getAPIClient().postAction("/api/v1/incidents/"+incidentID+"/reopen", message)
+		if err != nil {
+			return fmt.Errorf("reopen incident failed: %w", err)
 		}
 
-		// Parse the existing incident data
-		var incident map[string]interface{}
-		if err := json.Unmarshal(body, &incident); err != nil {
-			return fmt.Errorf("failed to parse incident data: %w", err)
+		fmt.Printf("Incident %s reopened successfully\n", incidentID)
+		return outputData(body, "Incident Reopened")
+	},
+}
+
+// incidentHistoryCmd shows the revision history of an incident
+var incidentHistoryCmd = &cobra.Command{
+	Use:   "history [incident-id]",
+	Short: "Show the history of an incident",
+	Long:  "Show every change made to an incident: who made it, when, and which fields changed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident history command for ID: %s", incidentID)
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("GET", "/api/v1/incidents/"+incidentID+"/revisions", nil)
+		if err != nil {
+			return fmt.Errorf("failed to get incident history: %w", err)
 		}
+		defer resp.Body.Close()
 
-		// Convert components from objects to string array (server format difference)
-		if components, ok := incident["components"].([]interface{}); ok {
-			var componentCodes []string
-			for _, comp := range components {
-				if compMap, ok := comp.(map[string]interface{}); ok {
-					if code, ok := compMap["code"].(string); ok {
-						componentCodes = append(componentCodes, code)
-					}
-				}
-			}
-			incident["components"] = componentCodes
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
 		}
 
-		// Convert criticality from number to string (server format difference)
-		if criticality, ok := incident["criticality"].(float64); ok {
-			criticalityMap := map[int]string{
-				0: "operational",
-				1: "degraded",
-				2: "partial outage",
-				3: "major outage",
-				4: "under maintenance",
-			}
-			if critStr, exists := criticalityMap[int(criticality)]; exists {
-				incident["criticality"] = critStr
-			}
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("get incident history failed: %s", string(body))
 		}
 
-		// Add end_effective to the existing data
-		incident["end_effective"] = time.Now().Format(time.RFC3339)
+		return outputData(body, "Incident History")
+	},
+}
+
+// incidentRestoreCmd restores a deleted incident
+var incidentRestoreCmd = &cobra.Command{
+	Use:   "restore [incident-id]",
+	Short: "Restore a deleted incident",
+	Long:  "Take a deleted incident out of the trash",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident restore command for ID: %s", incidentID)
 
-		// Update the incident with all required fields
-		resp, err = client.makeRequest("PUT", "/api/v1/incidents/"+incidentID, incident)
+		client := getAPIClient()
+		resp, err := client.makeRequest("POST", "/api/v1/incidents/"+incidentID+"/restore", nil)
 		if err != nil {
-			return fmt.Errorf("failed to stop incident: %w", err)
+			return fmt.Errorf("failed to restore incident: %w", err)
 		}
 		defer resp.Body.Close()
 
-		body, err = io.ReadAll(resp.Body)
+		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return fmt.Errorf("failed to read response: %w", err)
 		}
 
 		if resp.StatusCode != 200 {
-			return fmt.Errorf("stop incident failed: %s", string(body))
+			return fmt.Errorf("restore incident failed: %s", string(body))
 		}
 
-		fmt.Printf("Incident %s resolved successfully\n", incidentID)
-		return outputData(body, "Incident Resolved")
+		fmt.Printf("Incident %s restored successfully\n", incidentID)
+		return outputData(body, "Incident Restored")
 	},
 }
 
@@ -318,19 +474,41 @@ func init() {
 	incidentCmd.AddCommand(incidentCreateCmd)
 	incidentCmd.AddCommand(incidentUpdateCmd)
 	incidentCmd.AddCommand(incidentStopCmd)
+	incidentCmd.AddCommand(incidentAcknowledgeCmd)
+	incidentCmd.AddCommand(incidentReopenCmd)
 	incidentCmd.AddCommand(incidentDeleteCmd)
+	incidentCmd.AddCommand(incidentHistoryCmd)
+	incidentCmd.AddCommand(incidentTimelineCmd)
+	incidentCmd.AddCommand(incidentPostCmd)
+	incidentCmd.AddCommand(incidentRestoreCmd)
+
+	// Flags for list command
+	incidentListCmd.Flags().String("selector", "", "Label selector (e.g. team=payments,source in (alertmanager,grafana))")
 
 	// Flags for create command
 	incidentCreateCmd.Flags().String("title", "", "Incident title (required)")
 	incidentCreateCmd.Flags().String("description", "", "Incident description")
 	incidentCreateCmd.Flags().String("severity", "partial outage", "Incident criticality (operational|degraded|partial outage|major outage|under maintenance)")
-	incidentCreateCmd.Flags().String("component", "", "Component ID")
+	incidentCreateCmd.Flags().StringSlice("component", nil, "Component ID, repeat or separate with commas for several components")
+	incidentCreateCmd.Flags().StringToString("impact", nil, "Criticality of a component when it differs from --severity, as component=criticality (e.g. cache=degraded)")
+	incidentCreateCmd.Flags().StringToString("label", nil, "Label as key=value (e.g. team=payments), repeat or separate with commas for several labels")
+	incidentCreateCmd.Flags().String("status", "", "Initial lifecycle status (investigating|identified|monitoring), investigating by default")
+	incidentCreateCmd.Flags().String("message", "", "Public message of the initial status, the description by default")
 	incidentCreateCmd.MarkFlagRequired("title")
 
 	// Flags for update command
 	incidentUpdateCmd.Flags().String("title", "", "Incident title")
 	incidentUpdateCmd.Flags().String("description", "", "Incident description")
 	incidentUpdateCmd.Flags().String("severity", "", "Incident criticality (operational|degraded|partial outage|major outage|under maintenance)")
-	incidentUpdateCmd.Flags().String("status", "", "Incident status (investigating|identified|monitoring|resolved)")
+	incidentUpdateCmd.Flags().String("status", "", "Incident lifecycle status (investigating|identified|monitoring|resolved)")
+	incidentUpdateCmd.Flags().String("message", "", "Public message explaining the status change, required with --status")
 	incidentUpdateCmd.Flags().String("component", "", "Component ID")
+
+	// Flags for post command
+	incidentPostCmd.Flags().String("message", "", "Public update message (required)")
+	incidentPostCmd.MarkFlagRequired("message")
+
+	// Flags for action commands
+	incidentStopCmd.Flags().String("message", "", "Public message of the resolution")
+	incidentReopenCmd.Flags().String("message", "", "Public message explaining why the incident is reopened")
 }
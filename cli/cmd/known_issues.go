@@ -139,35 +139,21 @@ var knownIssueCreateCmd = &cobra.Command{
 	},
 }
 
-// knownIssueStopCmd stops/resolves a known issue (sets effective end time)
+// knownIssueStopCmd resolves a known issue
 var knownIssueStopCmd = &cobra.Command{
 	Use:   "stop [issue-id]",
 	Short: "Stop/resolve a known issue",
-	Long:  "Stop/resolve a known issue by setting the effective end time to now",
+	Long:  "Resolve a known issue now, with an optional public --message",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		issueID := args[0]
 		trace("Executing known-issue stop command for ID: %s", issueID)
 
-		// Update with current time as effective end
-		update := map[string]interface{}{
-			"end_effective": time.Now().Format(time.RFC3339),
-		}
-
-		client := getAPIClient()
-		resp, err := client.makeRequest("PUT", "/api/v1/known-issues/"+issueID, update)
-		if err != nil {
-			return fmt.Errorf("failed to stop known issue: %w", err)
-		}
-		defer resp.Body.Close()
+		message, _ := cmd.Flags().GetString("message")
 
-		body, err := io.ReadAll(resp.Body)
+		body, err := getAPIClient().postAction("/api/v1/incidents/"+issueID+"/resolve", message)
 		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
-		}
-
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("stop known issue failed: %s", string(body))
+			return fmt.Errorf("resolve known issue failed: %w", err)
 		}
 
 		fmt.Printf("Known issue %s resolved successfully\n", issueID)
@@ -191,4 +177,7 @@ func init() {
 	knownIssueCreateCmd.Flags().String("severity", "degraded", "Known issue criticality (operational|degraded|partial outage|major outage|under maintenance)")
 	knownIssueCreateCmd.Flags().String("component", "", "Component ID")
 	knownIssueCreateCmd.MarkFlagRequired("title")
+
+	// Flags for stop command
+	knownIssueStopCmd.Flags().String("message", "", "Public message of the resolution")
 }
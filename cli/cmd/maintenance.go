@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"time"
@@ -37,8 +36,10 @@ var maintenanceListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		trace("Executing maintenance list command")
 
+		selector, _ := cmd.Flags().GetString("selector")
+
 		client := getAPIClient()
-		resp, err := client.makeRequest("GET", "/api/v1/planned-maintenances", nil)
+		resp, err := client.makeRequest("GET", withSelector("/api/v1/planned-maintenances", selector), nil)
 		if err != nil {
 			return fmt.Errorf("failed to list maintenances: %w", err)
 		}
@@ -91,7 +92,7 @@ var maintenanceGetCmd = &cobra.Command{
 var maintenanceCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new planned maintenance",
-	Long:  "Create a new planned maintenance with title, description, and schedule",
+	Long:  "Create a new planned maintenance with title, description, and schedule, optionally repeated by a recurrence rule",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		trace("Executing maintenance create command")
 
@@ -100,6 +101,10 @@ var maintenanceCreateCmd = &cobra.Command{
 		componentID, _ := cmd.Flags().GetString("component")
 		startTime, _ := cmd.Flags().GetString("start-time")
 		endTime, _ := cmd.Flags().GetString("end-time")
+		recurrence, _ := cmd.Flags().GetString("recurrence")
+		autoStart, _ := cmd.Flags().GetBool("auto-start")
+		autoComplete, _ := cmd.Flags().GetBool("auto-complete")
+		labels, _ := cmd.Flags().GetStringToString("label")
 
 		if title == "" {
 			return fmt.Errorf("title is required")
@@ -126,6 +131,22 @@ var maintenanceCreateCmd = &cobra.Command{
 			"end_planned":   endTime,
 		}
 
+		// The start and end times then describe the first occurrence of the series
+		if recurrence != "" {
+			maintenance["recurrence"] = recurrence
+		}
+
+		// Let the server scheduler start and complete the maintenance at the planned times
+		if autoStart {
+			maintenance["auto_start"] = true
+		}
+		if autoComplete {
+			maintenance["auto_complete"] = true
+		}
+		if len(labels) > 0 {
+			maintenance["labels"] = labels
+		}
+
 		// Add component if specified (server expects array of strings)
 		if componentID != "" {
 			maintenance["components"] = []string{componentID}
@@ -216,96 +237,113 @@ var maintenanceUpdateCmd = &cobra.Command{
 	},
 }
 
-// maintenanceStartCmd starts a planned maintenance (sets effective start time)
+// maintenanceStartCmd starts a planned maintenance now
 var maintenanceStartCmd = &cobra.Command{
 	Use:   "start [maintenance-id]",
 	Short: "Start a planned maintenance",
-	Long:  "Start a planned maintenance by setting the effective start time to now",
+	Long:  "Start a planned maintenance now, an optional --message is posted as an update",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		maintenanceID := args[0]
 		trace("Executing maintenance start command for ID: %s", maintenanceID)
 
-		client := getAPIClient()
+		message, _ := cmd.Flags().GetString("message")
 
-		// First, get the existing maintenance data
-		resp, err := client.makeRequest("GET", "/api/v1/planned-maintenances/"+maintenanceID, nil)
+		body, err := getAPIClient().postAction("/api/v1/planned-maintenances/"+maintenanceID+"/start", message)
 		if err != nil {
-			return fmt.Errorf("failed to get maintenance: %w", err)
+			return fmt.Errorf("start maintenance failed: %w", err)
 		}
-		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
-		}
+		fmt.Printf("Maintenance %s started successfully\n", maintenanceID)
+		return outputData(body, "Maintenance Started")
+	},
+}
 
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("get maintenance failed: %s", string(body))
-		}
+// maintenanceStopCmd completes a started maintenance now
+var maintenanceStopCmd = &cobra.Command{
+	Use:   "stop [maintenance-id]",
+	Short: "Stop a planned maintenance",
+	Long:  "Complete a started maintenance now, an optional --message is posted as an update",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		maintenanceID := args[0]
+		trace("Executing maintenance stop command for ID: %s", maintenanceID)
 
-		// Parse the existing maintenance data
-		var maintenance map[string]interface{}
-		if err := json.Unmarshal(body, &maintenance); err != nil {
-			return fmt.Errorf("failed to parse maintenance data: %w", err)
-		}
+		message, _ := cmd.Flags().GetString("message")
 
-		// Convert components from objects to string array (server format difference)
-		if components, ok := maintenance["components"].([]interface{}); ok {
-			var componentCodes []string
-			for _, comp := range components {
-				if compMap, ok := comp.(map[string]interface{}); ok {
-					if code, ok := compMap["code"].(string); ok {
-						componentCodes = append(componentCodes, code)
-					}
-				}
-			}
-			maintenance["components"] = componentCodes
+		body, err := getAPIClient().postAction("/api/v1/planned-maintenances/"+maintenanceID+"/complete", message)
+		if err != nil {
+			return fmt.Errorf("complete maintenance failed: %w", err)
 		}
 
-		// Add start_effective to the existing data
-		maintenance["start_effective"] = time.Now().Format(time.RFC3339)
+		fmt.Printf("Maintenance %s completed successfully\n", maintenanceID)
+		return outputData(body, "Maintenance Completed")
+	},
+}
+
+// maintenanceCancelCmd cancels a maintenance which did not start
+var maintenanceCancelCmd = &cobra.Command{
+	Use:   "cancel [maintenance-id]",
+	Short: "Cancel a planned maintenance",
+	Long:  "Cancel a maintenance which did not start, an optional --message is posted as an update",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		maintenanceID := args[0]
+		trace("Executing maintenance cancel command for ID: %s", maintenanceID)
+
+		message, _ := cmd.Flags().GetString("message")
 
-		// Update the maintenance with all required fields
-		resp, err = client.makeRequest("PUT", "/api/v1/planned-maintenances/"+maintenanceID, maintenance)
+		body, err := getAPIClient().postAction("/api/v1/planned-maintenances/"+maintenanceID+"/cancel", message)
 		if err != nil {
-			return fmt.Errorf("failed to start maintenance: %w", err)
+			return fmt.Errorf("cancel maintenance failed: %w", err)
 		}
-		defer resp.Body.Close()
 
-		body, err = io.ReadAll(resp.Body)
+		fmt.Printf("Maintenance %s cancelled successfully\n", maintenanceID)
+		return outputData(body, "Maintenance Cancelled")
+	},
+}
+
+// maintenanceDeleteCmd deletes a maintenance
+var maintenanceDeleteCmd = &cobra.Command{
+	Use:   "delete [maintenance-id]",
+	Short: "Delete a planned maintenance",
+	Long:  "Delete an existing planned maintenance by ID, it stays in the trash until restored or purged",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		maintenanceID := args[0]
+		trace("Executing maintenance delete command for ID: %s", maintenanceID)
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("DELETE", "/api/v1/planned-maintenances/"+maintenanceID, nil)
 		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
+			return fmt.Errorf("failed to delete maintenance: %w", err)
 		}
+		defer resp.Body.Close()
 
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("start maintenance failed: %s", string(body))
+		if resp.StatusCode != 204 {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("delete maintenance failed: %s", string(body))
 		}
 
-		fmt.Printf("Maintenance %s started successfully\n", maintenanceID)
-		return outputData(body, "Maintenance Started")
+		fmt.Printf("Maintenance %s moved to the trash, undo with: maintenance restore %s\n", maintenanceID, maintenanceID)
+		return nil
 	},
 }
 
-// maintenanceStopCmd stops a planned maintenance (sets effective end time)
-var maintenanceStopCmd = &cobra.Command{
-	Use:   "stop [maintenance-id]",
-	Short: "Stop a planned maintenance",
-	Long:  "Stop a planned maintenance by setting the effective end time to now",
+// maintenanceHistoryCmd shows the revision history of a maintenance
+var maintenanceHistoryCmd = &cobra.Command{
+	Use:   "history [maintenance-id]",
+	Short: "Show the history of a maintenance",
+	Long:  "Show every change made to a maintenance: who made it, when, and which fields changed",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		maintenanceID := args[0]
-		trace("Executing maintenance stop command for ID: %s", maintenanceID)
-
-		// Update with current time as effective end
-		update := map[string]interface{}{
-			"end_effective": time.Now().Format(time.RFC3339),
-		}
+		trace("Executing maintenance history command for ID: %s", maintenanceID)
 
 		client := getAPIClient()
-		resp, err := client.makeRequest("PUT", "/api/v1/planned-maintenances/"+maintenanceID, update)
+		resp, err := client.makeRequest("GET", "/api/v1/planned-maintenances/"+maintenanceID+"/revisions", nil)
 		if err != nil {
-			return fmt.Errorf("failed to stop maintenance: %w", err)
+			return fmt.Errorf("failed to get maintenance history: %w", err)
 		}
 		defer resp.Body.Close()
 
@@ -315,38 +353,41 @@ var maintenanceStopCmd = &cobra.Command{
 		}
 
 		if resp.StatusCode != 200 {
-			return fmt.Errorf("stop maintenance failed: %s", string(body))
+			return fmt.Errorf("get maintenance history failed: %s", string(body))
 		}
 
-		fmt.Printf("Maintenance %s stopped successfully\n", maintenanceID)
-		return outputData(body, "Maintenance Stopped")
+		return outputData(body, "Maintenance History")
 	},
 }
 
-// maintenanceDeleteCmd deletes a maintenance
-var maintenanceDeleteCmd = &cobra.Command{
-	Use:   "delete [maintenance-id]",
-	Short: "Delete a planned maintenance",
-	Long:  "Delete an existing planned maintenance by ID",
+// maintenanceRestoreCmd restores a deleted maintenance
+var maintenanceRestoreCmd = &cobra.Command{
+	Use:   "restore [maintenance-id]",
+	Short: "Restore a deleted planned maintenance",
+	Long:  "Take a deleted planned maintenance out of the trash",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		maintenanceID := args[0]
-		trace("Executing maintenance delete command for ID: %s", maintenanceID)
+		trace("Executing maintenance restore command for ID: %s", maintenanceID)
 
 		client := getAPIClient()
-		resp, err := client.makeRequest("DELETE", "/api/v1/planned-maintenances/"+maintenanceID, nil)
+		resp, err := client.makeRequest("POST", "/api/v1/planned-maintenances/"+maintenanceID+"/restore", nil)
 		if err != nil {
-			return fmt.Errorf("failed to delete maintenance: %w", err)
+			return fmt.Errorf("failed to restore maintenance: %w", err)
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != 204 {
-			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("delete maintenance failed: %s", string(body))
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
 		}
 
-		fmt.Printf("Maintenance %s deleted successfully\n", maintenanceID)
-		return nil
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("restore maintenance failed: %s", string(body))
+		}
+
+		fmt.Printf("Maintenance %s restored successfully\n", maintenanceID)
+		return outputData(body, "Maintenance Restored")
 	},
 }
 
@@ -361,7 +402,13 @@ func init() {
 	maintenanceCmd.AddCommand(maintenanceUpdateCmd)
 	maintenanceCmd.AddCommand(maintenanceStartCmd)
 	maintenanceCmd.AddCommand(maintenanceStopCmd)
+	maintenanceCmd.AddCommand(maintenanceCancelCmd)
 	maintenanceCmd.AddCommand(maintenanceDeleteCmd)
+	maintenanceCmd.AddCommand(maintenanceHistoryCmd)
+	maintenanceCmd.AddCommand(maintenanceRestoreCmd)
+
+	// Flags for list command
+	maintenanceListCmd.Flags().String("selector", "", "Label selector (e.g. team=dba,!draft)")
 
 	// Flags for create command
 	maintenanceCreateCmd.Flags().String("title", "", "Maintenance title (required)")
@@ -369,6 +416,10 @@ func init() {
 	maintenanceCreateCmd.Flags().String("component", "", "Component ID")
 	maintenanceCreateCmd.Flags().String("start-time", "", "Start time (RFC3339 format)")
 	maintenanceCreateCmd.Flags().String("end-time", "", "End time (RFC3339 format)")
+	maintenanceCreateCmd.Flags().String("recurrence", "", "Repeat the maintenance with an RRULE (e.g. FREQ=MONTHLY;BYDAY=2TU)")
+	maintenanceCreateCmd.Flags().Bool("auto-start", false, "Start the maintenance automatically at its start time")
+	maintenanceCreateCmd.Flags().Bool("auto-complete", false, "Complete the maintenance automatically at its end time")
+	maintenanceCreateCmd.Flags().StringToString("label", nil, "Label as key=value (e.g. team=dba), repeat or separate with commas for several labels")
 	maintenanceCreateCmd.MarkFlagRequired("title")
 
 	// Flags for update command
@@ -378,4 +429,9 @@ func init() {
 	maintenanceUpdateCmd.Flags().String("component", "", "Component ID")
 	maintenanceUpdateCmd.Flags().String("start-time", "", "Start time (RFC3339 format)")
 	maintenanceUpdateCmd.Flags().String("end-time", "", "End time (RFC3339 format)")
+
+	// Flags for action commands
+	maintenanceStartCmd.Flags().String("message", "", "Update posted when the maintenance starts")
+	maintenanceStopCmd.Flags().String("message", "", "Update posted when the maintenance completes")
+	maintenanceCancelCmd.Flags().String("message", "", "Update posted when the maintenance is cancelled")
 }
@@ -125,6 +125,44 @@ func displayListPretty(items []OutputItem, title string) {
 	}
 }
 
+// displayRevisionsPretty shows a revision history, one block per write with its changed fields
+func displayRevisionsPretty(items []OutputItem, title string) {
+	fmt.Printf("Getting %s...\n", strings.ToLower(title))
+	fmt.Printf("OK\n")
+	fmt.Println()
+
+	for _, item := range items {
+		author := getString(item, "author", "unknown")
+		version, _ := item["version"].(float64)
+		fmt.Printf("%s  v%d  %s by %s\n", getTime(item, "timestamp"), int(version), getString(item, "action", "-"), author)
+
+		changes, _ := item["changes"].([]interface{})
+		for _, raw := range changes {
+			change, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			field, _ := change["field"].(string)
+			fmt.Printf("  %-16s %s -> %s\n", field, formatChangeValue(change["before"]), formatChangeValue(change["after"]))
+		}
+	}
+}
+
+// formatChangeValue renders a changed field value on a single line
+func formatChangeValue(value interface{}) string {
+	if value == nil {
+		return "-"
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
 // Helper functions
 func getString(item OutputItem, key, defaultValue string) string {
 	if value, ok := item[key].(string); ok {
@@ -236,6 +274,11 @@ func outputDataPretty(data []byte, title string) error {
 	// Try to parse as array
 	var items []OutputItem
 	if err := json.Unmarshal(data, &items); err == nil {
+		// Special handling for revision history
+		if len(items) > 0 && items[0]["action"] != nil && items[0]["changes"] != nil {
+			displayRevisionsPretty(items, title)
+			return nil
+		}
 		displayPretty(items, title)
 		return nil
 	}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// postmortemCmd represents the incident postmortem command
+var postmortemCmd = &cobra.Command{
+	Use:   "postmortem",
+	Short: "Manage incident postmortems",
+	Long:  "Write, show and delete the postmortem of resolved incidents, and list the incidents still missing one",
+}
+
+// postmortemGetCmd shows the postmortem of an incident
+var postmortemGetCmd = &cobra.Command{
+	Use:   "get [incident-id]",
+	Short: "Show the postmortem of an incident",
+	Long:  "Show the summary, root cause, contributing factors and action items of an incident postmortem",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident postmortem get command for ID: %s", incidentID)
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("GET", "/api/v1/incidents/"+incidentID+"/postmortem", nil)
+		if err != nil {
+			return fmt.Errorf("failed to get incident postmortem: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("get incident postmortem failed: %s", string(body))
+		}
+
+		return outputData(body, "Incident Postmortem")
+	},
+}
+
+// postmortemSetCmd writes the postmortem of an incident
+var postmortemSetCmd = &cobra.Command{
+	Use:   "set [incident-id]",
+	Short: "Write the postmortem of an incident",
+	Long:  "Write or replace the postmortem of a resolved incident, it shows on the status page as final once --published",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident postmortem set command for ID: %s", incidentID)
+
+		summary, _ := cmd.Flags().GetString("summary")
+		rootCause, _ := cmd.Flags().GetString("root-cause")
+		factors, _ := cmd.Flags().GetStringArray("factor")
+		actions, _ := cmd.Flags().GetStringArray("action")
+		published, _ := cmd.Flags().GetBool("published")
+
+		postmortem := map[string]interface{}{
+			"summary":    summary,
+			"root_cause": rootCause,
+			"published":  published,
+		}
+		if len(factors) > 0 {
+			postmortem["contributing_factors"] = factors
+		}
+		if len(actions) > 0 {
+			items := make([]map[string]interface{}, 0, len(actions))
+			for _, action := range actions {
+				item, err := parseActionItem(action)
+				if err != nil {
+					return err
+				}
+				items = append(items, item)
+			}
+			postmortem["action_items"] = items
+		}
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("PUT", "/api/v1/incidents/"+incidentID+"/postmortem", postmortem)
+		if err != nil {
+			return fmt.Errorf("failed to write incident postmortem: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 && resp.StatusCode != 201 {
+			return fmt.Errorf("write incident postmortem failed: %s", string(body))
+		}
+
+		return outputData(body, "Incident Postmortem Written")
+	},
+}
+
+// parseActionItem parses an --action flag written as owner:due-date:description, the due date being optional
+func parseActionItem(value string) (map[string]interface{}, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid action %q, expected owner:due-date:description", value)
+	}
+	item := map[string]interface{}{
+		"owner":       strings.TrimSpace(parts[0]),
+		"description": strings.TrimSpace(parts[2]),
+	}
+	if due := strings.TrimSpace(parts[1]); due != "" {
+		dueDate, err := time.Parse("2006-01-02", due)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date %q in action %q, expected YYYY-MM-DD", due, value)
+		}
+		item["due_date"] = dueDate.Format(time.RFC3339)
+	}
+	return item, nil
+}
+
+// postmortemDeleteCmd deletes the postmortem of an incident
+var postmortemDeleteCmd = &cobra.Command{
+	Use:   "delete [incident-id]",
+	Short: "Delete the postmortem of an incident",
+	Long:  "Delete the postmortem of an incident, the incident then shows as missing one again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		incidentID := args[0]
+		trace("Executing incident postmortem delete command for ID: %s", incidentID)
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("DELETE", "/api/v1/incidents/"+incidentID+"/postmortem", nil)
+		if err != nil {
+			return fmt.Errorf("failed to delete incident postmortem: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 204 {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("delete incident postmortem failed: %s", string(body))
+		}
+
+		fmt.Printf("Postmortem of incident %s deleted successfully\n", incidentID)
+		return nil
+	},
+}
+
+// postmortemMissingCmd lists the resolved incidents without a published postmortem
+var postmortemMissingCmd = &cobra.Command{
+	Use:   "missing",
+	Short: "List the incidents missing a postmortem",
+	Long:  "List the resolved incidents of at least --criticality without a published postmortem",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		trace("Executing incident postmortem missing command")
+
+		criticality, _ := cmd.Flags().GetString("criticality")
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("GET", "/api/v1/incidents/missing-postmortems?criticality="+url.QueryEscape(criticality), nil)
+		if err != nil {
+			return fmt.Errorf("failed to list incidents missing a postmortem: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("list incidents missing a postmortem failed: %s", string(body))
+		}
+
+		return outputData(body, "Incidents Missing a Postmortem")
+	},
+}
+
+func init() {
+	// Add postmortem command to incident
+	incidentCmd.AddCommand(postmortemCmd)
+
+	// Add subcommands to postmortem
+	postmortemCmd.AddCommand(postmortemGetCmd)
+	postmortemCmd.AddCommand(postmortemSetCmd)
+	postmortemCmd.AddCommand(postmortemDeleteCmd)
+	postmortemCmd.AddCommand(postmortemMissingCmd)
+
+	// Flags for set command
+	postmortemSetCmd.Flags().String("summary", "", "What happened and its impact (required)")
+	postmortemSetCmd.Flags().String("root-cause", "", "Root cause of the incident (required)")
+	postmortemSetCmd.Flags().StringArray("factor", nil, "Contributing factor, repeat for several factors")
+	postmortemSetCmd.Flags().StringArray("action", nil, "Action item as owner:due-date:description (e.g. bob:2026-01-31:Alert on certificate expiry), repeat for several items")
+	postmortemSetCmd.Flags().Bool("published", false, "Publish the postmortem as final, it stays a draft otherwise")
+	postmortemSetCmd.MarkFlagRequired("summary")
+	postmortemSetCmd.MarkFlagRequired("root-cause")
+
+	// Flags for missing command
+	postmortemMissingCmd.Flags().String("criticality", "major outage", "Minimum criticality of the listed incidents (degraded|partial outage|major outage)")
+}
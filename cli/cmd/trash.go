@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+// trashCmd represents the trash command
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted events",
+	Long:  "List and purge the deleted incidents and planned maintenances",
+}
+
+// trashListCmd lists the deleted events
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deleted events",
+	Long:  "List the deleted incidents and planned maintenances, most recently deleted first",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		trace("Executing trash list command")
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("GET", "/api/v1/trash", nil)
+		if err != nil {
+			return fmt.Errorf("failed to list trash: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("list trash failed: %s", string(body))
+		}
+
+		if outputFormat != "" {
+			return outputData(body, "Trash")
+		}
+
+		// Pretty output shows both lists one after the other
+		var trash struct {
+			Incidents           json.RawMessage `json:"incidents"`
+			PlannedMaintenances json.RawMessage `json:"planned_maintenances"`
+		}
+		if err := json.Unmarshal(body, &trash); err != nil {
+			return fmt.Errorf("invalid JSON response: %w", err)
+		}
+		if err := outputData(trash.Incidents, "Deleted Incidents"); err != nil {
+			return err
+		}
+		fmt.Println()
+		return outputData(trash.PlannedMaintenances, "Deleted Maintenances")
+	},
+}
+
+// trashPurgeCmd permanently removes old deleted events
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently remove old deleted events",
+	Long:  "Permanently remove the events deleted longer ago than the server purge age, or --older-than when given",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		trace("Executing trash purge command (older than: %s)", olderThan)
+
+		endpoint := "/api/v1/trash/purge"
+		if olderThan != "" {
+			endpoint += "?older_than=" + url.QueryEscape(olderThan)
+		}
+
+		client := getAPIClient()
+		resp, err := client.makeRequest("POST", endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("failed to purge trash: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("purge trash failed: %s", string(body))
+		}
+
+		var result struct {
+			Purged int `json:"purged"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("invalid JSON response: %w", err)
+		}
+		fmt.Printf("%d deleted events purged\n", result.Purged)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+
+	trashPurgeCmd.Flags().String("older-than", "", "Only purge events deleted longer ago than this duration (e.g. 72h)")
+}
@@ -90,9 +90,19 @@ type BaseEvent struct {
 	Title          string                 `json:"title"`
 	Content        string                 `json:"content"`
 	ExtraFields    map[string]string      `json:"extra_fields"`
+	Labels         map[string]string      `json:"labels,omitempty"` // Queryable with selectors, unlike ExtraFields
 	Components     []*component.Component `json:"components,omitempty"`
 	StartEffective *time.Time             `json:"start_effective"`
 	EndEffective   *time.Time             `json:"end_effective"`
+	Updates        []Update               `json:"updates,omitempty"`    // Public updates, oldest first
+	Version        int64                  `json:"version"`              // Revision counter, incremented by the storage on every write
+	DeletedAt      *time.Time             `json:"deleted_at,omitempty"` // Set while the event is in the trash
+	DeletedBy      string                 `json:"deleted_by,omitempty"`
+}
+
+// IsDeleted returns true if the event was moved to the trash
+func (b *BaseEvent) IsDeleted() bool {
+	return b.DeletedAt != nil
 }
 
 // NewBaseEvent creates a new BaseEvent with automatically generated GUID
@@ -340,3 +340,28 @@ func TestBaseEvent_ExtraFieldsHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseEvent_PostUpdate(t *testing.T) {
+	base := NewBaseEvent("Outage", "API is down", nil)
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	first := base.PostUpdate("Cause identified", "alice", start)
+	stored := base
+	second := base.PostUpdate("Fix deployed", "bob", start.Add(time.Hour))
+
+	if first.GUID == "" || first.GUID == second.GUID {
+		t.Errorf("Expected distinct update GUIDs, got %q and %q", first.GUID, second.GUID)
+	}
+	if len(base.Updates) != 2 || base.Updates[0] != first || base.Updates[1] != second {
+		t.Errorf("Expected updates in posting order, got %+v", base.Updates)
+	}
+	if len(stored.Updates) != 1 {
+		t.Errorf("Expected the copy taken before the second post to keep 1 update, got %d", len(stored.Updates))
+	}
+
+	// The copy must not see updates posted on the original through a shared backing array
+	stored.PostUpdate("Other", "carol", start)
+	if base.Updates[1] != second {
+		t.Errorf("Expected posting on a copy to leave the original untouched, got %+v", base.Updates[1])
+	}
+}
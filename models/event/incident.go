@@ -9,8 +9,14 @@ import (
 // Incident represents a service incident or known issue
 type Incident struct {
 	BaseEvent
-	Perpetual           bool        `json:"perpetual,omitempty"` // Indicates if the incident is perpetual (known issue)
-	IncidentCriticality Criticality `json:"criticality,omitempty"`
+	Perpetual              bool                   `json:"perpetual,omitempty"` // Indicates if the incident is perpetual (known issue)
+	IncidentCriticality    Criticality            `json:"criticality,omitempty"`
+	ComponentCriticalities map[string]Criticality `json:"component_criticalities,omitempty"` // Impact by component code, IncidentCriticality for the others
+	LifecycleStatus        Status                 `json:"lifecycle_status,omitempty"`        // Set by Transition, empty for incidents outside the lifecycle
+	Transitions            []StatusTransition     `json:"transitions,omitempty"`             // Lifecycle timeline, oldest first
+	AcknowledgedAt         *time.Time             `json:"acknowledged_at,omitempty"`         // Set by Acknowledge, cleared when the incident is reopened
+	AcknowledgedBy         string                 `json:"acknowledged_by,omitempty"`
+	Postmortem             *Postmortem            `json:"postmortem,omitempty"` // Set by SetPostmortem once the incident is resolved
 }
 
 // Type returns the event type based on whether it's perpetual or not
@@ -21,19 +27,48 @@ func (i *Incident) Type() TypeEvent {
 	return TypeFiringIncident
 }
 
-// Status returns the current status based on timing
+// Status returns the lifecycle status of the incident, or the status derived from timing outside the lifecycle.
+// An acknowledged incident nobody identified the cause of yet is acknowledged.
 func (i *Incident) Status() Status {
+	if i.LifecycleStatus != "" {
+		if i.LifecycleStatus == StatusInvestigating && i.AcknowledgedAt != nil {
+			return StatusAcknowledged
+		}
+		return i.LifecycleStatus
+	}
 	if i.EndEffective != nil && i.EndEffective.Before(time.Now()) {
 		return StatusResolved
 	}
 	if i.StartEffective != nil && i.StartEffective.Before(time.Now()) {
+		if i.AcknowledgedAt != nil {
+			return StatusAcknowledged
+		}
 		return StatusOnGoing
 	}
 	return StatusUnknown
 }
 
-// Criticality returns the incident criticality level
+// Criticality returns the highest impact of the incident on its components, or its criticality without components
 func (i *Incident) Criticality() Criticality {
+	if len(i.Components) == 0 {
+		return i.IncidentCriticality
+	}
+	highest := CriticalityUnknown
+	for _, comp := range i.Components {
+		if criticality := i.CriticalityFor(comp); criticality > highest {
+			highest = criticality
+		}
+	}
+	return highest
+}
+
+// CriticalityFor returns the impact of the incident on one of its components
+func (i *Incident) CriticalityFor(comp *component.Component) Criticality {
+	if comp != nil {
+		if criticality, ok := i.ComponentCriticalities[comp.Code]; ok {
+			return criticality
+		}
+	}
 	return i.IncidentCriticality
 }
 
@@ -137,6 +137,30 @@ func TestIncident_Criticality(t *testing.T) {
 	}
 }
 
+func TestIncident_ComponentCriticalities(t *testing.T) {
+	api := &component.Component{BaseComponent: component.BaseComponent{Name: "API", Code: "api"}}
+	cache := &component.Component{BaseComponent: component.BaseComponent{Name: "Cache", Code: "cache"}}
+	incident := NewFiringIncident("Outage", "Requests fail", []*component.Component{api, cache}, CriticalityDegraded)
+
+	incident.ComponentCriticalities = map[string]Criticality{"api": CriticalityMajorOutage}
+	if got := incident.CriticalityFor(api); got != CriticalityMajorOutage {
+		t.Errorf("CriticalityFor(api) = %v, want %v", got, CriticalityMajorOutage)
+	}
+	if got := incident.CriticalityFor(cache); got != CriticalityDegraded {
+		t.Errorf("CriticalityFor(cache) = %v, want the incident criticality %v", got, CriticalityDegraded)
+	}
+	if got := incident.Criticality(); got != CriticalityMajorOutage {
+		t.Errorf("Criticality() = %v, want the highest impact %v", got, CriticalityMajorOutage)
+	}
+
+	// Lower impacts on every component lower the incident as a whole
+	incident.IncidentCriticality = CriticalityMajorOutage
+	incident.ComponentCriticalities = map[string]Criticality{"api": CriticalityPartialOutage, "cache": CriticalityDegraded}
+	if got := incident.Criticality(); got != CriticalityPartialOutage {
+		t.Errorf("Criticality() = %v, want %v", got, CriticalityPartialOutage)
+	}
+}
+
 func TestIncident_InterfaceCompliance(t *testing.T) {
 	incident := &Incident{
 		BaseEvent: BaseEvent{
@@ -0,0 +1,226 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidLabel is returned when a label key or value does not follow the Kubernetes label syntax
+	ErrInvalidLabel = errors.New("invalid label")
+	// ErrInvalidSelector is returned when a label selector cannot be parsed
+	ErrInvalidSelector = errors.New("invalid label selector")
+)
+
+const (
+	maxLabelNameLength   = 63
+	maxLabelPrefixLength = 253
+)
+
+var (
+	labelNamePattern   = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	labelPrefixPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// ValidateLabelKey checks a key such as "team" or "example.com/team": an optional DNS subdomain prefix
+// and a name of at most 63 alphanumeric characters, '-', '_' or '.'
+func ValidateLabelKey(key string) error {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) > maxLabelPrefixLength || !labelPrefixPattern.MatchString(prefix) {
+			return fmt.Errorf("%w: key %q must have a DNS subdomain as prefix", ErrInvalidLabel, key)
+		}
+	}
+	if len(name) > maxLabelNameLength || !labelNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: key %q must be at most 63 alphanumeric characters, '-', '_' or '.'", ErrInvalidLabel, key)
+	}
+	return nil
+}
+
+// ValidateLabelValue checks a value: empty, or at most 63 alphanumeric characters, '-', '_' or '.'
+func ValidateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxLabelNameLength || !labelNamePattern.MatchString(value) {
+		return fmt.Errorf("%w: value %q must be at most 63 alphanumeric characters, '-', '_' or '.'", ErrInvalidLabel, value)
+	}
+	return nil
+}
+
+// SelectorOperator is the comparison a selector requirement applies to a label
+type SelectorOperator string
+
+const (
+	SelectorEquals       SelectorOperator = "="
+	SelectorNotEquals    SelectorOperator = "!="
+	SelectorIn           SelectorOperator = "in"
+	SelectorNotIn        SelectorOperator = "notin"
+	SelectorExists       SelectorOperator = "exists"
+	SelectorDoesNotExist SelectorOperator = "!"
+)
+
+// Requirement is one comma separated term of a selector
+type Requirement struct {
+	Key      string           `json:"key"`
+	Operator SelectorOperator `json:"operator"`
+	Values   []string         `json:"values,omitempty"` // One value for = and !=, sorted for in and notin
+}
+
+// Selector selects the events whose labels satisfy every requirement, an empty selector selects everything
+type Selector []Requirement
+
+// ParseSelector parses a Kubernetes label selector such as "team=payments,source in (alertmanager,grafana),!draft".
+// Supported terms are key, !key, key=value, key==value, key!=value, key in (values) and key notin (values);
+// != and notin also select the events without the label.
+func ParseSelector(selector string) (Selector, error) {
+	terms, err := splitSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	var parsed Selector
+	for _, term := range terms {
+		requirement, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, requirement)
+	}
+	return parsed, nil
+}
+
+// splitSelector splits a selector on the commas outside of value sets
+func splitSelector(selector string) ([]string, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, nil
+	}
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, fmt.Errorf("%w: nested parentheses in %q", ErrInvalidSelector, selector)
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%w: unbalanced parentheses in %q", ErrInvalidSelector, selector)
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, strings.TrimSpace(selector[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: unbalanced parentheses in %q", ErrInvalidSelector, selector)
+	}
+	return append(terms, strings.TrimSpace(selector[start:])), nil
+}
+
+// parseRequirement parses a single term of a selector
+func parseRequirement(term string) (Requirement, error) {
+	var requirement Requirement
+	switch {
+	case term == "":
+		return requirement, fmt.Errorf("%w: empty requirement", ErrInvalidSelector)
+	case strings.HasPrefix(term, "!") && !strings.ContainsAny(term, "=()"):
+		requirement = Requirement{Key: strings.TrimSpace(term[1:]), Operator: SelectorDoesNotExist}
+	case strings.Contains(term, "!="):
+		key, value, _ := strings.Cut(term, "!=")
+		requirement = Requirement{Key: strings.TrimSpace(key), Operator: SelectorNotEquals, Values: []string{strings.TrimSpace(value)}}
+	case strings.Contains(term, "=="):
+		key, value, _ := strings.Cut(term, "==")
+		requirement = Requirement{Key: strings.TrimSpace(key), Operator: SelectorEquals, Values: []string{strings.TrimSpace(value)}}
+	case strings.Contains(term, "="):
+		key, value, _ := strings.Cut(term, "=")
+		requirement = Requirement{Key: strings.TrimSpace(key), Operator: SelectorEquals, Values: []string{strings.TrimSpace(value)}}
+	case strings.Contains(term, "("):
+		fields := strings.Fields(term[:strings.Index(term, "(")])
+		if len(fields) != 2 || (fields[1] != string(SelectorIn) && fields[1] != string(SelectorNotIn)) || !strings.HasSuffix(term, ")") {
+			return requirement, fmt.Errorf("%w: %q must be 'key in (values)' or 'key notin (values)'", ErrInvalidSelector, term)
+		}
+		requirement = Requirement{Key: fields[0], Operator: SelectorOperator(fields[1])}
+		for _, value := range strings.Split(term[strings.Index(term, "(")+1:len(term)-1], ",") {
+			requirement.Values = append(requirement.Values, strings.TrimSpace(value))
+		}
+		sort.Strings(requirement.Values)
+	default:
+		requirement = Requirement{Key: term, Operator: SelectorExists}
+	}
+
+	if err := ValidateLabelKey(requirement.Key); err != nil {
+		return requirement, fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+	}
+	for _, value := range requirement.Values {
+		if err := ValidateLabelValue(value); err != nil {
+			return requirement, fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+		}
+	}
+	return requirement, nil
+}
+
+// Matches reports whether the labels satisfy the requirement
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case SelectorEquals:
+		return ok && value == r.Values[0]
+	case SelectorNotEquals:
+		return !ok || value != r.Values[0]
+	case SelectorIn:
+		return ok && containsLabelValue(r.Values, value)
+	case SelectorNotIn:
+		return !ok || !containsLabelValue(r.Values, value)
+	case SelectorExists:
+		return ok
+	case SelectorDoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// Matches reports whether the labels satisfy every requirement of the selector
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in its normalized syntax
+func (s Selector) String() string {
+	terms := make([]string, len(s))
+	for i, r := range s {
+		switch r.Operator {
+		case SelectorExists:
+			terms[i] = r.Key
+		case SelectorDoesNotExist:
+			terms[i] = "!" + r.Key
+		case SelectorIn, SelectorNotIn:
+			terms[i] = fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+		default:
+			terms[i] = r.Key + string(r.Operator) + r.Values[0]
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+func containsLabelValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package event
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateLabel(t *testing.T) {
+	for _, key := range []string{"team", "customer-impact", "example.com/source", "a"} {
+		if err := ValidateLabelKey(key); err != nil {
+			t.Errorf("ValidateLabelKey(%q) error = %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "team payments", "-team", "Example.com/team", "example.com/"} {
+		if err := ValidateLabelKey(key); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("ValidateLabelKey(%q) error = %v, want ErrInvalidLabel", key, err)
+		}
+	}
+
+	for _, value := range []string{"", "payments", "v1.2_3"} {
+		if err := ValidateLabelValue(value); err != nil {
+			t.Errorf("ValidateLabelValue(%q) error = %v", value, err)
+		}
+	}
+	for _, value := range []string{"payments!", "-payments", strings.Repeat("a", 64)} {
+		if err := ValidateLabelValue(value); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("ValidateLabelValue(%q) error = %v, want ErrInvalidLabel", value, err)
+		}
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{selector: "", want: ""},
+		{selector: "team=payments", want: "team=payments"},
+		{selector: "team == payments, source!=grafana", want: "team=payments,source!=grafana"},
+		{selector: "source in (grafana, alertmanager),team notin (ops)", want: "source in (alertmanager,grafana),team notin (ops)"},
+		{selector: "customer-impact,!draft", want: "customer-impact,!draft"},
+		{selector: "example.com/team=payments", want: "example.com/team=payments"},
+		{selector: "team=", want: "team="},
+		{selector: "team=payments,", wantErr: true},
+		{selector: "source in (grafana", wantErr: true},
+		{selector: "source within (grafana)", wantErr: true},
+		{selector: "team=pay ments", wantErr: true},
+		{selector: "=payments", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, err := ParseSelector(tt.selector)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSelector) {
+					t.Fatalf("ParseSelector() error = %v, want ErrInvalidSelector", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSelector() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("String() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	labels := map[string]string{"team": "payments", "source": "alertmanager"}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{selector: "", want: true},
+		{selector: "team=payments", want: true},
+		{selector: "team=search", want: false},
+		{selector: "team!=search", want: true},
+		{selector: "region!=eu", want: true},
+		{selector: "source in (alertmanager,grafana)", want: true},
+		{selector: "source notin (alertmanager)", want: false},
+		{selector: "region notin (eu)", want: true},
+		{selector: "region in (eu)", want: false},
+		{selector: "team", want: true},
+		{selector: "!team", want: false},
+		{selector: "!region", want: true},
+		{selector: "team=payments,source=grafana", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseSelector() error = %v", err)
+			}
+			if got := selector.Matches(labels); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lifecycle statuses an incident goes through until it is resolved
+const (
+	StatusInvestigating Status = "investigating"
+	StatusIdentified    Status = "identified"
+	StatusMonitoring    Status = "monitoring"
+)
+
+// ErrInvalidTransition is returned when an incident cannot move to the requested lifecycle status
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// StatusTransition records an incident moving to a lifecycle status
+type StatusTransition struct {
+	From    Status    `json:"from,omitempty"` // Empty for the transition opening the lifecycle
+	To      Status    `json:"to"`
+	At      time.Time `json:"at"`
+	Message string    `json:"message"` // Public message shown with the transition
+	Author  string    `json:"author,omitempty"`
+}
+
+// incidentTransitions lists the lifecycle statuses each one can move to.
+// The empty status is the one of incidents which never entered the lifecycle.
+var incidentTransitions = map[Status][]Status{
+	"":                  {StatusInvestigating, StatusIdentified, StatusMonitoring, StatusResolved},
+	StatusInvestigating: {StatusIdentified, StatusMonitoring, StatusResolved},
+	StatusIdentified:    {StatusInvestigating, StatusMonitoring, StatusResolved},
+	StatusMonitoring:    {StatusInvestigating, StatusIdentified, StatusResolved},
+	StatusResolved:      {StatusInvestigating},
+}
+
+// LifecycleStatuses returns the lifecycle statuses of incidents, in their usual order
+func LifecycleStatuses() []Status {
+	return []Status{StatusInvestigating, StatusIdentified, StatusMonitoring, StatusResolved}
+}
+
+// NextStatuses returns the lifecycle statuses an incident in status from can move to
+func NextStatuses(from Status) []Status {
+	return append([]Status(nil), incidentTransitions[from]...)
+}
+
+// CanTransition reports whether an incident in lifecycle status from can move to status to
+func CanTransition(from, to Status) bool {
+	for _, next := range incidentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether the incident is still being worked on according to its lifecycle.
+// Incidents outside the lifecycle are open until their effective end.
+func (i *Incident) IsOpen() bool {
+	switch i.LifecycleStatus {
+	case StatusInvestigating, StatusIdentified, StatusMonitoring:
+		return true
+	case StatusResolved:
+		return false
+	}
+	return i.EndEffective == nil || i.EndEffective.After(time.Now())
+}
+
+// Acknowledge records that author took charge of the open incident at the given time
+func (i *Incident) Acknowledge(author string, at time.Time) error {
+	if !i.IsOpen() {
+		return fmt.Errorf("%w: a resolved incident cannot be acknowledged", ErrInvalidTransition)
+	}
+	if i.AcknowledgedAt != nil {
+		return fmt.Errorf("%w: already acknowledged by %s", ErrInvalidTransition, i.AcknowledgedBy)
+	}
+	i.AcknowledgedAt, i.AcknowledgedBy = &at, author
+	return nil
+}
+
+// Reopen moves a resolved incident back to investigating, the acknowledgement is cleared
+func (i *Incident) Reopen(message, author string, at time.Time) error {
+	if i.IsOpen() {
+		return fmt.Errorf("%w: only a resolved incident can be reopened", ErrInvalidTransition)
+	}
+	return i.Transition(StatusInvestigating, message, author, at)
+}
+
+// Transition moves the incident to a lifecycle status at the given time and records it in its transitions.
+// The first transition sets the effective start when missing, resolving sets the effective end and reopening
+// a resolved incident clears it along with the acknowledgement.
+// Incidents outside the lifecycle which already ended count as resolved.
+func (i *Incident) Transition(to Status, message, author string, at time.Time) error {
+	from := i.LifecycleStatus
+	if from == "" && i.EndEffective != nil && !i.EndEffective.After(at) {
+		from = StatusResolved
+	}
+	if !CanTransition(from, to) {
+		if from == "" {
+			return fmt.Errorf("%w to %s", ErrInvalidTransition, to)
+		}
+		return fmt.Errorf("%w from %s to %s", ErrInvalidTransition, from, to)
+	}
+
+	if to == StatusResolved {
+		i.EndEffective = &at
+	} else {
+		if i.StartEffective == nil {
+			i.StartEffective = &at
+		}
+		if from == StatusResolved {
+			i.EndEffective = nil
+			i.AcknowledgedAt, i.AcknowledgedBy = nil, ""
+		}
+	}
+	i.LifecycleStatus = to
+	// The transitions may be shared with a stored copy of the incident, never append in place
+	i.Transitions = append(i.Transitions[:len(i.Transitions):len(i.Transitions)], StatusTransition{
+		From:    from,
+		To:      to,
+		At:      at,
+		Message: message,
+		Author:  author,
+	})
+	return nil
+}
@@ -0,0 +1,159 @@
+package event
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		expected bool
+	}{
+		{"", StatusInvestigating, true},
+		{"", StatusResolved, true},
+		{StatusInvestigating, StatusIdentified, true},
+		{StatusInvestigating, StatusInvestigating, false},
+		{StatusIdentified, StatusMonitoring, true},
+		{StatusMonitoring, StatusInvestigating, true},
+		{StatusMonitoring, StatusResolved, true},
+		{StatusResolved, StatusInvestigating, true},
+		{StatusResolved, StatusMonitoring, false},
+		{StatusInvestigating, StatusOnGoing, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.expected {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.expected)
+		}
+	}
+}
+
+func TestIncident_Transition(t *testing.T) {
+	incident := NewFiringIncident("API outage", "Requests time out", nil, CriticalityMajorOutage)
+	opened := time.Now().Add(-time.Hour)
+
+	if err := incident.Transition(StatusInvestigating, "We are looking into it", "alice", opened); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if incident.Status() != StatusInvestigating || !incident.IsOpen() {
+		t.Errorf("Expected an open investigating incident, got %s", incident.Status())
+	}
+	if incident.StartEffective == nil || !incident.StartEffective.Equal(opened) {
+		t.Errorf("Expected the first transition to set the effective start")
+	}
+
+	resolved := opened.Add(30 * time.Minute)
+	if err := incident.Transition(StatusResolved, "Fixed", "bob", resolved); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if incident.IsOpen() || incident.EndEffective == nil || !incident.EndEffective.Equal(resolved) {
+		t.Errorf("Expected resolving to set the effective end")
+	}
+
+	err := incident.Transition(StatusMonitoring, "Watching", "bob", resolved)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition from resolved to monitoring, got %v", err)
+	}
+
+	if err := incident.Transition(StatusInvestigating, "It is back", "alice", resolved.Add(time.Minute)); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if incident.EndEffective != nil || !incident.StartEffective.Equal(opened) {
+		t.Errorf("Expected reopening to clear the effective end and keep the start")
+	}
+
+	if len(incident.Transitions) != 3 {
+		t.Fatalf("Expected 3 transitions, got %d", len(incident.Transitions))
+	}
+	last := incident.Transitions[2]
+	if last.From != StatusResolved || last.To != StatusInvestigating || last.Message != "It is back" || last.Author != "alice" {
+		t.Errorf("Unexpected last transition %+v", last)
+	}
+	if first := incident.Transitions[0]; first.From != "" {
+		t.Errorf("Expected the first transition to open the lifecycle, got from %q", first.From)
+	}
+}
+
+func TestIncident_TransitionOutsideLifecycle(t *testing.T) {
+	// Incidents created before the lifecycle count as resolved once ended
+	start, end := time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)
+	incident := NewFiringIncident("Old outage", "Requests time out", nil, CriticalityDegraded)
+	incident.StartEffective, incident.EndEffective = &start, &end
+
+	if incident.Status() != StatusResolved {
+		t.Fatalf("Expected the timing status, got %s", incident.Status())
+	}
+	if err := incident.Transition(StatusMonitoring, "Watching", "alice", time.Now()); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected an ended incident to only reopen, got %v", err)
+	}
+	if err := incident.Transition(StatusInvestigating, "It is back", "alice", time.Now()); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if incident.Transitions[0].From != StatusResolved || incident.EndEffective != nil {
+		t.Errorf("Expected the ended incident reopened, got %+v", incident.Transitions[0])
+	}
+}
+
+func TestIncident_TransitionKeepsSharedTimeline(t *testing.T) {
+	incident := NewFiringIncident("API outage", "Requests time out", nil, CriticalityMajorOutage)
+	if err := incident.Transition(StatusInvestigating, "Looking", "alice", time.Now()); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	incident.Transitions = append(make([]StatusTransition, 0, 4), incident.Transitions...)
+
+	stored := *incident
+	if err := incident.Transition(StatusIdentified, "Found", "alice", time.Now()); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	copied := stored
+	if err := copied.Transition(StatusMonitoring, "Watching", "bob", time.Now()); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if incident.Transitions[1].To != StatusIdentified {
+		t.Errorf("Expected copies of an incident not to overwrite each other's timeline")
+	}
+}
+
+func TestIncident_AcknowledgeAndReopen(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	incident := NewFiringIncident("Outage", "API is down", nil, CriticalityMajorOutage)
+	if err := incident.Transition(StatusInvestigating, "Looking into it", "alice", start); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+
+	if err := incident.Reopen("Again", "alice", start); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected an open incident not to be reopened, got %v", err)
+	}
+	if err := incident.Acknowledge("bob", start.Add(time.Minute)); err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+	if incident.Status() != StatusAcknowledged || incident.AcknowledgedBy != "bob" {
+		t.Errorf("Expected acknowledged by bob, got %s by %s", incident.Status(), incident.AcknowledgedBy)
+	}
+	if err := incident.Acknowledge("carol", start.Add(time.Minute)); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected a second acknowledgement to fail, got %v", err)
+	}
+
+	// The acknowledgement only shows while the cause is investigated
+	if err := incident.Transition(StatusIdentified, "Found it", "bob", start.Add(time.Hour)); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if incident.Status() != StatusIdentified {
+		t.Errorf("Expected identified, got %s", incident.Status())
+	}
+
+	if err := incident.Transition(StatusResolved, "Fixed", "bob", start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if err := incident.Acknowledge("carol", start.Add(3*time.Hour)); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected a resolved incident not to be acknowledged, got %v", err)
+	}
+	if err := incident.Reopen("It is back", "carol", start.Add(3*time.Hour)); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if incident.Status() != StatusInvestigating || incident.AcknowledgedAt != nil || incident.EndEffective != nil {
+		t.Errorf("Expected a reopened incident to be investigated without acknowledgement, got %s acknowledged at %v", incident.Status(), incident.AcknowledgedAt)
+	}
+}
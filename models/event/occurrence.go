@@ -0,0 +1,216 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrOccurrenceNotFound is returned when a recurrence ID does not match an occurrence of the series
+var ErrOccurrenceNotFound = errors.New("occurrence not found")
+
+// OccurrenceOverride changes a single occurrence of a recurring maintenance
+type OccurrenceOverride struct {
+	RecurrenceID time.Time  `json:"recurrence_id"` // Start of the occurrence as generated by the rule
+	Cancelled    bool       `json:"cancelled,omitempty"`
+	StartPlanned *time.Time `json:"start_planned,omitempty"`
+	EndPlanned   *time.Time `json:"end_planned,omitempty"`
+}
+
+// Occurrence is one window of a recurring maintenance, with its override applied
+type Occurrence struct {
+	RecurrenceID time.Time `json:"recurrence_id"`
+	StartPlanned time.Time `json:"start_planned"`
+	EndPlanned   time.Time `json:"end_planned"`
+	Cancelled    bool      `json:"cancelled,omitempty"`
+	Overridden   bool      `json:"overridden,omitempty"`
+}
+
+// IsRecurring reports whether the maintenance is a series
+func (pm *PlannedMaintenance) IsRecurring() bool {
+	return pm.Recurrence != ""
+}
+
+// eachOccurrence calls fn with every occurrence of the series in the order of the rule, until fn returns false
+func (pm *PlannedMaintenance) eachOccurrence(fn func(Occurrence) bool) error {
+	rule, err := ParseRecurrence(pm.Recurrence)
+	if err != nil {
+		return err
+	}
+	duration := pm.EndPlanned.Sub(pm.StartPlanned)
+	rule.each(pm.StartPlanned, func(start time.Time) bool {
+		occurrence := Occurrence{RecurrenceID: start, StartPlanned: start, EndPlanned: start.Add(duration)}
+		if override := pm.override(start); override != nil {
+			occurrence.apply(*override, duration)
+		}
+		return fn(occurrence)
+	})
+	return nil
+}
+
+// apply changes the occurrence as the override does, a moved start keeping the duration unless the end moves too
+func (o *Occurrence) apply(override OccurrenceOverride, duration time.Duration) {
+	o.Overridden = true
+	o.Cancelled = override.Cancelled
+	if override.StartPlanned != nil {
+		o.StartPlanned = *override.StartPlanned
+		o.EndPlanned = override.StartPlanned.Add(duration)
+	}
+	if override.EndPlanned != nil {
+		o.EndPlanned = *override.EndPlanned
+	}
+}
+
+// override returns the override of the occurrence generated at recurrenceID, if any
+func (pm *PlannedMaintenance) override(recurrenceID time.Time) *OccurrenceOverride {
+	for i := range pm.Overrides {
+		if pm.Overrides[i].RecurrenceID.Equal(recurrenceID) {
+			return &pm.Overrides[i]
+		}
+	}
+	return nil
+}
+
+// horizon returns the later of t and the last overridden occurrence: past it, no occurrence is moved or cancelled
+func (pm *PlannedMaintenance) horizon(t time.Time) time.Time {
+	for _, override := range pm.Overrides {
+		if override.RecurrenceID.After(t) {
+			t = override.RecurrenceID
+		}
+	}
+	return t
+}
+
+// Occurrences returns the occurrences overlapping the from to to window, cancelled ones included, sorted by start.
+// A maintenance which does not recur has a single occurrence.
+func (pm *PlannedMaintenance) Occurrences(from, to time.Time) ([]Occurrence, error) {
+	occurrences := []Occurrence{}
+	if !pm.IsRecurring() {
+		if pm.EndPlanned.After(from) && pm.StartPlanned.Before(to) {
+			occurrences = append(occurrences, Occurrence{RecurrenceID: pm.StartPlanned, StartPlanned: pm.StartPlanned, EndPlanned: pm.EndPlanned, Cancelled: pm.Cancelled})
+		}
+		return occurrences, nil
+	}
+
+	horizon := pm.horizon(to)
+	err := pm.eachOccurrence(func(occurrence Occurrence) bool {
+		if occurrence.RecurrenceID.After(horizon) {
+			return false
+		}
+		if occurrence.EndPlanned.After(from) && occurrence.StartPlanned.Before(to) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartPlanned.Before(occurrences[j].StartPlanned)
+	})
+	return occurrences, nil
+}
+
+// seriesStatus returns ongoing during an occurrence, planned while one is to come and resolved once the series is over
+func (pm *PlannedMaintenance) seriesStatus(now time.Time) Status {
+	horizon := pm.horizon(now)
+	status := StatusResolved
+	err := pm.eachOccurrence(func(occurrence Occurrence) bool {
+		if !occurrence.Cancelled {
+			if !now.Before(occurrence.StartPlanned) && now.Before(occurrence.EndPlanned) {
+				status = StatusOnGoing
+				return false
+			}
+			if occurrence.StartPlanned.After(now) {
+				status = StatusPlanned
+			}
+		}
+		// Occurrences past the horizon are neither moved nor cancelled, the first one settles the status
+		return !occurrence.RecurrenceID.After(horizon)
+	})
+	if err != nil {
+		return StatusUnknown
+	}
+	return status
+}
+
+// SeriesEnd returns the end of the last occurrence, or nil when the series does not end
+func (pm *PlannedMaintenance) SeriesEnd() *time.Time {
+	if !pm.IsRecurring() {
+		return &pm.EndPlanned
+	}
+	rule, err := ParseRecurrence(pm.Recurrence)
+	if err != nil || !rule.IsBounded() {
+		return nil
+	}
+
+	end := pm.EndPlanned
+	_ = pm.eachOccurrence(func(occurrence Occurrence) bool {
+		if occurrence.EndPlanned.After(end) {
+			end = occurrence.EndPlanned
+		}
+		return true
+	})
+	return &end
+}
+
+// SetOverride cancels or moves the occurrence generated at override.RecurrenceID, replacing its previous override
+func (pm *PlannedMaintenance) SetOverride(override OccurrenceOverride) error {
+	if !pm.IsRecurring() {
+		return fmt.Errorf("%w: the maintenance is not recurring", ErrInvalidTransition)
+	}
+	if !pm.hasOccurrence(override.RecurrenceID) {
+		return fmt.Errorf("%w: %s", ErrOccurrenceNotFound, override.RecurrenceID.Format(time.RFC3339))
+	}
+	// Check the window the occurrence ends up with, an end alone may precede the generated start
+	duration := pm.EndPlanned.Sub(pm.StartPlanned)
+	occurrence := Occurrence{StartPlanned: override.RecurrenceID, EndPlanned: override.RecurrenceID.Add(duration)}
+	occurrence.apply(override, duration)
+	if !occurrence.EndPlanned.After(occurrence.StartPlanned) {
+		return fmt.Errorf("%w: the occurrence must end after it starts", ErrInvalidTransition)
+	}
+
+	// Copy the overrides rather than writing through, the slice may be shared with a stored revision
+	overrides := make([]OccurrenceOverride, 0, len(pm.Overrides)+1)
+	for _, existing := range pm.Overrides {
+		if !existing.RecurrenceID.Equal(override.RecurrenceID) {
+			overrides = append(overrides, existing)
+		}
+	}
+	pm.Overrides = append(overrides, override)
+	return nil
+}
+
+// RemoveOverride restores the occurrence generated at recurrenceID, reporting whether it was overridden
+func (pm *PlannedMaintenance) RemoveOverride(recurrenceID time.Time) bool {
+	for i, override := range pm.Overrides {
+		if override.RecurrenceID.Equal(recurrenceID) {
+			overrides := make([]OccurrenceOverride, 0, len(pm.Overrides)-1)
+			pm.Overrides = append(append(overrides, pm.Overrides[:i]...), pm.Overrides[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// PruneOverrides drops the overrides that no longer match an occurrence, after the schedule changed
+func (pm *PlannedMaintenance) PruneOverrides() {
+	var kept []OccurrenceOverride
+	for _, override := range pm.Overrides {
+		if pm.hasOccurrence(override.RecurrenceID) {
+			kept = append(kept, override)
+		}
+	}
+	pm.Overrides = kept
+}
+
+// hasOccurrence reports whether the rule generates an occurrence starting at recurrenceID
+func (pm *PlannedMaintenance) hasOccurrence(recurrenceID time.Time) bool {
+	found := false
+	_ = pm.eachOccurrence(func(occurrence Occurrence) bool {
+		found = occurrence.RecurrenceID.Equal(recurrenceID)
+		return !found && occurrence.RecurrenceID.Before(recurrenceID)
+	})
+	return found
+}
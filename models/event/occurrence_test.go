@@ -0,0 +1,127 @@
+package event
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newWeeklyMaintenance(start time.Time, rule string) *PlannedMaintenance {
+	pm := NewPlannedMaintenance("Weekly patching", "Rolling reboot", nil, start, start.Add(2*time.Hour))
+	pm.Recurrence = rule
+	return pm
+}
+
+func TestPlannedMaintenance_Occurrences(t *testing.T) {
+	start := time.Date(2026, 1, 13, 22, 0, 0, 0, time.UTC)
+	pm := newWeeklyMaintenance(start, "FREQ=WEEKLY;COUNT=4")
+
+	second := start.AddDate(0, 0, 7)
+	third := start.AddDate(0, 0, 14)
+	moved := third.Add(24 * time.Hour)
+	if err := pm.SetOverride(OccurrenceOverride{RecurrenceID: second, Cancelled: true}); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if err := pm.SetOverride(OccurrenceOverride{RecurrenceID: third, StartPlanned: &moved}); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+
+	occurrences, err := pm.Occurrences(start, start.AddDate(1, 0, 0))
+	if err != nil {
+		t.Fatalf("Occurrences() error = %v", err)
+	}
+	if len(occurrences) != 4 {
+		t.Fatalf("Occurrences() returned %d occurrences, want 4", len(occurrences))
+	}
+	if !occurrences[1].Cancelled || !occurrences[1].Overridden {
+		t.Errorf("second occurrence = %+v, want it cancelled", occurrences[1])
+	}
+	if !occurrences[2].StartPlanned.Equal(moved) || !occurrences[2].EndPlanned.Equal(moved.Add(2*time.Hour)) {
+		t.Errorf("third occurrence = %+v, want it moved to %v keeping its duration", occurrences[2], moved)
+	}
+	if !occurrences[2].RecurrenceID.Equal(third) {
+		t.Errorf("third occurrence recurrence ID = %v, want %v", occurrences[2].RecurrenceID, third)
+	}
+
+	if end := pm.SeriesEnd(); end == nil || !end.Equal(start.AddDate(0, 0, 21).Add(2*time.Hour)) {
+		t.Errorf("SeriesEnd() = %v, want the end of the fourth occurrence", end)
+	}
+
+	// Only occurrences of the rule can be overridden
+	err = pm.SetOverride(OccurrenceOverride{RecurrenceID: start.Add(time.Hour), Cancelled: true})
+	if !errors.Is(err, ErrOccurrenceNotFound) {
+		t.Errorf("SetOverride() error = %v, want ErrOccurrenceNotFound", err)
+	}
+
+	// The overridden window must not be inverted, whether the end moves alone or with the start
+	early := second.Add(-time.Hour)
+	for _, override := range []OccurrenceOverride{
+		{RecurrenceID: second, EndPlanned: &early},
+		{RecurrenceID: third, StartPlanned: &moved, EndPlanned: &third},
+	} {
+		if err := pm.SetOverride(override); !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("SetOverride(%+v) error = %v, want ErrInvalidTransition", override, err)
+		}
+	}
+	late := second.Add(3 * time.Hour)
+	if err := pm.SetOverride(OccurrenceOverride{RecurrenceID: second, Cancelled: true, EndPlanned: &late}); err != nil {
+		t.Errorf("SetOverride() error = %v for a later end", err)
+	}
+
+	if !pm.RemoveOverride(second) || pm.RemoveOverride(second) {
+		t.Error("RemoveOverride() should remove the override once")
+	}
+	if len(pm.Overrides) != 1 {
+		t.Errorf("Overrides = %d, want 1", len(pm.Overrides))
+	}
+
+	// Overrides that no longer match the rule are dropped
+	pm.Recurrence = "FREQ=WEEKLY;COUNT=2"
+	pm.PruneOverrides()
+	if len(pm.Overrides) != 0 {
+		t.Errorf("Overrides after PruneOverrides() = %d, want 0", len(pm.Overrides))
+	}
+}
+
+func TestPlannedMaintenance_SeriesStatus(t *testing.T) {
+	now := time.Now()
+
+	seriesStart := now.AddDate(0, 0, -14).Add(-time.Hour)
+	ongoing := newWeeklyMaintenance(seriesStart, "FREQ=WEEKLY")
+	if got := ongoing.Status(); got != StatusOnGoing {
+		t.Errorf("Status() during an occurrence = %v, want %v", got, StatusOnGoing)
+	}
+	if ongoing.SeriesEnd() != nil {
+		t.Error("SeriesEnd() of an unbounded series should be nil")
+	}
+
+	// Cancelling the current occurrence leaves the next one planned
+	current := seriesStart.AddDate(0, 0, 14)
+	if err := ongoing.SetOverride(OccurrenceOverride{RecurrenceID: current, Cancelled: true}); err != nil {
+		t.Fatalf("SetOverride() error = %v", err)
+	}
+	if got := ongoing.Status(); got != StatusPlanned {
+		t.Errorf("Status() with the current occurrence cancelled = %v, want %v", got, StatusPlanned)
+	}
+
+	over := newWeeklyMaintenance(now.AddDate(0, 0, -30), "FREQ=WEEKLY;COUNT=2")
+	if got := over.Status(); got != StatusResolved {
+		t.Errorf("Status() of a finished series = %v, want %v", got, StatusResolved)
+	}
+
+	if err := ongoing.Cancel("alice", now); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if got := ongoing.Status(); got != StatusCanceled {
+		t.Errorf("Status() of a cancelled series = %v, want %v", got, StatusCanceled)
+	}
+
+	invalid := newWeeklyMaintenance(now, "FREQ=HOURLY")
+	if got := invalid.Status(); got != StatusUnknown {
+		t.Errorf("Status() with an invalid rule = %v, want %v", got, StatusUnknown)
+	}
+
+	if err := over.Start("alice", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Start() on a series error = %v, want ErrInvalidTransition", err)
+	}
+}
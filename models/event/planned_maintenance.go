@@ -1,6 +1,7 @@
 package event
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gmllt/clariti/models/component"
@@ -12,6 +13,21 @@ type PlannedMaintenance struct {
 	StartPlanned time.Time `json:"start_planned"`
 	EndPlanned   time.Time `json:"end_planned"`
 	Cancelled    bool      `json:"cancelled,omitempty"`
+
+	// Actors of the start, complete and cancel actions, the effective times and CancelledAt record when
+	StartedBy   string     `json:"started_by,omitempty"`
+	CompletedBy string     `json:"completed_by,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	CancelledBy string     `json:"cancelled_by,omitempty"`
+
+	// Opt-in automation: the scheduler starts and completes the maintenance at its planned boundaries
+	AutoStart    bool       `json:"auto_start,omitempty"`
+	AutoComplete bool       `json:"auto_complete,omitempty"`
+	OverrunAt    *time.Time `json:"overrun_at,omitempty"` // When the maintenance was found running past its planned end
+
+	// Recurrence makes the maintenance a series: an RRULE repeating the StartPlanned to EndPlanned window
+	Recurrence string               `json:"recurrence,omitempty"`
+	Overrides  []OccurrenceOverride `json:"overrides,omitempty"`
 }
 
 // Type returns the event type for planned maintenance
@@ -24,6 +40,9 @@ func (pm *PlannedMaintenance) Status() Status {
 	if pm.Cancelled {
 		return StatusCanceled
 	}
+	if pm.IsRecurring() {
+		return pm.seriesStatus(time.Now())
+	}
 	if pm.EndEffective != nil && pm.EndEffective.Before(time.Now()) {
 		return StatusResolved
 	}
@@ -36,6 +55,89 @@ func (pm *PlannedMaintenance) Status() Status {
 	return StatusUnknown
 }
 
+// Start records that author started the maintenance at the given time
+func (pm *PlannedMaintenance) Start(author string, at time.Time) error {
+	switch {
+	case pm.Cancelled:
+		return fmt.Errorf("%w: the maintenance is cancelled", ErrInvalidTransition)
+	case pm.IsRecurring():
+		return fmt.Errorf("%w: the occurrences of a recurring maintenance follow their schedule", ErrInvalidTransition)
+	case pm.StartEffective != nil:
+		return fmt.Errorf("%w: the maintenance already started", ErrInvalidTransition)
+	}
+	pm.StartEffective, pm.StartedBy = &at, author
+	return nil
+}
+
+// Complete records that author completed the started maintenance at the given time
+func (pm *PlannedMaintenance) Complete(author string, at time.Time) error {
+	switch {
+	case pm.Cancelled:
+		return fmt.Errorf("%w: the maintenance is cancelled", ErrInvalidTransition)
+	case pm.IsRecurring():
+		return fmt.Errorf("%w: the occurrences of a recurring maintenance follow their schedule", ErrInvalidTransition)
+	case pm.StartEffective == nil:
+		return fmt.Errorf("%w: the maintenance did not start", ErrInvalidTransition)
+	case pm.EndEffective != nil:
+		return fmt.Errorf("%w: the maintenance is already completed", ErrInvalidTransition)
+	}
+	pm.EndEffective, pm.CompletedBy = &at, author
+	return nil
+}
+
+// Cancel records that author cancelled the maintenance at the given time, a started maintenance must be completed instead.
+// Cancelling a recurring maintenance cancels the whole series, see SetOverride to cancel a single occurrence.
+func (pm *PlannedMaintenance) Cancel(author string, at time.Time) error {
+	switch {
+	case pm.Cancelled:
+		return fmt.Errorf("%w: the maintenance is already cancelled", ErrInvalidTransition)
+	case pm.StartEffective != nil:
+		return fmt.Errorf("%w: the maintenance already started, complete it instead", ErrInvalidTransition)
+	}
+	pm.Cancelled, pm.CancelledAt, pm.CancelledBy = true, &at, author
+	return nil
+}
+
+// ScheduleAction is an action applied by the scheduler on a maintenance
+type ScheduleAction string
+
+const (
+	ScheduleStart    ScheduleAction = "start"
+	ScheduleComplete ScheduleAction = "complete"
+	ScheduleOverrun  ScheduleAction = "overrun"
+)
+
+// IsOverrunning reports whether the maintenance started and is still running past its planned end at now
+func (pm *PlannedMaintenance) IsOverrunning(now time.Time) bool {
+	return !pm.Cancelled && !pm.IsRecurring() && pm.StartEffective != nil && pm.EndEffective == nil && now.After(pm.EndPlanned)
+}
+
+// ApplySchedule applies the actions due at now on behalf of author and returns them in order. An opted-in
+// maintenance is started at StartPlanned and completed at EndPlanned, unless it was started by hand after
+// EndPlanned. A maintenance still running past EndPlanned is flagged once with OverrunAt.
+func (pm *PlannedMaintenance) ApplySchedule(author string, now time.Time) []ScheduleAction {
+	if pm.Cancelled || pm.IsRecurring() {
+		return nil
+	}
+
+	var actions []ScheduleAction
+	if pm.AutoStart && pm.StartEffective == nil && !now.Before(pm.StartPlanned) {
+		if pm.Start(author, pm.StartPlanned) == nil {
+			actions = append(actions, ScheduleStart)
+		}
+	}
+	if pm.AutoComplete && pm.StartEffective != nil && !pm.StartEffective.After(pm.EndPlanned) && !now.Before(pm.EndPlanned) {
+		if pm.Complete(author, pm.EndPlanned) == nil {
+			actions = append(actions, ScheduleComplete)
+		}
+	}
+	if pm.OverrunAt == nil && pm.IsOverrunning(now) {
+		pm.OverrunAt = &now
+		actions = append(actions, ScheduleOverrun)
+	}
+	return actions
+}
+
 // Criticality returns the criticality level for maintenance events
 func (pm *PlannedMaintenance) Criticality() Criticality {
 	return CriticalityUnderMaintenance
@@ -2,6 +2,7 @@ package event
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -676,3 +677,87 @@ func BenchmarkPlannedMaintenanceCreation_Concurrent(b *testing.B) {
 		}
 	})
 }
+
+func TestPlannedMaintenance_Actions(t *testing.T) {
+	now := time.Now()
+	pm := NewPlannedMaintenance("Upgrade", "Database upgrade", nil, now.Add(time.Hour), now.Add(2*time.Hour))
+
+	if err := pm.Complete("alice", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected a maintenance which did not start not to complete, got %v", err)
+	}
+	if err := pm.Start("alice", now.Add(-time.Minute)); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if pm.Status() != StatusOnGoing || pm.StartedBy != "alice" {
+		t.Errorf("Expected ongoing started by alice, got %s by %s", pm.Status(), pm.StartedBy)
+	}
+	if err := pm.Start("alice", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected a second start to fail, got %v", err)
+	}
+	if err := pm.Cancel("bob", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected a started maintenance not to be cancelled, got %v", err)
+	}
+	if err := pm.Complete("bob", now.Add(-time.Second)); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if pm.Status() != StatusResolved || pm.CompletedBy != "bob" {
+		t.Errorf("Expected resolved completed by bob, got %s by %s", pm.Status(), pm.CompletedBy)
+	}
+
+	cancelled := NewPlannedMaintenance("Migration", "Network migration", nil, now.Add(time.Hour), now.Add(2*time.Hour))
+	if err := cancelled.Cancel("carol", now); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if cancelled.Status() != StatusCanceled || cancelled.CancelledBy != "carol" || cancelled.CancelledAt == nil {
+		t.Errorf("Expected canceled by carol, got %s by %s", cancelled.Status(), cancelled.CancelledBy)
+	}
+	if err := cancelled.Start("carol", now); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected a cancelled maintenance not to start, got %v", err)
+	}
+}
+
+func TestPlannedMaintenance_ApplySchedule(t *testing.T) {
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	pm := NewPlannedMaintenance("DB upgrade", "PostgreSQL 16", nil, start, end)
+	if actions := pm.ApplySchedule("scheduler", end.Add(time.Hour)); len(actions) != 0 {
+		t.Errorf("ApplySchedule() without opt-in = %v, want no action", actions)
+	}
+
+	pm.AutoStart, pm.AutoComplete = true, true
+	if actions := pm.ApplySchedule("scheduler", start.Add(-time.Minute)); len(actions) != 0 {
+		t.Errorf("ApplySchedule() before the start = %v, want no action", actions)
+	}
+	// A scheduler which was down applies both actions at their planned times
+	actions := pm.ApplySchedule("scheduler", end.Add(time.Hour))
+	if len(actions) != 2 || actions[0] != ScheduleStart || actions[1] != ScheduleComplete {
+		t.Fatalf("ApplySchedule() = %v, want start then complete", actions)
+	}
+	if !pm.StartEffective.Equal(start) || !pm.EndEffective.Equal(end) || pm.StartedBy != "scheduler" || pm.CompletedBy != "scheduler" {
+		t.Errorf("Expected the maintenance to run from %v to %v, got %+v", start, end, pm)
+	}
+	if pm.OverrunAt != nil {
+		t.Errorf("A completed maintenance should not overrun")
+	}
+
+	// Without auto-completion, a started maintenance past its end is flagged once
+	manual := NewPlannedMaintenance("DB upgrade", "PostgreSQL 16", nil, start, end)
+	manual.AutoStart = true
+	if actions := manual.ApplySchedule("scheduler", start.Add(time.Minute)); len(actions) != 1 || actions[0] != ScheduleStart {
+		t.Fatalf("ApplySchedule() after the start = %v, want start", actions)
+	}
+	now := end.Add(time.Minute)
+	if actions := manual.ApplySchedule("scheduler", now); len(actions) != 1 || actions[0] != ScheduleOverrun {
+		t.Fatalf("ApplySchedule() past the end = %v, want overrun", actions)
+	}
+	if !manual.IsOverrunning(now) || manual.OverrunAt == nil || !manual.OverrunAt.Equal(now) {
+		t.Errorf("Expected the maintenance to be overrunning since %v, got %v", now, manual.OverrunAt)
+	}
+	if actions := manual.ApplySchedule("scheduler", now.Add(time.Hour)); len(actions) != 0 {
+		t.Errorf("ApplySchedule() on a flagged maintenance = %v, want no action", actions)
+	}
+	if err := manual.Complete("alice", now.Add(time.Hour)); err != nil || manual.IsOverrunning(now.Add(time.Hour)) {
+		t.Errorf("A completed maintenance should not be overrunning anymore")
+	}
+}
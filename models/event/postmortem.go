@@ -0,0 +1,55 @@
+package event
+
+import (
+	"fmt"
+	"time"
+)
+
+// Postmortem is the root cause analysis of an ended incident
+type Postmortem struct {
+	Summary             string       `json:"summary"`
+	RootCause           string       `json:"root_cause"`
+	ContributingFactors []string     `json:"contributing_factors,omitempty"`
+	ActionItems         []ActionItem `json:"action_items,omitempty"`
+	Published           bool         `json:"published,omitempty"`
+	PublishedAt         *time.Time   `json:"published_at,omitempty"` // First publication, kept when the postmortem is edited
+	Author              string       `json:"author,omitempty"`       // Author of the last edit
+	UpdatedAt           time.Time    `json:"updated_at"`
+}
+
+// ActionItem is a follow-up of a postmortem, owned by someone
+type ActionItem struct {
+	Description string     `json:"description"`
+	Owner       string     `json:"owner"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Done        bool       `json:"done,omitempty"`
+}
+
+// SetPostmortem attaches the postmortem written by author at the given time to the ended incident,
+// replacing the previous one but keeping its first publication time
+func (i *Incident) SetPostmortem(postmortem Postmortem, author string, at time.Time) error {
+	if i.Perpetual {
+		return fmt.Errorf("%w: known issues have no postmortem", ErrInvalidTransition)
+	}
+	if i.IsOpen() {
+		return fmt.Errorf("%w: the incident is not resolved yet", ErrInvalidTransition)
+	}
+
+	postmortem.Author, postmortem.UpdatedAt, postmortem.PublishedAt = author, at, nil
+	if postmortem.Published {
+		postmortem.PublishedAt = &at
+		if i.Postmortem != nil && i.Postmortem.PublishedAt != nil {
+			postmortem.PublishedAt = i.Postmortem.PublishedAt
+		}
+	}
+	i.Postmortem = &postmortem
+	return nil
+}
+
+// NeedsPostmortem reports whether the incident ended with at least the given criticality and has no published postmortem
+func (i *Incident) NeedsPostmortem(minimum Criticality) bool {
+	if i.Perpetual || i.IsOpen() || i.Criticality() < minimum {
+		return false
+	}
+	return i.Postmortem == nil || !i.Postmortem.Published
+}
@@ -0,0 +1,69 @@
+package event
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIncident_SetPostmortem(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	incident := NewFiringIncident("Outage", "API is down", nil, CriticalityMajorOutage)
+	if err := incident.Transition(StatusInvestigating, "Looking into it", "alice", start); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+
+	draft := Postmortem{Summary: "The API was down", RootCause: "Expired certificate"}
+	if err := incident.SetPostmortem(draft, "alice", start); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected an open incident to have no postmortem, got %v", err)
+	}
+	if incident.NeedsPostmortem(CriticalityOperational) {
+		t.Error("Expected an open incident not to need a postmortem yet")
+	}
+
+	if err := incident.Transition(StatusResolved, "Fixed", "bob", start.Add(time.Hour)); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if !incident.NeedsPostmortem(CriticalityMajorOutage) {
+		t.Error("Expected a resolved major outage to need a postmortem")
+	}
+
+	if err := incident.SetPostmortem(draft, "alice", start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("SetPostmortem failed: %v", err)
+	}
+	if incident.Postmortem.Author != "alice" || incident.Postmortem.PublishedAt != nil {
+		t.Errorf("Expected an unpublished draft by alice, got %+v", incident.Postmortem)
+	}
+	if !incident.NeedsPostmortem(CriticalityMajorOutage) {
+		t.Error("Expected a draft not to satisfy the postmortem requirement")
+	}
+
+	published := draft
+	published.Published = true
+	firstPublication := start.Add(3 * time.Hour)
+	if err := incident.SetPostmortem(published, "bob", firstPublication); err != nil {
+		t.Fatalf("SetPostmortem failed: %v", err)
+	}
+	if err := incident.SetPostmortem(published, "carol", start.Add(4*time.Hour)); err != nil {
+		t.Fatalf("SetPostmortem failed: %v", err)
+	}
+	if incident.Postmortem.Author != "carol" || incident.Postmortem.PublishedAt == nil || !incident.Postmortem.PublishedAt.Equal(firstPublication) {
+		t.Errorf("Expected the first publication time to be kept, got %+v", incident.Postmortem)
+	}
+	if incident.NeedsPostmortem(CriticalityMajorOutage) {
+		t.Error("Expected a published postmortem to satisfy the requirement")
+	}
+
+	minor := NewFiringIncident("Slowness", "API is slow", nil, CriticalityDegraded)
+	if err := minor.Transition(StatusResolved, "Fixed", "bob", start); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	if minor.NeedsPostmortem(CriticalityMajorOutage) {
+		t.Error("Expected a degradation not to need a major outage postmortem")
+	}
+
+	knownIssue := NewIncident("Known issue", "Slow exports", nil, CriticalityDegraded, true)
+	if err := knownIssue.SetPostmortem(draft, "alice", start); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected a known issue to have no postmortem, got %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRecurrence is returned when a recurrence rule cannot be parsed
+var ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+
+// Frequency is the period a recurrence rule repeats over
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+// maxRecurrencePeriods bounds the periods a rule is expanded over, so that rules which never match end
+const maxRecurrencePeriods = 100000
+
+// WeekdayNum is a BYDAY entry: a weekday, with the position of the weekday in the month for monthly rules.
+// Ordinal 0 means every such weekday, 2 the second one and -1 the last one.
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// Recurrence is a parsed RFC 5545 RRULE, limited to the FREQ, INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY parts
+type Recurrence struct {
+	Frequency  Frequency
+	Interval   int        // 1 when not set
+	Count      int        // 0 when not set
+	Until      *time.Time // Last instant an occurrence may start at
+	ByDay      []WeekdayNum
+	ByMonthDay []int // Days of the month, negative ones count from the end
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// ParseRecurrence parses a rule such as "FREQ=MONTHLY;BYDAY=2TU" or "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;COUNT=10".
+// The "RRULE:" prefix is optional. UNTIL is given as 20060102T150405Z or as a date, which includes the whole day.
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	if rule == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRecurrence)
+	}
+
+	r := &Recurrence{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: expected NAME=VALUE, got %q", ErrInvalidRecurrence, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s is given twice", ErrInvalidRecurrence, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Frequency = Frequency(value)
+			if r.Frequency != FrequencyDaily && r.Frequency != FrequencyWeekly && r.Frequency != FrequencyMonthly {
+				err = fmt.Errorf("unsupported FREQ %s, expected DAILY, WEEKLY or MONTHLY", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			r.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		default:
+			err = fmt.Errorf("unsupported part %s, expected FREQ, INTERVAL, COUNT, UNTIL, BYDAY or BYMONTHDAY", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+	}
+
+	switch {
+	case r.Frequency == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRecurrence)
+	case r.Count > 0 && r.Until != nil:
+		return nil, fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRecurrence)
+	case len(r.ByMonthDay) > 0 && r.Frequency == FrequencyWeekly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY cannot be used with FREQ=WEEKLY", ErrInvalidRecurrence)
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Frequency != FrequencyMonthly {
+			return nil, fmt.Errorf("%w: BYDAY positions such as 2TU require FREQ=MONTHLY", ErrInvalidRecurrence)
+		}
+	}
+	return r, nil
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %s", name, value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (*time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return &until, nil
+	}
+	day, err := time.Parse("20060102", value)
+	if err != nil {
+		return nil, fmt.Errorf("UNTIL must be formatted as 20060102T150405Z or 20060102, got %s", value)
+	}
+	until := day.Add(24*time.Hour - time.Second)
+	return &until, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, entry := range strings.Split(value, ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY entry %q", entry)
+		}
+		weekday, ok := weekdayCodes[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY weekday in %q, expected MO, TU, WE, TH, FR, SA or SU", entry)
+		}
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := entry[:len(entry)-2]; prefix != "" {
+			ordinal, err := strconv.Atoi(prefix)
+			if err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+				return nil, fmt.Errorf("invalid BYDAY position in %q, expected 1 to 5 or -1 to -5", entry)
+			}
+			day.Ordinal = ordinal
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, entry := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(entry)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %q, expected 1 to 31 or -1 to -31", entry)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// String returns the rule in its RRULE form, without prefix
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = strings.ToUpper(day.Weekday.String()[:2])
+			if day.Ordinal != 0 {
+				days[i] = strconv.Itoa(day.Ordinal) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// IsBounded reports whether the rule ends, through COUNT or UNTIL
+func (r *Recurrence) IsBounded() bool {
+	return r.Count > 0 || r.Until != nil
+}
+
+// each calls fn with the start of every occurrence of a series starting at dtstart, in order,
+// until fn returns false or the series ends. dtstart is always the first occurrence, as in RFC 5545.
+func (r *Recurrence) each(dtstart time.Time, fn func(time.Time) bool) {
+	count := 0
+	emit := func(start time.Time) bool {
+		if r.Until != nil && start.After(*r.Until) {
+			return false
+		}
+		count++
+		if !fn(start) {
+			return false
+		}
+		return r.Count == 0 || count < r.Count
+	}
+
+	if !emit(dtstart) {
+		return
+	}
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, start := range r.periodStarts(dtstart, period) {
+			if !start.After(dtstart) {
+				continue
+			}
+			if !emit(start) {
+				return
+			}
+		}
+		// Without any candidate left past UNTIL, the series is over
+		if r.Until != nil && r.periodBegin(dtstart, period).After(*r.Until) {
+			return
+		}
+	}
+}
+
+// periodBegin returns the first day of a period of the series, at midnight
+func (r *Recurrence) periodBegin(dtstart time.Time, period int) time.Time {
+	year, month, day := dtstart.Date()
+	switch r.Frequency {
+	case FrequencyWeekly:
+		// Weeks start on Monday
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset+7*r.Interval*period, 0, 0, 0, 0, dtstart.Location())
+	case FrequencyMonthly:
+		return time.Date(year, month+time.Month(r.Interval*period), 1, 0, 0, 0, 0, dtstart.Location())
+	default:
+		return time.Date(year, month, day+r.Interval*period, 0, 0, 0, 0, dtstart.Location())
+	}
+}
+
+// periodStarts returns the sorted occurrence starts of a period, at the time of day of dtstart
+func (r *Recurrence) periodStarts(dtstart time.Time, period int) []time.Time {
+	begin := r.periodBegin(dtstart, period)
+	at := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), dtstart.Nanosecond(), dtstart.Location())
+	}
+
+	var days []time.Time
+	switch r.Frequency {
+	case FrequencyDaily:
+		if r.matchesWeekday(begin) && r.matchesMonthDay(begin) {
+			days = append(days, begin)
+		}
+	case FrequencyWeekly:
+		for offset := 0; offset < 7; offset++ {
+			day := begin.AddDate(0, 0, offset)
+			if len(r.ByDay) == 0 && day.Weekday() == dtstart.Weekday() || len(r.ByDay) > 0 && r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+	case FrequencyMonthly:
+		for day := begin; day.Month() == begin.Month(); day = day.AddDate(0, 0, 1) {
+			if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+				if day.Day() == dtstart.Day() {
+					days = append(days, day)
+				}
+				continue
+			}
+			if (len(r.ByDay) == 0 || r.matchesMonthWeekday(day)) && r.matchesMonthDay(day) {
+				days = append(days, day)
+			}
+		}
+	}
+
+	starts := make([]time.Time, len(days))
+	for i, day := range days {
+		starts[i] = at(day)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	return starts
+}
+
+// matchesWeekday reports whether day is one of the BYDAY weekdays, ignoring positions
+func (r *Recurrence) matchesWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, entry := range r.ByDay {
+		if entry.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthWeekday reports whether day is one of the BYDAY weekdays at its position in the month
+func (r *Recurrence) matchesMonthWeekday(day time.Time) bool {
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	fromStart := (day.Day()-1)/7 + 1
+	fromEnd := -((daysInMonth-day.Day())/7 + 1)
+	for _, entry := range r.ByDay {
+		if entry.Weekday == day.Weekday() && (entry.Ordinal == 0 || entry.Ordinal == fromStart || entry.Ordinal == fromEnd) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthDay reports whether day is one of the BYMONTHDAY days
+func (r *Recurrence) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay == day.Day() || monthDay < 0 && daysInMonth+monthDay+1 == day.Day() {
+			return true
+		}
+	}
+	return false
+}
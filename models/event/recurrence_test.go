@@ -0,0 +1,113 @@
+package event
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "FREQ=MONTHLY;BYDAY=2TU", want: "FREQ=MONTHLY;BYDAY=2TU"},
+		{rule: "RRULE:freq=weekly;interval=2;byday=MO,FR;count=10", want: "FREQ=WEEKLY;INTERVAL=2;COUNT=10;BYDAY=MO,FR"},
+		{rule: "FREQ=DAILY;UNTIL=20260301", want: "FREQ=DAILY;UNTIL=20260301T235959Z"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=-1", want: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{rule: "", wantErr: true},
+		{rule: "INTERVAL=2", wantErr: true},
+		{rule: "FREQ=YEARLY", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=3;UNTIL=20260301", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=2TU", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{rule: "FREQ=DAILY;BYHOUR=3", wantErr: true},
+		{rule: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYDAY=XX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := ParseRecurrence(tt.rule)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRecurrence) {
+					t.Fatalf("ParseRecurrence() error = %v, want ErrInvalidRecurrence", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRecurrence() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("String() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Expansion(t *testing.T) {
+	// Tuesday 13 January 2026, the second Tuesday of the month
+	dtstart := time.Date(2026, 1, 13, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule string
+		want []string
+	}{
+		{
+			rule: "FREQ=MONTHLY;BYDAY=2TU;COUNT=4",
+			want: []string{"2026-01-13", "2026-02-10", "2026-03-10", "2026-04-14"},
+		},
+		{
+			rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			want: []string{"2026-01-13", "2026-01-30", "2026-02-27"},
+		},
+		{
+			rule: "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3",
+			want: []string{"2026-01-13", "2026-01-31", "2026-03-31"},
+		},
+		{
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=5",
+			want: []string{"2026-01-13", "2026-01-15", "2026-01-27", "2026-01-29", "2026-02-10"},
+		},
+		{
+			rule: "FREQ=WEEKLY;UNTIL=20260203T220000Z",
+			want: []string{"2026-01-13", "2026-01-20", "2026-01-27", "2026-02-03"},
+		},
+		{
+			rule: "FREQ=DAILY;INTERVAL=3;COUNT=3",
+			want: []string{"2026-01-13", "2026-01-16", "2026-01-19"},
+		},
+		{
+			rule: "FREQ=DAILY;BYDAY=SA,SU;COUNT=3",
+			want: []string{"2026-01-13", "2026-01-17", "2026-01-18"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence() error = %v", err)
+			}
+			var got []string
+			rule.each(dtstart, func(start time.Time) bool {
+				if start.Hour() != 22 {
+					t.Errorf("occurrence %v does not keep the time of day of DTSTART", start)
+				}
+				got = append(got, start.Format("2006-01-02"))
+				return len(got) < 10
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("occurrences = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("occurrences = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RevisionAction represents the kind of write a revision records
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "created"
+	RevisionUpdated  RevisionAction = "updated"
+	RevisionDeleted  RevisionAction = "deleted"
+	RevisionRestored RevisionAction = "restored"
+)
+
+// FieldChange describes how a single top-level field of an event changed.
+// Before is absent for created fields and After for removed ones.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Revision is an immutable record of a single write to an event
+type Revision struct {
+	EventGUID string         `json:"event_guid"`
+	Version   int64          `json:"version"` // Event version after the write, or the deleted version
+	Action    RevisionAction `json:"action"`
+	Author    string         `json:"author,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	Changes   []FieldChange  `json:"changes"`
+}
+
+// revisionIgnoredFields are bumped on every write and would only add noise to a diff
+var revisionIgnoredFields = map[string]bool{"version": true}
+
+// Diff compares the JSON representations of two events field by field.
+// A nil before lists every field of after, a nil after lists every field of before.
+func Diff(before, after interface{}) ([]FieldChange, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, seen := beforeFields[name]; !seen {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []FieldChange{}
+	for _, name := range names {
+		if revisionIgnoredFields[name] {
+			continue
+		}
+		b, a := beforeFields[name], afterFields[name]
+		if bytes.Equal(b, a) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Before: b, After: a})
+	}
+	return changes, nil
+}
+
+// jsonFields returns the top-level fields of the JSON representation of v, null ones excluded
+func jsonFields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if v == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode event fields: %w", err)
+	}
+	for name, value := range fields {
+		if bytes.Equal(value, []byte("null")) {
+			delete(fields, name)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,50 @@
+package event
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := NewFiringIncident("Outage", "API down", nil, CriticalityMajorOutage)
+	after := *before
+	after.Title = "Partial outage"
+	after.Version = before.Version + 1
+
+	changes, err := Diff(before, &after)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Field != "title" || string(changes[0].Before) != `"Outage"` || string(changes[0].After) != `"Partial outage"` {
+		t.Errorf("Unexpected title change: %+v", changes[0])
+	}
+}
+
+func TestDiff_CreateAndDelete(t *testing.T) {
+	incident := NewFiringIncident("Outage", "API down", nil, CriticalityMajorOutage)
+
+	created, err := Diff(nil, incident)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	deleted, err := Diff(incident, nil)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(created) == 0 || len(created) != len(deleted) {
+		t.Fatalf("Expected matching non-empty creation and deletion diffs, got %d and %d", len(created), len(deleted))
+	}
+	for i := range created {
+		if created[i].Before != nil || created[i].After == nil {
+			t.Errorf("Expected creation change %q to only have an after value", created[i].Field)
+		}
+		if deleted[i].After != nil || deleted[i].Before == nil {
+			t.Errorf("Expected deletion change %q to only have a before value", deleted[i].Field)
+		}
+		if created[i].Field == "version" {
+			t.Errorf("Expected version to be excluded from the diff")
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package event
+
+import (
+	"time"
+
+	"github.com/gmllt/clariti/utils"
+)
+
+// Update is a timestamped public message posted on an incident or a maintenance while it goes on
+type Update struct {
+	GUID    string    `json:"guid"`
+	At      time.Time `json:"at"`
+	Message string    `json:"message"`
+	Author  string    `json:"author,omitempty"`
+}
+
+// PostUpdate appends a new update to the event and returns it.
+// Updates are kept in the order they were posted.
+func (b *BaseEvent) PostUpdate(message, author string, at time.Time) Update {
+	update := Update{
+		GUID:    utils.NewGUIDString(),
+		At:      at,
+		Message: message,
+		Author:  author,
+	}
+	// The updates may be shared with a stored copy of the event, never append in place
+	b.Updates = append(b.Updates[:len(b.Updates):len(b.Updates)], update)
+	return update
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/gmllt/clariti/logger"
 	"github.com/gmllt/clariti/models/component"
@@ -15,6 +16,8 @@ type Config struct {
 	Auth       AuthConfig       `yaml:"auth"`
 	Components ComponentsConfig `yaml:"components"`
 	Storage    StorageConfig    `yaml:"storage"`
+	Retention  RetentionConfig  `yaml:"retention,omitempty"`
+	Scheduler  SchedulerConfig  `yaml:"scheduler,omitempty"`
 	Logging    logger.Config    `yaml:"logging"`
 }
 
@@ -60,8 +63,78 @@ type ComponentConfig struct {
 
 // StorageConfig holds the storage driver configuration
 type StorageConfig struct {
-	Driver string   `yaml:"driver"` // "ram" or "s3"
-	S3     S3Config `yaml:"s3,omitempty"`
+	Driver string       `yaml:"driver"` // "ram", "file", "sqlite", "s3" or "mirror"
+	RAM    RAMConfig    `yaml:"ram,omitempty"`
+	S3     S3Config     `yaml:"s3,omitempty"`
+	File   FileConfig   `yaml:"file,omitempty"`
+	SQLite SQLiteConfig `yaml:"sqlite,omitempty"`
+	Trash  TrashConfig  `yaml:"trash,omitempty"`
+	Cache  CacheConfig  `yaml:"cache,omitempty"`
+	Mirror MirrorConfig `yaml:"mirror,omitempty"`
+}
+
+// MirrorConfig holds the storages of the mirror driver, each configured like the storage section.
+// Their trash and cache settings are ignored, the ones of the mirror apply.
+type MirrorConfig struct {
+	Primary   *StorageConfig `yaml:"primary,omitempty"`   // Storage reads are served from
+	Secondary *StorageConfig `yaml:"secondary,omitempty"` // Storage writes are mirrored to
+}
+
+// DefaultTrashPurgeAfter is how long deleted events stay in the trash when not configured
+const DefaultTrashPurgeAfter = 30 * 24 * time.Hour
+
+// TrashConfig holds the configuration of deleted events
+type TrashConfig struct {
+	PurgeAfter time.Duration `yaml:"purge_after,omitempty"` // Default age of the deleted events a purge removes (e.g. "720h")
+}
+
+// DefaultCacheMaxEntries is how many reads the storage cache keeps when not configured
+const DefaultCacheMaxEntries = 1000
+
+// CacheConfig holds the configuration of the read-through storage cache, disabled unless a TTL is set
+type CacheConfig struct {
+	TTL        time.Duration `yaml:"ttl,omitempty"`         // How long a read is served from the cache (e.g. "30s")
+	MaxEntries int           `yaml:"max_entries,omitempty"` // Least recently used reads are evicted beyond this count
+}
+
+// Enabled reports whether storage reads are cached
+func (c CacheConfig) Enabled() bool {
+	return c.TTL > 0
+}
+
+// Retention actions applied to the events past their retention
+const (
+	RetentionArchive = "archive" // Move the events to the archive storage, queryable through the history endpoints
+	RetentionDelete  = "delete"  // Move the events to the trash
+)
+
+// DefaultRetentionInterval is how often the retention policy is enforced when not configured
+const DefaultRetentionInterval = time.Hour
+
+// RetentionConfig holds the retention policy of ended events, disabled unless a retention is set
+type RetentionConfig struct {
+	ResolvedIncidents     time.Duration `yaml:"resolved_incidents,omitempty"`     // How long incidents are kept after their end (e.g. "2160h")
+	CancelledMaintenances time.Duration `yaml:"cancelled_maintenances,omitempty"` // How long cancelled maintenances are kept after their planned end
+	Action                string        `yaml:"action,omitempty"`                 // "archive" (default) or "delete"
+	Interval              time.Duration `yaml:"interval,omitempty"`               // How often the policy is enforced
+}
+
+// Enabled reports whether the retention policy removes any event
+func (c RetentionConfig) Enabled() bool {
+	return c.ResolvedIncidents > 0 || c.CancelledMaintenances > 0
+}
+
+// Archives reports whether the retention policy moves events to the archive storage
+func (c RetentionConfig) Archives() bool {
+	return c.Enabled() && c.Action == RetentionArchive
+}
+
+// DefaultSchedulerInterval is how often the maintenance scheduler runs when not configured
+const DefaultSchedulerInterval = time.Minute
+
+// SchedulerConfig holds the settings of the scheduler starting and completing the opted-in maintenances
+type SchedulerConfig struct {
+	Interval time.Duration `yaml:"interval,omitempty"` // How often planned boundaries are checked, also the precision of the actions
 }
 
 // S3Config holds S3-specific configuration
@@ -72,6 +145,39 @@ type S3Config struct {
 	SecretAccessKey string `yaml:"secret_access_key,omitempty"` // Optional, can use IAM roles
 	Endpoint        string `yaml:"endpoint,omitempty"`          // Optional, for S3-compatible services
 	Prefix          string `yaml:"prefix,omitempty"`            // Optional, prefix for object keys
+
+	Encryption S3EncryptionConfig `yaml:"encryption,omitempty"` // Optional, client-side encryption of the objects
+}
+
+// S3EncryptionConfig holds the master keys of the S3 client-side encryption, disabled unless a source is set.
+// Keys are "id:base64-key" entries separated by newlines or commas, the first one encrypts new objects.
+type S3EncryptionConfig struct {
+	KeyFile string `yaml:"key_file,omitempty"` // File holding the keys
+	KeyEnv  string `yaml:"key_env,omitempty"`  // Environment variable holding the keys
+}
+
+// Enabled reports whether objects are encrypted before they are uploaded
+func (c S3EncryptionConfig) Enabled() bool {
+	return c.KeyFile != "" || c.KeyEnv != ""
+}
+
+// DefaultRAMSnapshotInterval is how often RAM snapshots are saved when not configured
+const DefaultRAMSnapshotInterval = time.Minute
+
+// RAMConfig holds in-memory storage configuration
+type RAMConfig struct {
+	SnapshotPath     string        `yaml:"snapshot_path,omitempty"`     // Optional, JSON file the events are saved to and reloaded from
+	SnapshotInterval time.Duration `yaml:"snapshot_interval,omitempty"` // How often the snapshot is saved, besides on shutdown
+}
+
+// FileConfig holds local file storage configuration
+type FileConfig struct {
+	Path string `yaml:"path"` // Directory holding the event files
+}
+
+// SQLiteConfig holds embedded SQLite storage configuration
+type SQLiteConfig struct {
+	Path string `yaml:"path"` // Database file, created if missing
 }
 
 // LoadConfig loads configuration from a YAML file
@@ -103,11 +209,37 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.Storage.Driver == "" {
 		config.Storage.Driver = "ram"
 	}
+	if config.Storage.Trash.PurgeAfter == 0 {
+		config.Storage.Trash.PurgeAfter = DefaultTrashPurgeAfter
+	}
+	if config.Storage.Cache.MaxEntries == 0 {
+		config.Storage.Cache.MaxEntries = DefaultCacheMaxEntries
+	}
+	for _, storage := range []*StorageConfig{&config.Storage, config.Storage.Mirror.Primary, config.Storage.Mirror.Secondary} {
+		if storage != nil && storage.RAM.SnapshotInterval == 0 {
+			storage.RAM.SnapshotInterval = DefaultRAMSnapshotInterval
+		}
+	}
+	if config.Retention.Action == "" {
+		config.Retention.Action = RetentionArchive
+	}
+	if config.Retention.Interval == 0 {
+		config.Retention.Interval = DefaultRetentionInterval
+	}
+	if config.Scheduler.Interval == 0 {
+		config.Scheduler.Interval = DefaultSchedulerInterval
+	}
 
 	// Validate storage configuration
 	if err := config.validateStorageConfig(); err != nil {
 		return nil, fmt.Errorf("invalid storage configuration: %w", err)
 	}
+	if err := config.validateRetentionConfig(); err != nil {
+		return nil, fmt.Errorf("invalid retention configuration: %w", err)
+	}
+	if config.Scheduler.Interval < 0 {
+		return nil, fmt.Errorf("invalid scheduler configuration: interval must not be negative")
+	}
 
 	// Initialize logger with configuration
 	logger.Init(&config.Logging)
@@ -211,9 +343,22 @@ func (c *Config) GetFullURL() string {
 
 // validateStorageConfig validates the storage configuration based on the selected driver
 func (c *Config) validateStorageConfig() error {
+	if c.Storage.Trash.PurgeAfter < 0 {
+		return fmt.Errorf("trash purge_after must not be negative")
+	}
+	if c.Storage.Cache.TTL < 0 {
+		return fmt.Errorf("cache ttl must not be negative")
+	}
+	if c.Storage.Cache.MaxEntries < 0 {
+		return fmt.Errorf("cache max_entries must not be negative")
+	}
+
 	switch c.Storage.Driver {
 	case "ram":
 		// RAM driver requires no additional configuration
+		if c.Storage.RAM.SnapshotInterval < 0 {
+			return fmt.Errorf("ram snapshot_interval must not be negative")
+		}
 		return nil
 	case "s3":
 		if c.Storage.S3.Region == "" {
@@ -222,9 +367,61 @@ func (c *Config) validateStorageConfig() error {
 		if c.Storage.S3.Bucket == "" {
 			return fmt.Errorf("s3 bucket is required when using s3 driver")
 		}
+		if c.Storage.S3.Encryption.KeyFile != "" && c.Storage.S3.Encryption.KeyEnv != "" {
+			return fmt.Errorf("s3 encryption key_file and key_env are mutually exclusive")
+		}
+		return nil
+	case "file":
+		if c.Storage.File.Path == "" {
+			return fmt.Errorf("file path is required when using file driver")
+		}
+		return nil
+	case "sqlite":
+		if c.Storage.SQLite.Path == "" {
+			return fmt.Errorf("sqlite path is required when using sqlite driver")
+		}
+		return nil
+	case "mirror":
+		for _, backend := range []struct {
+			name    string
+			storage *StorageConfig
+		}{
+			{"primary", c.Storage.Mirror.Primary},
+			{"secondary", c.Storage.Mirror.Secondary},
+		} {
+			if backend.storage == nil {
+				return fmt.Errorf("mirror %s storage is required when using mirror driver", backend.name)
+			}
+			if backend.storage.Driver == "mirror" {
+				return fmt.Errorf("mirror %s storage cannot be a mirror", backend.name)
+			}
+			nested := Config{Storage: *backend.storage}
+			if err := nested.validateStorageConfig(); err != nil {
+				return fmt.Errorf("mirror %s: %w", backend.name, err)
+			}
+		}
 		return nil
 	default:
-		return fmt.Errorf("unsupported storage driver: %s (supported: ram, s3)", c.Storage.Driver)
+		return fmt.Errorf("unsupported storage driver: %s (supported: ram, file, sqlite, s3, mirror)", c.Storage.Driver)
+	}
+}
+
+// validateRetentionConfig validates the retention policy
+func (c *Config) validateRetentionConfig() error {
+	if c.Retention.ResolvedIncidents < 0 {
+		return fmt.Errorf("resolved_incidents must not be negative")
+	}
+	if c.Retention.CancelledMaintenances < 0 {
+		return fmt.Errorf("cancelled_maintenances must not be negative")
+	}
+	if c.Retention.Interval < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	switch c.Retention.Action {
+	case RetentionArchive, RetentionDelete:
+		return nil
+	default:
+		return fmt.Errorf("unsupported action: %s (supported: %s, %s)", c.Retention.Action, RetentionArchive, RetentionDelete)
 	}
 }
 
@@ -237,3 +434,46 @@ func (c *Config) GetStorageDriver() string {
 func (c *Config) GetS3Config() S3Config {
 	return c.Storage.S3
 }
+
+// GetRAMConfig returns the RAM storage configuration
+func (c *Config) GetRAMConfig() RAMConfig {
+	return c.Storage.RAM
+}
+
+// GetFileConfig returns the file storage configuration
+func (c *Config) GetFileConfig() FileConfig {
+	return c.Storage.File
+}
+
+// GetCacheConfig returns the storage cache configuration
+func (c *Config) GetCacheConfig() CacheConfig {
+	return c.Storage.Cache
+}
+
+// GetRetentionConfig returns the retention policy, with the default interval when not loaded from a file
+func (c *Config) GetRetentionConfig() RetentionConfig {
+	retention := c.Retention
+	if retention.Interval <= 0 {
+		retention.Interval = DefaultRetentionInterval
+	}
+	return retention
+}
+
+// GetSchedulerConfig returns the maintenance scheduler settings, with the default interval when not loaded from a file
+func (c *Config) GetSchedulerConfig() SchedulerConfig {
+	scheduler := c.Scheduler
+	if scheduler.Interval <= 0 {
+		scheduler.Interval = DefaultSchedulerInterval
+	}
+	return scheduler
+}
+
+// GetMirrorConfig returns the mirror storage configuration
+func (c *Config) GetMirrorConfig() MirrorConfig {
+	return c.Storage.Mirror
+}
+
+// GetSQLiteConfig returns the SQLite storage configuration
+func (c *Config) GetSQLiteConfig() SQLiteConfig {
+	return c.Storage.SQLite
+}
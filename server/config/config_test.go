@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 func TestConfig_StorageDriverValidation(t *testing.T) {
@@ -56,6 +57,140 @@ func TestConfig_StorageDriverValidation(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "File driver valid",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "file",
+					File: FileConfig{
+						Path: "/var/lib/clariti",
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "File driver missing path",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "file",
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "SQLite driver valid",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "sqlite",
+					SQLite: SQLiteConfig{
+						Path: "/var/lib/clariti/clariti.db",
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "SQLite driver missing path",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "sqlite",
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative trash purge age",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "ram",
+					Trash: TrashConfig{
+						PurgeAfter: -time.Hour,
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "S3 encryption with two key sources",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "s3",
+					S3: S3Config{
+						Region: "us-east-1",
+						Bucket: "test-bucket",
+						Encryption: S3EncryptionConfig{
+							KeyFile: "/etc/clariti/keys",
+							KeyEnv:  "CLARITI_S3_KEYS",
+						},
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Negative cache TTL",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "ram",
+					Cache: CacheConfig{
+						TTL: -time.Second,
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Mirror driver valid",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "mirror",
+					Mirror: MirrorConfig{
+						Primary:   &StorageConfig{Driver: "sqlite", SQLite: SQLiteConfig{Path: "/var/lib/clariti/clariti.db"}},
+						Secondary: &StorageConfig{Driver: "file", File: FileConfig{Path: "/var/lib/clariti/mirror"}},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "Mirror driver missing secondary",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "mirror",
+					Mirror: MirrorConfig{
+						Primary: &StorageConfig{Driver: "ram"},
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Mirror driver with invalid primary",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "mirror",
+					Mirror: MirrorConfig{
+						Primary:   &StorageConfig{Driver: "sqlite"},
+						Secondary: &StorageConfig{Driver: "ram"},
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "Nested mirror driver",
+			config: Config{
+				Storage: StorageConfig{
+					Driver: "mirror",
+					Mirror: MirrorConfig{
+						Primary:   &StorageConfig{Driver: "ram"},
+						Secondary: &StorageConfig{Driver: "mirror"},
+					},
+				},
+			},
+			expectError: true,
+		},
 		{
 			name: "Unsupported driver",
 			config: Config{
@@ -114,3 +249,58 @@ func TestConfig_GetS3Config(t *testing.T) {
 		t.Errorf("Expected prefix clariti/, got %s", s3Config.Prefix)
 	}
 }
+
+func TestConfig_RetentionValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		retention   RetentionConfig
+		expectError bool
+	}{
+		{
+			name:        "Archive action valid",
+			retention:   RetentionConfig{ResolvedIncidents: 90 * 24 * time.Hour, Action: RetentionArchive},
+			expectError: false,
+		},
+		{
+			name:        "Delete action valid",
+			retention:   RetentionConfig{CancelledMaintenances: 30 * 24 * time.Hour, Action: RetentionDelete},
+			expectError: false,
+		},
+		{
+			name:        "Negative retention",
+			retention:   RetentionConfig{ResolvedIncidents: -time.Hour, Action: RetentionArchive},
+			expectError: true,
+		},
+		{
+			name:        "Unsupported action",
+			retention:   RetentionConfig{ResolvedIncidents: time.Hour, Action: "shred"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Retention: tt.retention}
+			err := config.validateRetentionConfig()
+			if tt.expectError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfig_GetRetentionConfigDefaultInterval(t *testing.T) {
+	// Configs built in code skip the defaults applied when loading a file
+	config := Config{Retention: RetentionConfig{ResolvedIncidents: time.Hour, Action: RetentionArchive}}
+	if interval := config.GetRetentionConfig().Interval; interval != DefaultRetentionInterval {
+		t.Errorf("Expected the default interval %v, got %v", DefaultRetentionInterval, interval)
+	}
+
+	config.Retention.Interval = 5 * time.Minute
+	if interval := config.GetRetentionConfig().Interval; interval != 5*time.Minute {
+		t.Errorf("Expected the configured interval, got %v", interval)
+	}
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// RebuildIndex recreates the index of the configured storage driver from its events and returns the number of indexed events.
+// It fails for drivers without an index.
+func RebuildIndex(configPath string) (int, error) {
+	cfg, storage, err := openStorage(configPath)
+	if err != nil {
+		return 0, err
+	}
+	defer closeStorage(storage)
+
+	indexed, ok := storage.(drivers.IndexedEventStorage)
+	if !ok {
+		return 0, fmt.Errorf("storage driver %s does not maintain an index", cfg.GetStorageDriver())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return indexed.RebuildIndex(ctx)
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gmllt/clariti/server/config"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// migrationAuthor is the author of the revisions recorded while migrating events
+const migrationAuthor = "migration"
+
+// Migrate copies every event from the storage configured in fromConfigPath to the one configured in toConfigPath.
+// Caches configured in either file are bypassed.
+func Migrate(fromConfigPath, toConfigPath string, opts drivers.MigrateOptions) (*drivers.MigrationReport, error) {
+	_, from, err := openStorage(fromConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("source storage: %w", err)
+	}
+	defer closeStorage(from)
+
+	_, to, err := openStorage(toConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("destination storage: %w", err)
+	}
+	from, to = withoutCache(from), withoutCache(to)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	report, err := drivers.Migrate(drivers.WithAuthor(ctx, migrationAuthor), from, to, opts)
+	// Closing saves the events copied to a RAM snapshot
+	if closeErr := closeStorage(to); closeErr != nil && err == nil {
+		err = fmt.Errorf("destination storage: %w", closeErr)
+	}
+	return report, err
+}
+
+// openStorage loads configPath and creates its storage driver
+func openStorage(configPath string) (*config.Config, drivers.EventStorage, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	storage, err := drivers.NewStorage(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, storage, nil
+}
+
+// withoutCache returns the storage driver behind a configured cache, which commands reading every event have no use for
+func withoutCache(storage drivers.EventStorage) drivers.EventStorage {
+	if cached, ok := storage.(*drivers.CachedStorage); ok {
+		return cached.Unwrap()
+	}
+	return storage
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// reconcileAuthor is the author of the revisions recorded while repairing a mirror
+const reconcileAuthor = "reconcile"
+
+// Reconcile makes the secondary storage of the mirror driver configured in configPath match the primary one.
+// It fails for other drivers.
+func Reconcile(configPath string, dryRun bool) (*drivers.ReconcileReport, error) {
+	cfg, storage, err := openStorage(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeStorage(storage)
+
+	mirror, ok := withoutCache(storage).(*drivers.MirrorStorage)
+	if !ok {
+		return nil, fmt.Errorf("storage driver %s is not a mirror", cfg.GetStorageDriver())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return mirror.Reconcile(drivers.WithAuthor(ctx, reconcileAuthor), dryRun)
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/gmllt/clariti/server/config"
+	"github.com/gmllt/clariti/server/drivers"
+	"github.com/gmllt/clariti/server/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// retentionAuthor is the author of the revisions recorded while enforcing the retention policy
+const retentionAuthor = "retention"
+
+// retentionTimeout bounds the storage calls made by a single enforcement of the retention policy
+const retentionTimeout = 5 * time.Minute
+
+// enforceRetentionPeriodically applies the retention policy at startup then at the configured interval
+func (s *Server) enforceRetentionPeriodically(ctx context.Context, log *logrus.Entry) {
+	retention := s.config.GetRetentionConfig()
+	if retention.Archives() && s.archive == nil {
+		log.Warn("Retention policy archives events but no archive storage is available, not enforcing it")
+		return
+	}
+
+	log.WithField("interval", retention.Interval).WithField("action", retention.Action).Info("Starting retention policy enforcement")
+	ticker := time.NewTicker(retention.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.enforceRetention(ctx, log)
+		select {
+		case <-ctx.Done():
+			log.Debug("Stopping retention policy enforcement")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforceRetention removes the events past their retention once
+func (s *Server) enforceRetention(ctx context.Context, log *logrus.Entry) {
+	ctx, cancel := context.WithTimeout(drivers.WithAuthor(ctx, retentionAuthor), retentionTimeout)
+	defer cancel()
+
+	retention := s.config.GetRetentionConfig()
+	policy := drivers.RetentionPolicy{
+		ResolvedIncidents:     retention.ResolvedIncidents,
+		CancelledMaintenances: retention.CancelledMaintenances,
+	}
+	if retention.Action == config.RetentionArchive {
+		policy.Archive = s.archive
+	}
+
+	report, err := drivers.ApplyRetention(ctx, s.storage, policy, time.Now())
+	if report != nil {
+		metrics.RecordRetainedEvents("incidents", retention.Action, report.Incidents)
+		metrics.RecordRetainedEvents("planned_maintenances", retention.Action, report.PlannedMaintenances)
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to enforce the retention policy")
+		return
+	}
+	log.WithField("incidents", report.Incidents).WithField("planned_maintenances", report.PlannedMaintenances).Debug("Retention policy enforced")
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+	"github.com/gmllt/clariti/server/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// schedulerAuthor is the author of the actions applied by the maintenance scheduler
+const schedulerAuthor = "scheduler"
+
+// schedulerTimeout bounds the storage calls made by a single scheduler run
+const schedulerTimeout = time.Minute
+
+// scheduleMaintenancesPeriodically applies the scheduled maintenance actions at startup then at the configured interval
+func (s *Server) scheduleMaintenancesPeriodically(ctx context.Context, log *logrus.Entry) {
+	interval := s.config.GetSchedulerConfig().Interval
+	log.WithField("interval", interval).Info("Starting maintenance scheduler")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.scheduleMaintenances(ctx, log)
+		select {
+		case <-ctx.Done():
+			log.Debug("Stopping maintenance scheduler")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scheduleMaintenances starts, completes and flags the maintenances whose planned boundaries passed once
+func (s *Server) scheduleMaintenances(ctx context.Context, log *logrus.Entry) {
+	ctx, cancel := context.WithTimeout(drivers.WithAuthor(ctx, schedulerAuthor), schedulerTimeout)
+	defer cancel()
+
+	report, err := drivers.ApplySchedule(ctx, s.storage, time.Now())
+	if report != nil {
+		metrics.RecordSchedulerActions(string(event.ScheduleStart), report.Started)
+		metrics.RecordSchedulerActions(string(event.ScheduleComplete), report.Completed)
+		metrics.RecordSchedulerActions(string(event.ScheduleOverrun), report.Overruns)
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to apply the maintenance schedule")
+		return
+	}
+	log.WithField("started", report.Started).WithField("completed", report.Completed).WithField("overruns", report.Overruns).Debug("Maintenance schedule applied")
+}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"context"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -23,10 +25,18 @@ import (
 type Server struct {
 	config     *config.Config
 	storage    drivers.EventStorage
+	archive    drivers.EventStorage // Receives the events removed by the retention policy, nil unless it archives them
 	httpServer *http.Server
 	handlers   *handlers.Handlers
+
+	// baseCtx is the parent of every request context, cancelled to abort in-flight storage calls
+	baseCtx    context.Context
+	cancelBase context.CancelFunc
 }
 
+// metricsUpdateTimeout bounds the storage calls made by a single business metrics update
+const metricsUpdateTimeout = 10 * time.Second
+
 // New creates a new server instance
 func New(configPath string) (*Server, error) {
 	log := logger.GetDefault().WithComponent("Server")
@@ -50,9 +60,21 @@ func New(configPath string) (*Server, error) {
 	}
 	log.Info("Storage driver initialized successfully")
 
+	// Initialize archive storage
+	var archive drivers.EventStorage
+	if cfg.GetRetentionConfig().Archives() {
+		log.Info("Initializing archive storage")
+		archive, err = drivers.NewArchiveStorage(cfg)
+		if err != nil {
+			log.WithError(err).Error("Failed to initialize archive storage")
+			return nil, err
+		}
+		log.Info("Archive storage initialized successfully")
+	}
+
 	// Initialize handlers
 	log.Debug("Initializing request handlers")
-	handlers := handlers.New(storage, cfg)
+	handlers := handlers.New(storage, archive, cfg)
 
 	// Initialize metrics
 	log.Debug("Initializing application metrics")
@@ -73,27 +95,33 @@ func New(configPath string) (*Server, error) {
 
 	// Configure server with timeouts
 	log.Debug("Configuring HTTP server with timeouts")
+	baseCtx, cancelBase := context.WithCancel(context.Background())
 	httpServer := &http.Server{
 		Addr:         cfg.GetAddress(),
 		Handler:      middleware.CORS(middleware.BasicAuth(cfg)(middleware.MetricsMiddleware(logger.GetDefault())(mux))),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  120 * time.Second,
+		BaseContext:  func(net.Listener) context.Context { return baseCtx },
 	}
 
 	log.WithField("address", cfg.GetAddress()).Info("Server instance created successfully")
 	return &Server{
 		config:     cfg,
 		storage:    storage,
+		archive:    archive,
 		httpServer: httpServer,
 		handlers:   handlers,
+		baseCtx:    baseCtx,
+		cancelBase: cancelBase,
 	}, nil
 }
 
-// NewWithConfig creates a new server instance with provided config and storage (useful for testing)
+// NewWithConfig creates a new server instance with provided config and storage (useful for testing).
+// It has no archive storage, so the history endpoints are empty.
 func NewWithConfig(cfg *config.Config, storage drivers.EventStorage) *Server {
 	// Initialize handlers
-	handlers := handlers.New(storage, cfg)
+	handlers := handlers.New(storage, nil, cfg)
 
 	// Initialize metrics
 	metrics.InitializeApplicationInfo()
@@ -108,12 +136,14 @@ func NewWithConfig(cfg *config.Config, storage drivers.EventStorage) *Server {
 	routes.Setup(mux, handlers, cfg)
 
 	// Configure server with timeouts
+	baseCtx, cancelBase := context.WithCancel(context.Background())
 	httpServer := &http.Server{
 		Addr:         cfg.GetAddress(),
 		Handler:      middleware.CORS(middleware.BasicAuth(cfg)(middleware.MetricsMiddleware(logger.GetDefault())(mux))),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  120 * time.Second,
+		BaseContext:  func(net.Listener) context.Context { return baseCtx },
 	}
 
 	return &Server{
@@ -121,6 +151,8 @@ func NewWithConfig(cfg *config.Config, storage drivers.EventStorage) *Server {
 		storage:    storage,
 		httpServer: httpServer,
 		handlers:   handlers,
+		baseCtx:    baseCtx,
+		cancelBase: cancelBase,
 	}
 }
 
@@ -166,12 +198,23 @@ func (s *Server) Run() error {
 	}()
 
 	// Start metrics updater goroutine - flight operations monitor
-	go s.updateMetricsPeriodically(log)
+	backgroundCtx, stopBackground := context.WithCancel(s.baseCtx)
+	defer stopBackground()
+	go s.updateMetricsPeriodically(backgroundCtx, log)
+
+	// Start retention goroutine - logbook archival
+	if s.config.GetRetentionConfig().Enabled() {
+		go s.enforceRetentionPeriodically(backgroundCtx, log)
+	}
+
+	// Start scheduler goroutine - autopilot for opted-in maintenances
+	go s.scheduleMaintenancesPeriodically(backgroundCtx, log)
 
 	// Wait for interrupt signal
 	log.Info("Server ready, waiting for requests")
 	<-stop
 	log.Warn("Shutdown signal received, stopping server")
+	stopBackground()
 
 	// Create a context with timeout for graceful shutdown
 	log.Info("Starting graceful shutdown with 30s timeout")
@@ -180,14 +223,39 @@ func (s *Server) Run() error {
 
 	// Attempt graceful shutdown
 	if err := s.httpServer.Shutdown(ctx); err != nil {
+		// Abort the storage calls of requests still running past the grace period
+		s.cancelBase()
+		s.closeStorage(log)
 		log.WithError(err).Error("Server forced to shutdown")
 		return err
 	}
+	s.cancelBase()
+	s.closeStorage(log)
 
 	log.Info("Server stopped gracefully")
 	return nil
 }
 
+// closeStorage releases the storage drivers once requests are done, saving the RAM snapshots
+func (s *Server) closeStorage(log *logrus.Entry) {
+	if err := closeStorage(s.storage); err != nil {
+		log.WithError(err).Error("Failed to close storage")
+	}
+	if s.archive != nil {
+		if err := closeStorage(s.archive); err != nil {
+			log.WithError(err).Error("Failed to close archive storage")
+		}
+	}
+}
+
+// closeStorage closes a storage driver holding resources, such as a SQLite database or a RAM snapshot
+func closeStorage(storage drivers.EventStorage) error {
+	if closer, ok := storage.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // GetConfig returns the server configuration
 func (s *Server) GetConfig() *config.Config {
 	return s.config
@@ -198,24 +266,35 @@ func (s *Server) GetStorage() drivers.EventStorage {
 	return s.storage
 }
 
+// GetArchive returns the archive storage, nil unless the retention policy archives events
+func (s *Server) GetArchive() drivers.EventStorage {
+	return s.archive
+}
+
 // updateMetricsPeriodically updates business metrics every 30 seconds - operational status monitoring
-func (s *Server) updateMetricsPeriodically(log *logrus.Entry) {
+func (s *Server) updateMetricsPeriodically(ctx context.Context, log *logrus.Entry) {
 	log.Debug("Starting periodic metrics updater - flight operations monitor")
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			log.Debug("Stopping periodic metrics updater")
+			return
 		case <-ticker.C:
-			s.updateBusinessMetrics(log)
+			s.updateBusinessMetrics(ctx, log)
 		}
 	}
 }
 
 // updateBusinessMetrics collects and updates business-related metrics - operational status collection
-func (s *Server) updateBusinessMetrics(log *logrus.Entry) {
+func (s *Server) updateBusinessMetrics(ctx context.Context, log *logrus.Entry) {
 	log.Debug("Updating business metrics - collecting operational status")
 
+	ctx, cancel := context.WithTimeout(ctx, metricsUpdateTimeout)
+	defer cancel()
+
 	// Update uptime
 	metrics.UpdateUptimeMetrics()
 
@@ -231,7 +310,7 @@ func (s *Server) updateBusinessMetrics(log *logrus.Entry) {
 	// Get real data from storage - flight operations data collection
 
 	// Count incidents by severity and status
-	incidents, err := s.storage.GetAllIncidents()
+	incidents, err := s.storage.GetAllIncidents(ctx)
 	if err != nil {
 		log.WithError(err).Warn("Failed to retrieve incidents for metrics - using default values")
 		// Set default values on error
@@ -253,7 +332,7 @@ func (s *Server) updateBusinessMetrics(log *logrus.Entry) {
 
 		// Update metrics for all combinations
 		severities := []string{"operational", "degraded", "partial outage", "major outage", "under maintenance", "unknown"}
-		statuses := []string{"ongoing", "resolved", "unknown"}
+		statuses := []string{"investigating", "acknowledged", "identified", "monitoring", "ongoing", "resolved", "unknown"}
 
 		for _, severity := range severities {
 			for _, status := range statuses {
@@ -269,7 +348,7 @@ func (s *Server) updateBusinessMetrics(log *logrus.Entry) {
 	}
 
 	// Count planned maintenances by status
-	maintenances, err := s.storage.GetAllPlannedMaintenances()
+	maintenances, err := s.storage.GetAllPlannedMaintenances(ctx)
 	if err != nil {
 		log.WithError(err).Warn("Failed to retrieve planned maintenances for metrics - using default values")
 		// Set default values on error
@@ -278,10 +357,16 @@ func (s *Server) updateBusinessMetrics(log *logrus.Entry) {
 	} else {
 		// Count maintenances by status
 		maintenanceCounts := make(map[string]int)
+		overrunning := 0
+		now := time.Now()
 		for _, maintenance := range maintenances {
 			status := string(maintenance.Status())
 			maintenanceCounts[status]++
+			if maintenance.IsOverrunning(now) {
+				overrunning++
+			}
 		}
+		metrics.UpdateOverrunningMaintenances(float64(overrunning))
 
 		// Update metrics for common statuses
 		statuses := []string{"scheduled", "ongoing", "completed", "unknown"}
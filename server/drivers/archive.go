@@ -0,0 +1,429 @@
+package drivers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+)
+
+const (
+	// ArchiveFormat identifies the header line of an archive
+	ArchiveFormat = "clariti-archive"
+	// ArchiveVersion is bumped whenever the archive layout changes incompatibly
+	ArchiveVersion = 1
+)
+
+// Archive record types, one JSON object per line
+const (
+	archiveRecordHeader             = "header"
+	archiveRecordIncident           = "incident"
+	archiveRecordPlannedMaintenance = "planned_maintenance"
+	archiveRecordFooter             = "footer"
+)
+
+// archiveMaxLine bounds the size of a single archive line
+const archiveMaxLine = 16 * 1024 * 1024
+
+// ErrInvalidArchive is returned when an archive cannot be read
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// ImportMode selects how an archive is applied to a storage
+type ImportMode string
+
+const (
+	// ImportMerge creates the archived events missing from the storage and reports the ones that differ as conflicts
+	ImportMerge ImportMode = "merge"
+	// ImportReplace makes the storage match the archive: differing events are overwritten and events missing
+	// from the archive are moved to the trash
+	ImportReplace ImportMode = "replace"
+)
+
+// ArchiveHeader describes an archive, it is its first line
+type ArchiveHeader struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Generator  string    `json:"generator,omitempty"`
+}
+
+// Archive is a portable copy of every incident and planned maintenance, deleted ones included
+type Archive struct {
+	Header              ArchiveHeader
+	Incidents           []*event.Incident
+	PlannedMaintenances []*event.PlannedMaintenance
+}
+
+// archiveRecord is a line of an archive.
+// The counts of the footer let readers detect truncated archives.
+type archiveRecord struct {
+	Type string `json:"type"`
+	*ArchiveHeader
+	Data                json.RawMessage `json:"data,omitempty"`
+	Incidents           *int            `json:"incidents,omitempty"`
+	PlannedMaintenances *int            `json:"planned_maintenances,omitempty"`
+}
+
+// ImportConflict is an archived event that was not applied
+type ImportConflict struct {
+	GUID   string `json:"guid"`
+	Reason string `json:"reason"`
+}
+
+// ImportKindReport summarizes the import of one kind of events
+type ImportKindReport struct {
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Deleted   int              `json:"deleted"`
+	Unchanged int              `json:"unchanged"`
+	Conflicts []ImportConflict `json:"conflicts"`
+}
+
+// ImportReport summarizes the import of an archive
+type ImportReport struct {
+	Mode                ImportMode       `json:"mode"`
+	Incidents           ImportKindReport `json:"incidents"`
+	PlannedMaintenances ImportKindReport `json:"planned_maintenances"`
+}
+
+// ExportArchive reads every event of storage into an archive
+func ExportArchive(ctx context.Context, storage EventStorage, generator string) (*Archive, error) {
+	incidents, err := allIncidents(ctx, storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read incidents: %w", err)
+	}
+	maintenances, err := allPlannedMaintenances(ctx, storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read planned maintenances: %w", err)
+	}
+	return &Archive{
+		Header: ArchiveHeader{
+			Format:     ArchiveFormat,
+			Version:    ArchiveVersion,
+			ExportedAt: time.Now().UTC(),
+			Generator:  generator,
+		},
+		Incidents:           incidents,
+		PlannedMaintenances: maintenances,
+	}, nil
+}
+
+// WriteTo writes the archive as NDJSON: the header, one line per event, then a footer with the event counts
+func (a *Archive) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	encoder := json.NewEncoder(counter)
+
+	header := a.Header
+	if err := encoder.Encode(archiveRecord{Type: archiveRecordHeader, ArchiveHeader: &header}); err != nil {
+		return counter.n, err
+	}
+	for _, incident := range a.Incidents {
+		if err := writeArchiveEvent(encoder, archiveRecordIncident, incident); err != nil {
+			return counter.n, err
+		}
+	}
+	for _, pm := range a.PlannedMaintenances {
+		if err := writeArchiveEvent(encoder, archiveRecordPlannedMaintenance, pm); err != nil {
+			return counter.n, err
+		}
+	}
+	incidents, maintenances := len(a.Incidents), len(a.PlannedMaintenances)
+	err := encoder.Encode(archiveRecord{Type: archiveRecordFooter, Incidents: &incidents, PlannedMaintenances: &maintenances})
+	return counter.n, err
+}
+
+func writeArchiveEvent(encoder *json.Encoder, recordType string, evt interface{}) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return encoder.Encode(archiveRecord{Type: recordType, Data: data})
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ReadArchive reads a whole archive, checking its header and footer before any event gets applied.
+// Errors wrap ErrInvalidArchive.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), archiveMaxLine)
+
+	archive := &Archive{}
+	seen := map[string]bool{}
+	line, headerRead, footerRead := 0, false, false
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if footerRead {
+			return nil, fmt.Errorf("%w: line %d: data after the footer", ErrInvalidArchive, line)
+		}
+
+		var record archiveRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidArchive, line, err)
+		}
+		if !headerRead && record.Type != archiveRecordHeader {
+			return nil, fmt.Errorf("%w: line %d: expected the header first", ErrInvalidArchive, line)
+		}
+
+		switch record.Type {
+		case archiveRecordHeader:
+			if headerRead {
+				return nil, fmt.Errorf("%w: line %d: duplicate header", ErrInvalidArchive, line)
+			}
+			if record.ArchiveHeader == nil || record.Format != ArchiveFormat {
+				return nil, fmt.Errorf("%w: line %d: not a %s", ErrInvalidArchive, line, ArchiveFormat)
+			}
+			if record.Version < 1 || record.Version > ArchiveVersion {
+				return nil, fmt.Errorf("%w: unsupported archive version %d (supported: up to %d)", ErrInvalidArchive, record.Version, ArchiveVersion)
+			}
+			archive.Header = *record.ArchiveHeader
+			headerRead = true
+		case archiveRecordIncident:
+			var incident event.Incident
+			if err := readArchiveEvent(record.Type, record.Data, &incident.BaseEvent, &incident, seen); err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidArchive, line, err)
+			}
+			archive.Incidents = append(archive.Incidents, &incident)
+		case archiveRecordPlannedMaintenance:
+			var pm event.PlannedMaintenance
+			if err := readArchiveEvent(record.Type, record.Data, &pm.BaseEvent, &pm, seen); err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidArchive, line, err)
+			}
+			archive.PlannedMaintenances = append(archive.PlannedMaintenances, &pm)
+		case archiveRecordFooter:
+			if record.Incidents == nil || record.PlannedMaintenances == nil ||
+				*record.Incidents != len(archive.Incidents) || *record.PlannedMaintenances != len(archive.PlannedMaintenances) {
+				return nil, fmt.Errorf("%w: line %d: event counts do not match the footer", ErrInvalidArchive, line)
+			}
+			footerRead = true
+		default:
+			return nil, fmt.Errorf("%w: line %d: unknown record type %q", ErrInvalidArchive, line, record.Type)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	if !headerRead {
+		return nil, fmt.Errorf("%w: empty archive", ErrInvalidArchive)
+	}
+	if !footerRead {
+		return nil, fmt.Errorf("%w: missing footer, the archive is truncated", ErrInvalidArchive)
+	}
+	return archive, nil
+}
+
+// readArchiveEvent decodes the data of an event record, seen holds the events already read by type and GUID
+func readArchiveEvent(recordType string, data json.RawMessage, base *event.BaseEvent, evt interface{}, seen map[string]bool) error {
+	if len(data) == 0 {
+		return errors.New("missing event data")
+	}
+	if err := json.Unmarshal(data, evt); err != nil {
+		return err
+	}
+	if base.GUID == "" {
+		return errors.New("event without guid")
+	}
+	key := recordType + "/" + base.GUID
+	if seen[key] {
+		return fmt.Errorf("duplicate %s %s", recordType, base.GUID)
+	}
+	seen[key] = true
+	return nil
+}
+
+// archiveKind gives access to the storage of one kind of events
+type archiveKind[T any] struct {
+	name    string
+	base    func(T) *event.BaseEvent
+	clone   func(T) T
+	load    func() ([]T, error)
+	create  func(T) error
+	update  func(T) error
+	remove  func(guid string) error
+	restore func(guid string) error
+}
+
+// incidentKind gives access to the incidents of storage, deleted ones included
+func incidentKind(ctx context.Context, storage EventStorage) archiveKind[*event.Incident] {
+	return archiveKind[*event.Incident]{
+		name:  "incidents",
+		base:  func(i *event.Incident) *event.BaseEvent { return &i.BaseEvent },
+		clone: func(i *event.Incident) *event.Incident { c := *i; return &c },
+		load:  func() ([]*event.Incident, error) { return allIncidents(ctx, storage) },
+		create: func(i *event.Incident) error {
+			return storage.CreateIncident(ctx, i)
+		},
+		update: func(i *event.Incident) error {
+			return storage.UpdateIncident(ctx, i)
+		},
+		remove: func(guid string) error {
+			return storage.DeleteIncident(ctx, guid)
+		},
+		restore: func(guid string) error {
+			_, err := storage.RestoreIncident(ctx, guid)
+			return err
+		},
+	}
+}
+
+// plannedMaintenanceKind gives access to the planned maintenances of storage, deleted ones included
+func plannedMaintenanceKind(ctx context.Context, storage EventStorage) archiveKind[*event.PlannedMaintenance] {
+	return archiveKind[*event.PlannedMaintenance]{
+		name:  "planned_maintenances",
+		base:  func(pm *event.PlannedMaintenance) *event.BaseEvent { return &pm.BaseEvent },
+		clone: func(pm *event.PlannedMaintenance) *event.PlannedMaintenance { c := *pm; return &c },
+		load:  func() ([]*event.PlannedMaintenance, error) { return allPlannedMaintenances(ctx, storage) },
+		create: func(pm *event.PlannedMaintenance) error {
+			return storage.CreatePlannedMaintenance(ctx, pm)
+		},
+		update: func(pm *event.PlannedMaintenance) error {
+			return storage.UpdatePlannedMaintenance(ctx, pm)
+		},
+		remove: func(guid string) error {
+			return storage.DeletePlannedMaintenance(ctx, guid)
+		},
+		restore: func(guid string) error {
+			_, err := storage.RestorePlannedMaintenance(ctx, guid)
+			return err
+		},
+	}
+}
+
+// ImportArchive applies an archive to storage according to mode.
+// Created events keep their version and trash state, overwritten ones are stored at their next version.
+func ImportArchive(ctx context.Context, storage EventStorage, archive *Archive, mode ImportMode) (*ImportReport, error) {
+	if mode != ImportMerge && mode != ImportReplace {
+		return nil, fmt.Errorf("unsupported import mode: %s", mode)
+	}
+	report := &ImportReport{Mode: mode}
+
+	incidents := incidentKind(ctx, storage)
+	if err := importKind(incidents, archive.Incidents, mode, &report.Incidents); err != nil {
+		return report, err
+	}
+
+	maintenances := plannedMaintenanceKind(ctx, storage)
+	if err := importKind(maintenances, archive.PlannedMaintenances, mode, &report.PlannedMaintenances); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// importKind applies the archived events of one kind
+func importKind[T any](kind archiveKind[T], archived []T, mode ImportMode, report *ImportKindReport) error {
+	log := logger.GetDefault().WithComponent("Archive").WithField("kind", kind.name)
+	report.Conflicts = []ImportConflict{}
+
+	stored, err := kind.load()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", kind.name, err)
+	}
+	byGUID := make(map[string]T, len(stored))
+	for _, item := range stored {
+		byGUID[kind.base(item).GUID] = item
+	}
+
+	inArchive := make(map[string]bool, len(archived))
+	for _, item := range archived {
+		base := kind.base(item)
+		inArchive[base.GUID] = true
+
+		current, exists := byGUID[base.GUID]
+		if !exists {
+			if err := kind.create(item); err != nil {
+				return fmt.Errorf("failed to create %s %s: %w", kind.name, base.GUID, err)
+			}
+			report.Created++
+			continue
+		}
+
+		changes, err := event.Diff(current, item)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			report.Unchanged++
+			continue
+		}
+		if mode == ImportMerge {
+			fields := make([]string, len(changes))
+			for i, change := range changes {
+				fields[i] = change.Field
+			}
+			report.Conflicts = append(report.Conflicts, ImportConflict{
+				GUID: base.GUID,
+				Reason: fmt.Sprintf("differs from the stored event at version %d: %s",
+					kind.base(current).Version, strings.Join(fields, ", ")),
+			})
+			continue
+		}
+
+		if err := replaceEvent(kind, current, item); err != nil {
+			return fmt.Errorf("failed to replace %s %s: %w", kind.name, base.GUID, err)
+		}
+		report.Updated++
+	}
+
+	if mode == ImportReplace {
+		for guid, item := range byGUID {
+			if inArchive[guid] || kind.base(item).IsDeleted() {
+				continue
+			}
+			if err := kind.remove(guid); err != nil {
+				return fmt.Errorf("failed to delete %s %s: %w", kind.name, guid, err)
+			}
+			report.Deleted++
+		}
+	}
+
+	log.WithField("created", report.Created).WithField("updated", report.Updated).WithField("deleted", report.Deleted).
+		WithField("conflicts", len(report.Conflicts)).Info("Archive imported")
+	return nil
+}
+
+// replaceEvent overwrites a stored event with an archived one, moving it in or out of the trash as needed
+func replaceEvent[T any](kind archiveKind[T], current, archived T) error {
+	guid := kind.base(archived).GUID
+	if kind.base(current).IsDeleted() {
+		if err := kind.restore(guid); err != nil {
+			return err
+		}
+	}
+
+	// The archived content is written unconditionally, as a live event
+	replacement := kind.clone(archived)
+	base := kind.base(replacement)
+	base.Version = 0
+	base.DeletedAt = nil
+	base.DeletedBy = ""
+	if err := kind.update(replacement); err != nil {
+		return err
+	}
+
+	if kind.base(archived).IsDeleted() {
+		return kind.remove(guid)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+func TestArchive_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := newMigrationSource(t)
+
+	archive, err := ExportArchive(ctx, source, "clariti test")
+	if err != nil {
+		t.Fatalf("ExportArchive failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := archive.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 5 {
+		t.Errorf("Expected a header, 3 events and a footer, got %d lines", lines)
+	}
+
+	read, err := ReadArchive(&buf)
+	if err != nil {
+		t.Fatalf("ReadArchive failed: %v", err)
+	}
+	if read.Header.Version != ArchiveVersion || read.Header.Generator != "clariti test" {
+		t.Errorf("Expected the archive header, got %+v", read.Header)
+	}
+	if len(read.Incidents) != 2 || len(read.PlannedMaintenances) != 1 {
+		t.Fatalf("Expected 2 incidents and 1 planned maintenance, got %d and %d", len(read.Incidents), len(read.PlannedMaintenances))
+	}
+
+	// Importing into an empty storage recreates every event with its trash state
+	destination := NewRAMStorage()
+	report, err := ImportArchive(ctx, destination, read, ImportMerge)
+	if err != nil {
+		t.Fatalf("ImportArchive failed: %v", err)
+	}
+	if report.Incidents.Created != 2 || report.PlannedMaintenances.Created != 1 {
+		t.Errorf("Expected every event to be created, got %+v", report)
+	}
+	if trash, _ := destination.GetDeletedIncidents(ctx); len(trash) != 1 {
+		t.Errorf("Expected the deleted incident in the trash, got %d", len(trash))
+	}
+}
+
+func TestReadArchive_Invalid(t *testing.T) {
+	header := `{"type":"header","format":"clariti-archive","version":1,"exported_at":"2026-01-01T00:00:00Z"}`
+	incident := `{"type":"incident","data":{"guid":"a","title":"Outage"}}`
+	tests := []struct {
+		name    string
+		archive string
+	}{
+		{"empty", ""},
+		{"no header", incident},
+		{"other format", `{"type":"header","format":"other","version":1}`},
+		{"newer version", `{"type":"header","format":"clariti-archive","version":99}`},
+		{"truncated", header + "\n" + incident},
+		{"wrong counts", header + "\n" + incident + "\n" + `{"type":"footer","incidents":2,"planned_maintenances":0}`},
+		{"duplicate event", header + "\n" + incident + "\n" + incident + "\n" + `{"type":"footer","incidents":2,"planned_maintenances":0}`},
+		{"unknown record", header + "\n" + `{"type":"component"}`},
+		{"malformed", header + "\n{"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadArchive(strings.NewReader(tt.archive)); !errors.Is(err, ErrInvalidArchive) {
+				t.Errorf("Expected ErrInvalidArchive, got %v", err)
+			}
+		})
+	}
+}
+
+func TestImportArchive_Modes(t *testing.T) {
+	ctx := context.Background()
+	source := newMigrationSource(t)
+	archive, err := ExportArchive(ctx, source, "")
+	if err != nil {
+		t.Fatalf("ExportArchive failed: %v", err)
+	}
+	live, _ := source.GetAllIncidents(ctx)
+
+	for _, mode := range []ImportMode{ImportMerge, ImportReplace} {
+		t.Run(string(mode), func(t *testing.T) {
+			storage := NewRAMStorage()
+			if _, err := ImportArchive(ctx, storage, archive, ImportMerge); err != nil {
+				t.Fatalf("ImportArchive failed: %v", err)
+			}
+
+			// The storage drifts from the archive
+			edited := *live[0]
+			edited.Version = 0
+			edited.Title = "Edited after the backup"
+			if err := storage.UpdateIncident(ctx, &edited); err != nil {
+				t.Fatalf("UpdateIncident failed: %v", err)
+			}
+			extra := event.NewPlannedMaintenance("Extra", "Not in the backup", nil, time.Now(), time.Now().Add(time.Hour))
+			if err := storage.CreatePlannedMaintenance(ctx, extra); err != nil {
+				t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+			}
+
+			report, err := ImportArchive(ctx, storage, archive, mode)
+			if err != nil {
+				t.Fatalf("ImportArchive failed: %v", err)
+			}
+			got, _ := storage.GetIncident(ctx, live[0].GUID)
+			maintenances, _ := storage.GetAllPlannedMaintenances(ctx)
+
+			switch mode {
+			case ImportMerge:
+				if len(report.Incidents.Conflicts) != 1 || report.Incidents.Conflicts[0].GUID != live[0].GUID || report.Incidents.Unchanged != 1 {
+					t.Errorf("Expected the edited incident as the only conflict, got %+v", report.Incidents)
+				}
+				if got.Title != "Edited after the backup" || len(maintenances) != 2 {
+					t.Errorf("Expected merge to leave the storage untouched, got %q and %d maintenances", got.Title, len(maintenances))
+				}
+			case ImportReplace:
+				if report.Incidents.Updated != 1 || report.PlannedMaintenances.Deleted != 1 || len(report.Incidents.Conflicts) != 0 {
+					t.Errorf("Expected 1 updated incident and 1 deleted maintenance, got %+v", report)
+				}
+				if got.Title != live[0].Title || len(maintenances) != 1 {
+					t.Errorf("Expected replace to restore the archive, got %q and %d maintenances", got.Title, len(maintenances))
+				}
+			}
+		})
+	}
+}
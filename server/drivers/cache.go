@@ -0,0 +1,346 @@
+package drivers
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/config"
+	"github.com/gmllt/clariti/server/metrics"
+)
+
+const (
+	cacheKindIncidents           = "incidents"
+	cacheKindPlannedMaintenances = "planned_maintenances"
+)
+
+// CacheConfig holds the configuration of a CachedStorage
+type CacheConfig struct {
+	TTL        time.Duration
+	MaxEntries int // 0 uses config.DefaultCacheMaxEntries
+}
+
+// CachedStorage is a read-through cache in front of any EventStorage.
+// Reads are served from the cache for up to the TTL, and every write made through it invalidates the cached
+// reads of the written kind. Writes made by other servers sharing the storage are seen once the TTL expires,
+// as are status changes that only depend on time.
+//
+// Cached values are stored encoded, so callers may modify the events they get without altering the cache.
+type CachedStorage struct {
+	storage    EventStorage
+	ttl        time.Duration
+	maxEntries int
+
+	mu          sync.Mutex
+	entries     map[string]*list.Element
+	lru         *list.List // most recently used first
+	generations map[string]uint64
+}
+
+// cacheEntry is a cached read
+type cacheEntry struct {
+	key     string
+	kind    string
+	data    []byte
+	expires time.Time
+}
+
+// NewCachedStorage wraps storage in a read-through cache
+func NewCachedStorage(storage EventStorage, cfg CacheConfig) *CachedStorage {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = config.DefaultCacheMaxEntries
+	}
+
+	log := logger.GetDefault().WithComponent("CachedStorage")
+	log.WithField("ttl", cfg.TTL.String()).WithField("max_entries", cfg.MaxEntries).Info("Initializing storage cache")
+
+	return &CachedStorage{
+		storage:     storage,
+		ttl:         cfg.TTL,
+		maxEntries:  cfg.MaxEntries,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		generations: make(map[string]uint64),
+	}
+}
+
+// Unwrap returns the cached storage driver
+func (c *CachedStorage) Unwrap() EventStorage {
+	return c.storage
+}
+
+// Close closes the cached storage driver when it holds resources, such as a SQLite database or a RAM snapshot
+func (c *CachedStorage) Close() error {
+	if closer, ok := c.storage.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// lookup returns the cached data of a key, along with the generation of its kind to store a missed read with
+func (c *CachedStorage) lookup(kind, key string) ([]byte, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	generation := c.generations[kind]
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, generation, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(element)
+		metrics.UpdateStorageCacheEntries(len(c.entries))
+		return nil, generation, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.data, generation, true
+}
+
+// store caches the data of a key, unless a write invalidated its kind since the read started
+func (c *CachedStorage) store(kind, key string, generation uint64, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[kind] != generation {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:     key,
+		kind:    kind,
+		data:    data,
+		expires: time.Now().Add(c.ttl),
+	})
+	for len(c.entries) > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+	metrics.UpdateStorageCacheEntries(len(c.entries))
+}
+
+// invalidate drops the cached reads of the given kinds and discards the reads still in flight
+func (c *CachedStorage) invalidate(kinds ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, kind := range kinds {
+		c.generations[kind]++
+	}
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*cacheEntry)
+		for _, kind := range kinds {
+			if entry.kind == kind {
+				c.remove(element)
+				break
+			}
+		}
+		element = next
+	}
+	metrics.UpdateStorageCacheEntries(len(c.entries))
+}
+
+// remove drops an entry, the caller holds the lock
+func (c *CachedStorage) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+// cachedRead serves a read from the cache, or loads it from the storage driver and caches it
+func cachedRead[T any](ctx context.Context, c *CachedStorage, kind, key string, load func() (T, error)) (T, error) {
+	var value T
+	if err := ctx.Err(); err != nil {
+		return value, err
+	}
+
+	data, generation, ok := c.lookup(kind, key)
+	if ok {
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.RecordStorageCacheHit(kind)
+			return value, nil
+		}
+	}
+	metrics.RecordStorageCacheMiss(kind)
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		c.store(kind, key, generation, data)
+	} else {
+		logger.GetDefault().WithComponent("CachedStorage").WithError(err).WithField("key", key).Warn("Failed to cache read")
+	}
+	return value, nil
+}
+
+// cacheKey builds the key of a read
+func cacheKey(kind, operation, argument string) string {
+	return fmt.Sprintf("%s:%s:%s", kind, operation, argument)
+}
+
+// filterCacheKey builds the key of a filtered listing
+func filterCacheKey(kind string, filter EventFilter) (string, error) {
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return "", err
+	}
+	return cacheKey(kind, "list", string(encoded)), nil
+}
+
+// Incidents implementation
+func (c *CachedStorage) CreateIncident(ctx context.Context, incident *event.Incident) error {
+	defer c.invalidate(cacheKindIncidents)
+	return c.storage.CreateIncident(ctx, incident)
+}
+
+func (c *CachedStorage) GetIncident(ctx context.Context, id string) (*event.Incident, error) {
+	return cachedRead(ctx, c, cacheKindIncidents, cacheKey(cacheKindIncidents, "get", id), func() (*event.Incident, error) {
+		return c.storage.GetIncident(ctx, id)
+	})
+}
+
+func (c *CachedStorage) GetAllIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return cachedRead(ctx, c, cacheKindIncidents, cacheKey(cacheKindIncidents, "all", ""), func() ([]*event.Incident, error) {
+		return c.storage.GetAllIncidents(ctx)
+	})
+}
+
+func (c *CachedStorage) ListIncidents(ctx context.Context, filter EventFilter) (*IncidentPage, error) {
+	key, err := filterCacheKey(cacheKindIncidents, filter)
+	if err != nil {
+		return c.storage.ListIncidents(ctx, filter)
+	}
+	return cachedRead(ctx, c, cacheKindIncidents, key, func() (*IncidentPage, error) {
+		return c.storage.ListIncidents(ctx, filter)
+	})
+}
+
+func (c *CachedStorage) UpdateIncident(ctx context.Context, incident *event.Incident) error {
+	defer c.invalidate(cacheKindIncidents)
+	return c.storage.UpdateIncident(ctx, incident)
+}
+
+func (c *CachedStorage) DeleteIncident(ctx context.Context, id string) error {
+	defer c.invalidate(cacheKindIncidents)
+	return c.storage.DeleteIncident(ctx, id)
+}
+
+func (c *CachedStorage) GetDeletedIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return cachedRead(ctx, c, cacheKindIncidents, cacheKey(cacheKindIncidents, "deleted", ""), func() ([]*event.Incident, error) {
+		return c.storage.GetDeletedIncidents(ctx)
+	})
+}
+
+func (c *CachedStorage) RestoreIncident(ctx context.Context, id string) (*event.Incident, error) {
+	defer c.invalidate(cacheKindIncidents)
+	return c.storage.RestoreIncident(ctx, id)
+}
+
+func (c *CachedStorage) GetIncidentRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return cachedRead(ctx, c, cacheKindIncidents, cacheKey(cacheKindIncidents, "revisions", id), func() ([]*event.Revision, error) {
+		return c.storage.GetIncidentRevisions(ctx, id)
+	})
+}
+
+// GetActiveIncidents caches the active incidents of drivers able to select them, and all incidents otherwise
+func (c *CachedStorage) GetActiveIncidents(ctx context.Context) ([]*event.Incident, error) {
+	active, ok := c.storage.(ActiveEventStorage)
+	if !ok {
+		return c.GetAllIncidents(ctx)
+	}
+	return cachedRead(ctx, c, cacheKindIncidents, cacheKey(cacheKindIncidents, "active", ""), func() ([]*event.Incident, error) {
+		return active.GetActiveIncidents(ctx)
+	})
+}
+
+// Planned Maintenances implementation
+func (c *CachedStorage) CreatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	defer c.invalidate(cacheKindPlannedMaintenances)
+	return c.storage.CreatePlannedMaintenance(ctx, pm)
+}
+
+func (c *CachedStorage) GetPlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	return cachedRead(ctx, c, cacheKindPlannedMaintenances, cacheKey(cacheKindPlannedMaintenances, "get", id), func() (*event.PlannedMaintenance, error) {
+		return c.storage.GetPlannedMaintenance(ctx, id)
+	})
+}
+
+func (c *CachedStorage) GetAllPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return cachedRead(ctx, c, cacheKindPlannedMaintenances, cacheKey(cacheKindPlannedMaintenances, "all", ""), func() ([]*event.PlannedMaintenance, error) {
+		return c.storage.GetAllPlannedMaintenances(ctx)
+	})
+}
+
+func (c *CachedStorage) ListPlannedMaintenances(ctx context.Context, filter EventFilter) (*PlannedMaintenancePage, error) {
+	key, err := filterCacheKey(cacheKindPlannedMaintenances, filter)
+	if err != nil {
+		return c.storage.ListPlannedMaintenances(ctx, filter)
+	}
+	return cachedRead(ctx, c, cacheKindPlannedMaintenances, key, func() (*PlannedMaintenancePage, error) {
+		return c.storage.ListPlannedMaintenances(ctx, filter)
+	})
+}
+
+func (c *CachedStorage) UpdatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	defer c.invalidate(cacheKindPlannedMaintenances)
+	return c.storage.UpdatePlannedMaintenance(ctx, pm)
+}
+
+func (c *CachedStorage) DeletePlannedMaintenance(ctx context.Context, id string) error {
+	defer c.invalidate(cacheKindPlannedMaintenances)
+	return c.storage.DeletePlannedMaintenance(ctx, id)
+}
+
+func (c *CachedStorage) GetDeletedPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return cachedRead(ctx, c, cacheKindPlannedMaintenances, cacheKey(cacheKindPlannedMaintenances, "deleted", ""), func() ([]*event.PlannedMaintenance, error) {
+		return c.storage.GetDeletedPlannedMaintenances(ctx)
+	})
+}
+
+func (c *CachedStorage) RestorePlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	defer c.invalidate(cacheKindPlannedMaintenances)
+	return c.storage.RestorePlannedMaintenance(ctx, id)
+}
+
+func (c *CachedStorage) GetPlannedMaintenanceRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return cachedRead(ctx, c, cacheKindPlannedMaintenances, cacheKey(cacheKindPlannedMaintenances, "revisions", id), func() ([]*event.Revision, error) {
+		return c.storage.GetPlannedMaintenanceRevisions(ctx, id)
+	})
+}
+
+// GetActivePlannedMaintenances caches the active planned maintenances of drivers able to select them, and all planned maintenances otherwise
+func (c *CachedStorage) GetActivePlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	active, ok := c.storage.(ActiveEventStorage)
+	if !ok {
+		return c.GetAllPlannedMaintenances(ctx)
+	}
+	return cachedRead(ctx, c, cacheKindPlannedMaintenances, cacheKey(cacheKindPlannedMaintenances, "active", ""), func() ([]*event.PlannedMaintenance, error) {
+		return active.GetActivePlannedMaintenances(ctx)
+	})
+}
+
+// Trash implementation
+func (c *CachedStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	defer c.invalidate(cacheKindIncidents, cacheKindPlannedMaintenances)
+	return c.storage.PurgeDeleted(ctx, before)
+}
+
+// RebuildIndex rebuilds the index of drivers maintaining one, then clears the cached reads served from the previous index
+func (c *CachedStorage) RebuildIndex(ctx context.Context) (int, error) {
+	indexed, ok := c.storage.(IndexedEventStorage)
+	if !ok {
+		return 0, errors.New("cached storage driver does not maintain an index")
+	}
+	defer c.invalidate(cacheKindIncidents, cacheKindPlannedMaintenances)
+	return indexed.RebuildIndex(ctx)
+}
@@ -0,0 +1,193 @@
+package drivers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/config"
+)
+
+// countingStorage counts the reads reaching the wrapped driver
+type countingStorage struct {
+	EventStorage
+	gets int
+	alls int
+}
+
+func (s *countingStorage) GetIncident(ctx context.Context, id string) (*event.Incident, error) {
+	s.gets++
+	return s.EventStorage.GetIncident(ctx, id)
+}
+
+func (s *countingStorage) GetAllIncidents(ctx context.Context) ([]*event.Incident, error) {
+	s.alls++
+	return s.EventStorage.GetAllIncidents(ctx)
+}
+
+func TestCachedStorage_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStorage{EventStorage: NewRAMStorage()}
+	storage := NewCachedStorage(backend, CacheConfig{TTL: time.Minute})
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := storage.GetIncident(ctx, incident.GUID)
+		if err != nil {
+			t.Fatalf("GetIncident failed: %v", err)
+		}
+		// Callers modifying what they read must not alter the cache
+		got.Title = "Modified by caller"
+	}
+	if backend.gets != 1 {
+		t.Errorf("Expected a single read reaching the driver, got %d", backend.gets)
+	}
+	if got, _ := storage.GetIncident(ctx, incident.GUID); got.Title != "Outage" {
+		t.Errorf("Expected the cached title, got %q", got.Title)
+	}
+
+	// Writes through the cache invalidate the cached reads of their kind
+	if _, err := storage.GetAllIncidents(ctx); err != nil {
+		t.Fatalf("GetAllIncidents failed: %v", err)
+	}
+	updated := *incident
+	updated.Title = "Outage resolved"
+	if err := storage.UpdateIncident(ctx, &updated); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	got, err := storage.GetIncident(ctx, incident.GUID)
+	if err != nil || got.Title != "Outage resolved" {
+		t.Errorf("Expected the updated incident after a write, got %v", err)
+	}
+	if all, err := storage.GetAllIncidents(ctx); err != nil || len(all) != 1 || all[0].Title != "Outage resolved" {
+		t.Errorf("Expected the updated listing after a write, got %v", err)
+	}
+	if backend.gets != 2 || backend.alls != 2 {
+		t.Errorf("Expected the write to invalidate both reads, got %d gets and %d listings", backend.gets, backend.alls)
+	}
+
+	// Writes of another kind keep the cached incidents
+	pm := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+	if _, err := storage.GetIncident(ctx, incident.GUID); err != nil || backend.gets != 2 {
+		t.Errorf("Expected the incident to stay cached, got %d gets, %v", backend.gets, err)
+	}
+}
+
+func TestCachedStorage_Expiration(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStorage{EventStorage: NewRAMStorage()}
+	storage := NewCachedStorage(backend, CacheConfig{TTL: 10 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		if _, err := storage.GetAllIncidents(ctx); err != nil {
+			t.Fatalf("GetAllIncidents failed: %v", err)
+		}
+	}
+	if backend.alls != 1 {
+		t.Fatalf("Expected a cached listing, got %d listings", backend.alls)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, err := storage.GetAllIncidents(ctx); err != nil {
+		t.Fatalf("GetAllIncidents failed: %v", err)
+	}
+	if backend.alls != 2 {
+		t.Errorf("Expected an expired listing to be read again, got %d listings", backend.alls)
+	}
+}
+
+func TestCachedStorage_Eviction(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStorage{EventStorage: NewRAMStorage()}
+	storage := NewCachedStorage(backend, CacheConfig{TTL: time.Minute, MaxEntries: 2})
+
+	var guids []string
+	for i := 0; i < 3; i++ {
+		incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+		if err := storage.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+		guids = append(guids, incident.GUID)
+	}
+	for _, guid := range guids {
+		if _, err := storage.GetIncident(ctx, guid); err != nil {
+			t.Fatalf("GetIncident failed: %v", err)
+		}
+	}
+	if len(storage.entries) != 2 {
+		t.Errorf("Expected 2 cached entries, got %d", len(storage.entries))
+	}
+
+	// The least recently used incident was evicted
+	if _, err := storage.GetIncident(ctx, guids[2]); err != nil || backend.gets != 3 {
+		t.Errorf("Expected the last incident to be cached, got %d gets, %v", backend.gets, err)
+	}
+	if _, err := storage.GetIncident(ctx, guids[0]); err != nil || backend.gets != 4 {
+		t.Errorf("Expected the first incident to be evicted, got %d gets, %v", backend.gets, err)
+	}
+}
+
+// indexedStorage is a driver maintaining an index
+type indexedStorage struct {
+	*countingStorage
+	rebuilds int
+}
+
+func (s *indexedStorage) RebuildIndex(ctx context.Context) (int, error) {
+	s.rebuilds++
+	return 1, nil
+}
+
+func TestCachedStorage_RebuildIndex(t *testing.T) {
+	ctx := context.Background()
+	backend := &indexedStorage{countingStorage: &countingStorage{EventStorage: NewRAMStorage()}}
+	storage := NewCachedStorage(backend, CacheConfig{TTL: time.Minute})
+
+	if _, err := storage.GetAllIncidents(ctx); err != nil {
+		t.Fatalf("GetAllIncidents failed: %v", err)
+	}
+	if count, err := storage.RebuildIndex(ctx); err != nil || count != 1 || backend.rebuilds != 1 {
+		t.Fatalf("Expected the rebuild to reach the driver, got %d, %v", count, err)
+	}
+	// Reads cached before the rebuild may come from the previous index
+	if _, err := storage.GetAllIncidents(ctx); err != nil || backend.alls != 2 {
+		t.Errorf("Expected the rebuild to clear the cache, got %d listings, %v", backend.alls, err)
+	}
+
+	uncached := NewCachedStorage(NewRAMStorage(), CacheConfig{TTL: time.Minute})
+	if _, err := uncached.RebuildIndex(ctx); err == nil {
+		t.Error("Expected an error for a driver without index")
+	}
+}
+
+func TestNewStorage_Cached(t *testing.T) {
+	cfg := &config.Config{
+		Storage: config.StorageConfig{
+			Driver: "ram",
+			Cache: config.CacheConfig{
+				TTL: time.Minute,
+			},
+		},
+	}
+
+	storage, err := NewStorage(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cached, ok := storage.(*CachedStorage)
+	if !ok {
+		t.Fatalf("expected CachedStorage, got %T", storage)
+	}
+	if _, ok := cached.Unwrap().(*RAMStorage); !ok {
+		t.Fatalf("expected a cached RAMStorage, got %T", cached.Unwrap())
+	}
+}
@@ -0,0 +1,62 @@
+package drivers_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/server/drivers"
+	"github.com/gmllt/clariti/server/drivers/drivertest"
+)
+
+// TestConformance runs the conformance suite against every built-in driver, S3 against a fake server
+func TestConformance(t *testing.T) {
+	for _, driver := range []struct {
+		name    string
+		storage drivertest.NewStorage
+	}{
+		{"ram", func(t *testing.T) drivers.EventStorage { return drivers.NewRAMStorage() }},
+		{"ram-snapshot", func(t *testing.T) drivers.EventStorage {
+			storage, err := drivers.NewRAMStorageWithSnapshot(drivers.RAMConfig{
+				SnapshotPath:     filepath.Join(t.TempDir(), "snapshot.json"),
+				SnapshotInterval: time.Hour,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create RAM storage: %v", err)
+			}
+			t.Cleanup(func() { storage.Close() })
+			return storage
+		}},
+		{"file", func(t *testing.T) drivers.EventStorage {
+			storage, err := drivers.NewFileStorage(drivers.FileConfig{Path: t.TempDir()})
+			if err != nil {
+				t.Fatalf("Failed to create file storage: %v", err)
+			}
+			return storage
+		}},
+		{"sqlite", func(t *testing.T) drivers.EventStorage {
+			storage, err := drivers.NewSQLiteStorage(drivers.SQLiteConfig{Path: filepath.Join(t.TempDir(), "clariti.db")})
+			if err != nil {
+				t.Fatalf("Failed to create SQLite storage: %v", err)
+			}
+			t.Cleanup(func() { storage.Close() })
+			return storage
+		}},
+		{"s3", func(t *testing.T) drivers.EventStorage { return drivers.NewTestS3Storage(t, nil) }},
+		{"s3-encrypted", func(t *testing.T) drivers.EventStorage {
+			keyring, err := drivers.ParseKeyring("primary:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+			if err != nil {
+				t.Fatalf("Failed to parse keyring: %v", err)
+			}
+			return drivers.NewTestS3Storage(t, keyring)
+		}},
+		{"cached", func(t *testing.T) drivers.EventStorage {
+			return drivers.NewCachedStorage(drivers.NewRAMStorage(), drivers.CacheConfig{TTL: time.Minute})
+		}},
+		{"mirror", func(t *testing.T) drivers.EventStorage {
+			return drivers.NewMirrorStorage(drivers.NewRAMStorage(), drivers.NewRAMStorage())
+		}},
+	} {
+		t.Run(driver.name, func(t *testing.T) { drivertest.Run(t, driver.storage) })
+	}
+}
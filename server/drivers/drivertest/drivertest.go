@@ -0,0 +1,659 @@
+// Package drivertest provides a conformance suite for drivers.EventStorage implementations.
+//
+// A driver runs the suite from its own tests by passing a function creating an empty storage:
+//
+//	func TestConformance(t *testing.T) {
+//		drivertest.Run(t, func(t *testing.T) drivers.EventStorage {
+//			return NewMyStorage(t.TempDir())
+//		})
+//	}
+package drivertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// NewStorage returns an empty storage for a single test, releasing its resources with t.Cleanup
+type NewStorage func(t *testing.T) drivers.EventStorage
+
+// author is the author the suite attributes its writes to
+const author = "drivertest"
+
+// concurrency is the number of goroutines writing at once in the concurrent access tests
+const concurrency = 8
+
+// storageFields are the event fields set by the storage rather than by the caller
+var storageFields = map[string]bool{"version": true, "deleted_at": true, "deleted_by": true}
+
+// Run runs the conformance suite against the storages created by newStorage, each subtest getting its own one
+func Run(t *testing.T, newStorage NewStorage) {
+	t.Run("Incidents", func(t *testing.T) { runKind(t, newStorage, incidents) })
+	t.Run("PlannedMaintenances", func(t *testing.T) { runKind(t, newStorage, plannedMaintenances) })
+	t.Run("PurgeDeleted", func(t *testing.T) { testPurgeDeleted(t, newStorage(t)) })
+}
+
+// kind adapts the storage methods of one kind of events so that both kinds share the suite
+type kind[T any] struct {
+	newEvent   func(title string) T // Event with every caller field set
+	base       func(T) *event.BaseEvent
+	clone      func(T) T
+	create     func(context.Context, drivers.EventStorage, T) error
+	get        func(context.Context, drivers.EventStorage, string) (T, error)
+	getAll     func(context.Context, drivers.EventStorage) ([]T, error)
+	update     func(context.Context, drivers.EventStorage, T) error
+	remove     func(context.Context, drivers.EventStorage, string) error
+	getDeleted func(context.Context, drivers.EventStorage) ([]T, error)
+	restore    func(context.Context, drivers.EventStorage, string) (T, error)
+	revisions  func(context.Context, drivers.EventStorage, string) ([]*event.Revision, error)
+}
+
+var incidents = kind[*event.Incident]{
+	newEvent: newIncident,
+	base:     func(i *event.Incident) *event.BaseEvent { return &i.BaseEvent },
+	clone:    func(i *event.Incident) *event.Incident { c := *i; return &c },
+	create: func(ctx context.Context, s drivers.EventStorage, i *event.Incident) error {
+		return s.CreateIncident(ctx, i)
+	},
+	get: func(ctx context.Context, s drivers.EventStorage, id string) (*event.Incident, error) {
+		return s.GetIncident(ctx, id)
+	},
+	getAll: func(ctx context.Context, s drivers.EventStorage) ([]*event.Incident, error) {
+		return s.GetAllIncidents(ctx)
+	},
+	update: func(ctx context.Context, s drivers.EventStorage, i *event.Incident) error {
+		return s.UpdateIncident(ctx, i)
+	},
+	remove: func(ctx context.Context, s drivers.EventStorage, id string) error { return s.DeleteIncident(ctx, id) },
+	getDeleted: func(ctx context.Context, s drivers.EventStorage) ([]*event.Incident, error) {
+		return s.GetDeletedIncidents(ctx)
+	},
+	restore: func(ctx context.Context, s drivers.EventStorage, id string) (*event.Incident, error) {
+		return s.RestoreIncident(ctx, id)
+	},
+	revisions: func(ctx context.Context, s drivers.EventStorage, id string) ([]*event.Revision, error) {
+		return s.GetIncidentRevisions(ctx, id)
+	},
+}
+
+var plannedMaintenances = kind[*event.PlannedMaintenance]{
+	newEvent: newPlannedMaintenance,
+	base:     func(pm *event.PlannedMaintenance) *event.BaseEvent { return &pm.BaseEvent },
+	clone:    func(pm *event.PlannedMaintenance) *event.PlannedMaintenance { c := *pm; return &c },
+	create: func(ctx context.Context, s drivers.EventStorage, pm *event.PlannedMaintenance) error {
+		return s.CreatePlannedMaintenance(ctx, pm)
+	},
+	get: func(ctx context.Context, s drivers.EventStorage, id string) (*event.PlannedMaintenance, error) {
+		return s.GetPlannedMaintenance(ctx, id)
+	},
+	getAll: func(ctx context.Context, s drivers.EventStorage) ([]*event.PlannedMaintenance, error) {
+		return s.GetAllPlannedMaintenances(ctx)
+	},
+	update: func(ctx context.Context, s drivers.EventStorage, pm *event.PlannedMaintenance) error {
+		return s.UpdatePlannedMaintenance(ctx, pm)
+	},
+	remove: func(ctx context.Context, s drivers.EventStorage, id string) error {
+		return s.DeletePlannedMaintenance(ctx, id)
+	},
+	getDeleted: func(ctx context.Context, s drivers.EventStorage) ([]*event.PlannedMaintenance, error) {
+		return s.GetDeletedPlannedMaintenances(ctx)
+	},
+	restore: func(ctx context.Context, s drivers.EventStorage, id string) (*event.PlannedMaintenance, error) {
+		return s.RestorePlannedMaintenance(ctx, id)
+	},
+	revisions: func(ctx context.Context, s drivers.EventStorage, id string) ([]*event.Revision, error) {
+		return s.GetPlannedMaintenanceRevisions(ctx, id)
+	},
+}
+
+// at returns a fixed time with a nanosecond part, to catch drivers losing precision or time zones
+func at(hours int) *time.Time {
+	t := time.Date(2026, time.March, 14, 9, 26, 53, 589793238, time.UTC).Add(time.Duration(hours) * time.Hour)
+	return &t
+}
+
+func components() []*component.Component {
+	platform := component.NewPlatform("Production", "prod")
+	instance := component.NewInstance("Europe", "eu", platform)
+	return []*component.Component{
+		component.NewComponent("API", "api", instance),
+		component.NewComponent("Dashboard", "dashboard", instance),
+	}
+}
+
+func newIncident(title string) *event.Incident {
+	incident := event.NewKnownIssue(title, "Requests to the API time out", components(), event.CriticalityPartialOutage)
+	incident.ExtraFields["ticket"] = "OPS-1234"
+	incident.Labels = map[string]string{"team": "platform", "example.com/source": "alertmanager"}
+	incident.ComponentCriticalities = map[string]event.Criticality{"api": event.CriticalityMajorOutage}
+	if err := incident.Transition(event.StatusInvestigating, "We are looking into it", "oncall", *at(0)); err != nil {
+		panic(err)
+	}
+	incident.PostUpdate("The load balancer drops connections", "oncall", *at(1))
+	if err := incident.Acknowledge("oncall", *at(1)); err != nil {
+		panic(err)
+	}
+	if err := incident.Transition(event.StatusResolved, "Requests succeed again", "oncall", *at(2)); err != nil {
+		panic(err)
+	}
+	// Set directly for the round trip, known issues get no postmortem through SetPostmortem
+	incident.Postmortem = &event.Postmortem{
+		Summary:             "The API timed out for two hours",
+		RootCause:           "The load balancer ran out of connections",
+		ContributingFactors: []string{"No connection limit alert"},
+		ActionItems:         []event.ActionItem{{Description: "Alert on connection usage", Owner: "oncall", DueDate: at(48), Done: true}},
+		Published:           true,
+		PublishedAt:         at(3),
+		Author:              "oncall",
+		UpdatedAt:           *at(3),
+	}
+	return incident
+}
+
+func newPlannedMaintenance(title string) *event.PlannedMaintenance {
+	pm := event.NewPlannedMaintenanceWithTimes(title, "The database is upgraded", components(), *at(24), *at(26), at(24), at(25))
+	pm.ExtraFields["change"] = "CHG-5678"
+	pm.Labels = map[string]string{"team": "dba"}
+	// Every field is set for the round trip, a real maintenance is either completed or cancelled
+	pm.StartedBy, pm.CompletedBy = "dba", "dba"
+	pm.Cancelled, pm.CancelledAt, pm.CancelledBy = true, at(23), "dba"
+	pm.PostUpdate("The upgrade is postponed", "dba", *at(23))
+	pm.AutoStart, pm.AutoComplete, pm.OverrunAt = true, true, at(25)
+	pm.Recurrence = "FREQ=WEEKLY;COUNT=4"
+	movedStart, movedEnd := at(24).AddDate(0, 0, 8), at(26).AddDate(0, 0, 8)
+	pm.Overrides = []event.OccurrenceOverride{{RecurrenceID: at(24).AddDate(0, 0, 7), Cancelled: true, StartPlanned: &movedStart, EndPlanned: &movedEnd}}
+	return pm
+}
+
+func runKind[T any](t *testing.T, newStorage NewStorage, k kind[T]) {
+	t.Run("RoundTrip", func(t *testing.T) { testRoundTrip(t, newStorage(t), k) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, newStorage(t), k) })
+	t.Run("Exists", func(t *testing.T) { testExists(t, newStorage(t), k) })
+	t.Run("Versions", func(t *testing.T) { testVersions(t, newStorage(t), k) })
+	t.Run("GetAll", func(t *testing.T) { testGetAll(t, newStorage(t), k) })
+	t.Run("Trash", func(t *testing.T) { testTrash(t, newStorage(t), k) })
+	t.Run("Revisions", func(t *testing.T) { testRevisions(t, newStorage(t), k) })
+	t.Run("Concurrent", func(t *testing.T) { testConcurrent(t, newStorage(t), k) })
+	t.Run("CanceledContext", func(t *testing.T) { testCanceledContext(t, newStorage(t), k) })
+}
+
+func testRoundTrip[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	original := k.newEvent("Round trip")
+	requireComplete(t, original)
+
+	if err := k.create(ctx, storage, original); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	stored, err := k.get(ctx, storage, k.base(original).GUID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	requireSameJSON(t, "created event", original, stored)
+
+	all, err := k.getAll(ctx, storage)
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(all))
+	}
+	requireSameJSON(t, "listed event", original, all[0])
+
+	updated := k.clone(stored)
+	k.base(updated).Title = "Round trip, updated"
+	k.base(updated).ExtraFields = map[string]string{"ticket": "OPS-4321", "owner": "sre"}
+	k.base(updated).EndEffective = at(3)
+	if err := k.update(ctx, storage, updated); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	stored, err = k.get(ctx, storage, k.base(original).GUID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	requireSameJSON(t, "updated event", updated, stored)
+}
+
+func testNotFound[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	missing := k.newEvent("Missing")
+	id := k.base(missing).GUID
+
+	if _, err := k.get(ctx, storage, id); err != drivers.ErrNotFound {
+		t.Errorf("Get of a missing event: expected ErrNotFound, got %v", err)
+	}
+	if err := k.update(ctx, storage, missing); err != drivers.ErrNotFound {
+		t.Errorf("Update of a missing event: expected ErrNotFound, got %v", err)
+	}
+	if err := k.remove(ctx, storage, id); err != drivers.ErrNotFound {
+		t.Errorf("Delete of a missing event: expected ErrNotFound, got %v", err)
+	}
+	if _, err := k.restore(ctx, storage, id); err != drivers.ErrNotFound {
+		t.Errorf("Restore of a missing event: expected ErrNotFound, got %v", err)
+	}
+
+	live := k.newEvent("Live")
+	if err := k.create(ctx, storage, live); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := k.restore(ctx, storage, k.base(live).GUID); err != drivers.ErrNotFound {
+		t.Errorf("Restore of a live event: expected ErrNotFound, got %v", err)
+	}
+
+	if err := k.remove(ctx, storage, k.base(live).GUID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := k.get(ctx, storage, k.base(live).GUID); err != drivers.ErrNotFound {
+		t.Errorf("Get of a deleted event: expected ErrNotFound, got %v", err)
+	}
+	stale := k.clone(live)
+	k.base(stale).Version = 0
+	if err := k.update(ctx, storage, stale); err != drivers.ErrNotFound {
+		t.Errorf("Update of a deleted event: expected ErrNotFound, got %v", err)
+	}
+	if err := k.remove(ctx, storage, k.base(live).GUID); err != drivers.ErrNotFound {
+		t.Errorf("Delete of a deleted event: expected ErrNotFound, got %v", err)
+	}
+}
+
+func testExists[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	item := k.newEvent("Existing")
+	if err := k.create(ctx, storage, item); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	duplicate := k.clone(item)
+	k.base(duplicate).Title = "Duplicate"
+	k.base(duplicate).Version = 0
+	if err := k.create(ctx, storage, duplicate); err != drivers.ErrExists {
+		t.Errorf("Create of an existing event: expected ErrExists, got %v", err)
+	}
+	if stored, err := k.get(ctx, storage, k.base(item).GUID); err != nil || k.base(stored).Title != "Existing" {
+		t.Errorf("Expected the existing event kept, got %v", err)
+	}
+
+	if err := k.remove(ctx, storage, k.base(item).GUID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := k.create(ctx, storage, duplicate); err != drivers.ErrExists {
+		t.Errorf("Create of a deleted event: expected ErrExists, got %v", err)
+	}
+}
+
+func testVersions[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	item := k.newEvent("Versioned")
+	if err := k.create(ctx, storage, item); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if v := k.base(item).Version; v != 1 {
+		t.Fatalf("Expected version 1 after create, got %d", v)
+	}
+
+	if err := k.update(ctx, storage, item); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if v := k.base(item).Version; v != 2 {
+		t.Errorf("Expected version 2 set on the updated event, got %d", v)
+	}
+
+	stale := k.clone(item)
+	k.base(stale).Version = 1
+	k.base(stale).Title = "Stale"
+	if err := k.update(ctx, storage, stale); err != drivers.ErrVersionConflict {
+		t.Errorf("Update at a stale version: expected ErrVersionConflict, got %v", err)
+	}
+	stored, err := k.get(ctx, storage, k.base(item).GUID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if k.base(stored).Title == "Stale" || k.base(stored).Version != 2 {
+		t.Errorf("Expected a conflicting update not to be stored, got %q at version %d", k.base(stored).Title, k.base(stored).Version)
+	}
+
+	unconditional := k.clone(item)
+	k.base(unconditional).Version = 0
+	if err := k.update(ctx, storage, unconditional); err != nil {
+		t.Fatalf("Unconditional update failed: %v", err)
+	}
+	if v := k.base(unconditional).Version; v != 3 {
+		t.Errorf("Expected version 3 after an unconditional update, got %d", v)
+	}
+
+	migrated := k.newEvent("Migrated")
+	k.base(migrated).Version = 7
+	if err := k.create(ctx, storage, migrated); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if stored, err := k.get(ctx, storage, k.base(migrated).GUID); err != nil || k.base(stored).Version != 7 {
+		t.Errorf("Expected an event created at version 7 to keep it, got %v", err)
+	}
+}
+
+func testGetAll[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	if all, err := k.getAll(ctx, storage); err != nil || all == nil || len(all) != 0 {
+		t.Errorf("Expected an empty, non-nil list from an empty storage, got %v, %v", all, err)
+	}
+	if deleted, err := k.getDeleted(ctx, storage); err != nil || deleted == nil || len(deleted) != 0 {
+		t.Errorf("Expected an empty, non-nil trash from an empty storage, got %v, %v", deleted, err)
+	}
+
+	var guids []string
+	for i := 0; i < 6; i++ {
+		item := k.newEvent(fmt.Sprintf("Event %d", i))
+		if err := k.create(ctx, storage, item); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		guids = append(guids, k.base(item).GUID)
+	}
+	if err := k.remove(ctx, storage, guids[0]); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	live := append([]string(nil), guids[1:]...)
+	sort.Strings(live)
+
+	all, err := k.getAll(ctx, storage)
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	got := make([]string, len(all))
+	for i, item := range all {
+		got[i] = k.base(item).GUID
+	}
+	if !reflect.DeepEqual(got, live) {
+		t.Errorf("Expected the live events ordered by GUID %v, got %v", live, got)
+	}
+}
+
+func testTrash[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	first, second := k.newEvent("First"), k.newEvent("Second")
+	for _, item := range []T{first, second} {
+		if err := k.create(ctx, storage, item); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	written := k.clone(first)
+	for _, item := range []T{first, second} {
+		if err := k.remove(ctx, storage, k.base(item).GUID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		// Deletion times must differ for the trash order to be checked
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	deleted, err := k.getDeleted(ctx, storage)
+	if err != nil {
+		t.Fatalf("GetDeleted failed: %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Fatalf("Expected 2 events in the trash, got %d", len(deleted))
+	}
+	if k.base(deleted[0]).GUID != k.base(second).GUID {
+		t.Errorf("Expected the trash ordered most recently deleted first")
+	}
+	for _, item := range deleted {
+		base := k.base(item)
+		if base.DeletedAt == nil || base.DeletedBy != author || base.Version != 2 {
+			t.Errorf("Expected %s deleted by %s at version 2, got %v by %q at version %d",
+				base.GUID, author, base.DeletedAt, base.DeletedBy, base.Version)
+		}
+	}
+	withoutTrash := k.clone(deleted[1])
+	k.base(withoutTrash).Version = k.base(written).Version
+	k.base(withoutTrash).DeletedAt, k.base(withoutTrash).DeletedBy = nil, ""
+	requireSameJSON(t, "deleted event", written, withoutTrash)
+
+	restored, err := k.restore(ctx, storage, k.base(first).GUID)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if base := k.base(restored); base.IsDeleted() || base.DeletedBy != "" || base.Version != 3 {
+		t.Errorf("Expected the restored event out of the trash at version 3, got version %d", base.Version)
+	}
+	if stored, err := k.get(ctx, storage, k.base(first).GUID); err != nil {
+		t.Errorf("Expected the restored event readable, got %v", err)
+	} else {
+		requireSameJSON(t, "restored event", restored, stored)
+	}
+	if deleted, _ := k.getDeleted(ctx, storage); len(deleted) != 1 {
+		t.Errorf("Expected 1 event left in the trash, got %d", len(deleted))
+	}
+
+	trashed := k.newEvent("Trashed")
+	k.base(trashed).DeletedAt = at(-1)
+	k.base(trashed).DeletedBy = "migration"
+	if err := k.create(ctx, storage, trashed); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := k.get(ctx, storage, k.base(trashed).GUID); err != drivers.ErrNotFound {
+		t.Errorf("Expected an event created with DeletedAt set to go to the trash, got %v", err)
+	}
+}
+
+func testRevisions[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	item := k.newEvent("Revised")
+	id := k.base(item).GUID
+	if revisions, err := k.revisions(ctx, storage, id); err != nil || revisions == nil || len(revisions) != 0 {
+		t.Errorf("Expected an empty, non-nil history for an unknown event, got %v, %v", revisions, err)
+	}
+
+	if err := k.create(ctx, storage, item); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	updated := k.clone(item)
+	k.base(updated).Title = "Revised, updated"
+	if err := k.update(ctx, storage, updated); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := k.remove(ctx, storage, id); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := k.restore(ctx, storage, id); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	revisions, err := k.revisions(ctx, storage, id)
+	if err != nil {
+		t.Fatalf("GetRevisions failed: %v", err)
+	}
+	want := []event.RevisionAction{event.RevisionCreated, event.RevisionUpdated, event.RevisionDeleted, event.RevisionRestored}
+	if len(revisions) != len(want) {
+		t.Fatalf("Expected %d revisions, got %d", len(want), len(revisions))
+	}
+	for i, revision := range revisions {
+		if revision.Action != want[i] || revision.Version != int64(i+1) || revision.Author != author || revision.EventGUID != id {
+			t.Errorf("Revision %d: expected %s at version %d by %s, got %s at version %d by %q",
+				i, want[i], i+1, author, revision.Action, revision.Version, revision.Author)
+		}
+	}
+	if changes := revisions[1].Changes; len(changes) != 1 || changes[0].Field != "title" {
+		t.Errorf("Expected the update to record the title change only, got %+v", changes)
+	}
+}
+
+func testConcurrent[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+
+	// Concurrent creations of distinct events, interleaved with reads, all succeed
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- k.create(ctx, storage, k.newEvent(fmt.Sprintf("Concurrent %d", i)))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := k.getAll(ctx, storage)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Concurrent access failed: %v", err)
+		}
+	}
+	all, err := k.getAll(ctx, storage)
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(all) != concurrency {
+		t.Fatalf("Expected %d events after concurrent creations, got %d", concurrency, len(all))
+	}
+
+	// Concurrent updates from the same version: exactly one wins, the others conflict
+	contended, version := all[0], k.base(all[0]).Version
+	results := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		update := k.clone(contended)
+		k.base(update).Title = fmt.Sprintf("Update %d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- k.update(ctx, storage, update)
+		}()
+	}
+	wg.Wait()
+	close(results)
+	won := 0
+	for err := range results {
+		switch err {
+		case nil:
+			won++
+		case drivers.ErrVersionConflict:
+		default:
+			t.Errorf("Concurrent update failed: %v", err)
+		}
+	}
+	if won != 1 {
+		t.Errorf("Expected exactly 1 concurrent update to succeed, got %d", won)
+	}
+	stored, err := k.get(ctx, storage, k.base(contended).GUID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if v := k.base(stored).Version; v != version+1 {
+		t.Errorf("Expected version %d after concurrent updates, got %d", version+1, v)
+	}
+}
+
+func testCanceledContext[T any](t *testing.T, storage drivers.EventStorage, k kind[T]) {
+	ctx, cancel := context.WithCancel(drivers.WithAuthor(context.Background(), author))
+	cancel()
+
+	item := k.newEvent("Canceled")
+	if err := k.create(ctx, storage, item); err == nil {
+		t.Errorf("Expected Create to fail with a canceled context")
+	}
+	if _, err := k.getAll(ctx, storage); err == nil {
+		t.Errorf("Expected GetAll to fail with a canceled context")
+	}
+	if _, err := k.get(context.Background(), storage, k.base(item).GUID); err != drivers.ErrNotFound {
+		t.Errorf("Expected a canceled Create not to store the event, got %v", err)
+	}
+}
+
+func testPurgeDeleted(t *testing.T, storage drivers.EventStorage) {
+	ctx := drivers.WithAuthor(context.Background(), author)
+	incident, pm := newIncident("Purged"), newPlannedMaintenance("Purged")
+	kept := newIncident("Kept")
+	for _, create := range []func() error{
+		func() error { return storage.CreateIncident(ctx, incident) },
+		func() error { return storage.CreatePlannedMaintenance(ctx, pm) },
+		func() error { return storage.CreateIncident(ctx, kept) },
+		func() error { return storage.DeleteIncident(ctx, incident.GUID) },
+		func() error { return storage.DeletePlannedMaintenance(ctx, pm.GUID) },
+	} {
+		if err := create(); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	if purged, err := storage.PurgeDeleted(ctx, time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Errorf("Expected nothing deleted an hour ago to purge, got %d, %v", purged, err)
+	}
+	purged, err := storage.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("PurgeDeleted failed: %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("Expected 2 events purged, got %d", purged)
+	}
+
+	if deleted, _ := storage.GetDeletedIncidents(ctx); len(deleted) != 0 {
+		t.Errorf("Expected the incident trash empty, got %d", len(deleted))
+	}
+	if deleted, _ := storage.GetDeletedPlannedMaintenances(ctx); len(deleted) != 0 {
+		t.Errorf("Expected the planned maintenance trash empty, got %d", len(deleted))
+	}
+	if _, err := storage.RestoreIncident(ctx, incident.GUID); err != drivers.ErrNotFound {
+		t.Errorf("Restore of a purged event: expected ErrNotFound, got %v", err)
+	}
+	if _, err := storage.GetIncident(ctx, kept.GUID); err != nil {
+		t.Errorf("Expected the live incident kept, got %v", err)
+	}
+	if revisions, err := storage.GetIncidentRevisions(ctx, incident.GUID); err != nil || len(revisions) != 2 {
+		t.Errorf("Expected the history of a purged event kept, got %d revisions, %v", len(revisions), err)
+	}
+}
+
+// requireComplete fails unless every caller field of the event is set, so that new model fields get covered
+func requireComplete(t *testing.T, item interface{}) {
+	t.Helper()
+	var missing []string
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				walk(v.Field(i))
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if !storageFields[name] && v.Field(i).IsZero() {
+				missing = append(missing, name)
+			}
+		}
+	}
+	walk(reflect.ValueOf(item).Elem())
+	if len(missing) > 0 {
+		t.Fatalf("The %T fixture leaves %v unset, extend it to round-trip every field", item, missing)
+	}
+}
+
+// requireSameJSON fails when two events have different documents
+func requireSameJSON(t *testing.T, what string, want, got interface{}) {
+	t.Helper()
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Failed to encode %s: %v", what, err)
+	}
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Failed to encode %s: %v", what, err)
+	}
+	if string(wantJSON) != string(gotJSON) {
+		t.Errorf("The %s differs from the one written\nwant: %s\ngot:  %s", what, wantJSON, gotJSON)
+	}
+}
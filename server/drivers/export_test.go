@@ -0,0 +1,10 @@
+package drivers
+
+import "testing"
+
+// NewTestS3Storage exposes to the external tests an S3 driver backed by a fake server, encrypting objects when
+// keyring is not nil
+func NewTestS3Storage(t *testing.T, keyring *Keyring) *S3Storage {
+	t.Helper()
+	return newFakeS3(t).storage(t, keyring)
+}
@@ -2,15 +2,94 @@ package drivers
 
 import (
 	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/gmllt/clariti/server/config"
 )
 
-// NewStorage creates a new storage driver based on the configuration
+// NewStorage creates a new storage driver based on the configuration, wrapped in a cache when one is configured
 func NewStorage(cfg *config.Config) (EventStorage, error) {
+	storage, err := newDriver(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cacheConfig := cfg.GetCacheConfig(); cacheConfig.Enabled() {
+		return NewCachedStorage(storage, CacheConfig{
+			TTL:        cacheConfig.TTL,
+			MaxEntries: cacheConfig.MaxEntries,
+		}), nil
+	}
+	return storage, nil
+}
+
+// archiveLocation names the prefix and directory of the archive storage
+const archiveLocation = "archive"
+
+// NewArchiveStorage creates the storage receiving the events archived by the retention policy.
+// It uses the configured driver at a separate location: an archive prefix for S3, an archive directory
+// for files and a sibling database or snapshot for SQLite and RAM. Like the events, RAM archives without
+// a snapshot are lost on restart.
+func NewArchiveStorage(cfg *config.Config) (EventStorage, error) {
+	archiveCfg := *cfg
+	storage := &archiveCfg.Storage
+	switch storage.Driver {
+	case "ram":
+		if storage.RAM.SnapshotPath != "" {
+			storage.RAM.SnapshotPath = archiveSibling(storage.RAM.SnapshotPath)
+		}
+	case "file":
+		storage.File.Path = filepath.Join(storage.File.Path, archiveLocation)
+	case "sqlite":
+		storage.SQLite.Path = archiveSibling(storage.SQLite.Path)
+	case "s3":
+		storage.S3.Prefix = path.Join(storage.S3.Prefix, archiveLocation)
+	case "mirror":
+		if storage.Mirror.Primary == nil || storage.Mirror.Secondary == nil {
+			return nil, fmt.Errorf("mirror driver requires a primary and a secondary storage")
+		}
+		primary, err := NewArchiveStorage(&config.Config{Storage: *storage.Mirror.Primary})
+		if err != nil {
+			return nil, fmt.Errorf("mirror primary: %w", err)
+		}
+		secondary, err := NewArchiveStorage(&config.Config{Storage: *storage.Mirror.Secondary})
+		if err != nil {
+			return nil, fmt.Errorf("mirror secondary: %w", err)
+		}
+		return NewMirrorStorage(primary, secondary), nil
+	}
+	return newDriver(&archiveCfg)
+}
+
+// archiveSibling returns the path of the archive next to the file at path, clariti-archive.db for clariti.db
+func archiveSibling(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + archiveLocation + ext
+}
+
+// newDriver creates the configured storage driver
+func newDriver(cfg *config.Config) (EventStorage, error) {
 	switch cfg.GetStorageDriver() {
 	case "ram":
+		ramConfig := cfg.GetRAMConfig()
+		if ramConfig.SnapshotPath != "" {
+			return NewRAMStorageWithSnapshot(RAMConfig{
+				SnapshotPath:     ramConfig.SnapshotPath,
+				SnapshotInterval: ramConfig.SnapshotInterval,
+			})
+		}
 		return NewRAMStorage(), nil
+	case "file":
+		fileConfig := cfg.GetFileConfig()
+		return NewFileStorage(FileConfig{
+			Path: fileConfig.Path,
+		})
+	case "sqlite":
+		sqliteConfig := cfg.GetSQLiteConfig()
+		return NewSQLiteStorage(SQLiteConfig{
+			Path: sqliteConfig.Path,
+		})
 	case "s3":
 		s3Config := cfg.GetS3Config()
 		s3StorageConfig := S3Config{
@@ -21,7 +100,28 @@ func NewStorage(cfg *config.Config) (EventStorage, error) {
 			Endpoint:        s3Config.Endpoint,
 			Prefix:          s3Config.Prefix,
 		}
+		if s3Config.Encryption.Enabled() {
+			keyring, err := LoadKeyring(s3Config.Encryption.KeyFile, s3Config.Encryption.KeyEnv)
+			if err != nil {
+				return nil, fmt.Errorf("invalid s3 encryption configuration: %w", err)
+			}
+			s3StorageConfig.Keyring = keyring
+		}
 		return NewS3Storage(s3StorageConfig)
+	case "mirror":
+		mirrorConfig := cfg.GetMirrorConfig()
+		if mirrorConfig.Primary == nil || mirrorConfig.Secondary == nil {
+			return nil, fmt.Errorf("mirror driver requires a primary and a secondary storage")
+		}
+		primary, err := newDriver(&config.Config{Storage: *mirrorConfig.Primary})
+		if err != nil {
+			return nil, fmt.Errorf("mirror primary: %w", err)
+		}
+		secondary, err := newDriver(&config.Config{Storage: *mirrorConfig.Secondary})
+		if err != nil {
+			return nil, fmt.Errorf("mirror secondary: %w", err)
+		}
+		return NewMirrorStorage(primary, secondary), nil
 	default:
 		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.GetStorageDriver())
 	}
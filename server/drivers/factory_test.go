@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/gmllt/clariti/server/config"
@@ -23,6 +24,48 @@ func TestNewStorage_RAM(t *testing.T) {
 	}
 }
 
+func TestNewStorage_File(t *testing.T) {
+	cfg := &config.Config{
+		Storage: config.StorageConfig{
+			Driver: "file",
+			File: config.FileConfig{
+				Path: t.TempDir(),
+			},
+		},
+	}
+
+	storage, err := NewStorage(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, ok := storage.(*FileStorage); !ok {
+		t.Fatalf("expected FileStorage, got %T", storage)
+	}
+}
+
+func TestNewStorage_SQLite(t *testing.T) {
+	cfg := &config.Config{
+		Storage: config.StorageConfig{
+			Driver: "sqlite",
+			SQLite: config.SQLiteConfig{
+				Path: filepath.Join(t.TempDir(), "clariti.db"),
+			},
+		},
+	}
+
+	storage, err := NewStorage(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sqliteStorage, ok := storage.(*SQLiteStorage)
+	if !ok {
+		t.Fatalf("expected SQLiteStorage, got %T", storage)
+	}
+	sqliteStorage.Close()
+}
+
 func TestNewStorage_S3(t *testing.T) {
 	cfg := &config.Config{
 		Storage: config.StorageConfig{
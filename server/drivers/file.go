@@ -0,0 +1,679 @@
+package drivers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+)
+
+// FileStorage implements EventStorage interface using a local directory
+type FileStorage struct {
+	dir string
+}
+
+// FileConfig holds file-specific configuration
+type FileConfig struct {
+	Path string
+}
+
+const (
+	fileLockName          = ".clariti.lock"
+	fileLockRetryInterval = 5 * time.Millisecond
+)
+
+// NewFileStorage creates a new file storage driver
+func NewFileStorage(cfg FileConfig) (*FileStorage, error) {
+	log := logger.GetDefault().WithComponent("FileStorage")
+	log.WithField("path", cfg.Path).Info("Initializing file storage driver")
+
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("file storage path is required")
+	}
+
+	dir, err := filepath.Abs(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage path %s: %w", cfg.Path, err)
+	}
+
+	for _, category := range []string{"incidents", "planned_maintenances"} {
+		if err := os.MkdirAll(filepath.Join(dir, category), 0o750); err != nil {
+			log.WithError(err).WithField("path", dir).Error("Failed to create storage directory")
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+
+	log.WithField("path", dir).Info("File storage driver initialized successfully")
+	return &FileStorage{dir: dir}, nil
+}
+
+// Helper methods for file operations
+
+func (f *FileStorage) getPath(category, id string) (string, error) {
+	// IDs come straight from request paths, never let them escape the category directory
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid event id %q", id)
+	}
+	return filepath.Join(f.dir, category, id+".json"), nil
+}
+
+// withLock runs fn while holding the directory lock, exclusive for writes and shared for reads.
+// The lock is taken on a freshly opened descriptor so that it also serializes goroutines of this process.
+// Waiting for the lock is abandoned when ctx is done.
+func (f *FileStorage) withLock(ctx context.Context, exclusive bool, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	lockFile, err := os.OpenFile(filepath.Join(f.dir, fileLockName), os.O_CREATE|os.O_RDWR, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+	defer lockFile.Close()
+
+	for {
+		locked, err := tryLockFileDescriptor(lockFile, exclusive)
+		if err != nil {
+			return fmt.Errorf("failed to lock storage directory: %w", err)
+		}
+		if locked {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(fileLockRetryInterval):
+		}
+	}
+	defer func() {
+		_ = unlockFileDescriptor(lockFile)
+	}()
+
+	return fn()
+}
+
+func (f *FileStorage) writeFile(path string, data interface{}) error {
+	log := logger.GetDefault().WithComponent("FileStorage")
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		log.WithError(err).WithField("path", path).Error("Failed to marshal data")
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+	if err := writeFileAtomic(path, jsonData); err != nil {
+		return err
+	}
+
+	log.WithField("path", path).WithField("size_bytes", len(jsonData)).Debug("File written successfully")
+	return nil
+}
+
+// writeFileAtomic replaces the file at path with data, so that readers and crashes see either the old or the new content
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		// No-op once the rename succeeded
+		_ = os.Remove(tmpName)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", path, err)
+	}
+	if err := syncDir(dir); err != nil {
+		return fmt.Errorf("failed to sync directory %s: %w", dir, err)
+	}
+	return nil
+}
+
+func (f *FileStorage) readFile(path string, dest interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+func (f *FileStorage) removeFile(path string) error {
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+func (f *FileStorage) exists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to stat %s: %w", path, err)
+}
+
+func (f *FileStorage) listFiles(category string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(f.dir, category))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", category, err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		paths = append(paths, filepath.Join(f.dir, category, name))
+	}
+	return paths, nil
+}
+
+// syncDir flushes directory entries so that renames and removals survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil && !isSyncUnsupported(err) {
+		return err
+	}
+	return nil
+}
+
+// create stores data at path unless a file already exists there, and records its creation.
+// version points to the version field of data and is set to the initial version.
+func (f *FileStorage) create(ctx context.Context, category, id, path string, version *int64, data interface{}) error {
+	return f.withLock(ctx, true, func() error {
+		exists, err := f.exists(path)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrExists
+		}
+
+		previous := *version
+		*version = initialVersion(previous)
+		revision, err := newRevision(ctx, id, event.RevisionCreated, *version, nil, data)
+		if err != nil {
+			*version = previous
+			return err
+		}
+		if err := f.writeFile(path, data); err != nil {
+			*version = previous
+			return err
+		}
+		return f.writeRevision(category, id, revision)
+	})
+}
+
+// replace overwrites the data stored at path, which must already exist, and records the change.
+// version points to the version field of data, it is checked against the stored version and set to the next one.
+func (f *FileStorage) replace(ctx context.Context, category, id, path string, version *int64, data interface{}) error {
+	return f.withLock(ctx, true, func() error {
+		stored, state, err := f.readVersioned(path)
+		if err != nil {
+			return err
+		}
+		if state.DeletedAt != nil {
+			return ErrNotFound
+		}
+		next, err := nextVersion(state.Version, *version)
+		if err != nil {
+			return err
+		}
+
+		expected := *version
+		*version = next
+		revision, err := newRevision(ctx, id, event.RevisionUpdated, next, stored, data)
+		if err != nil {
+			*version = expected
+			return err
+		}
+		if err := f.writeFile(path, data); err != nil {
+			*version = expected
+			return err
+		}
+		return f.writeRevision(category, id, revision)
+	})
+}
+
+// trash moves the data stored at path to the trash and records its deletion
+func (f *FileStorage) trash(ctx context.Context, category, id, path string) error {
+	return f.withLock(ctx, true, func() error {
+		stored, state, err := f.readVersioned(path)
+		if err != nil {
+			return err
+		}
+		if state.DeletedAt != nil {
+			return ErrNotFound
+		}
+
+		deleted, err := markDeleted(ctx, stored, state.Version+1)
+		if err != nil {
+			return err
+		}
+		revision, err := newRevision(ctx, id, event.RevisionDeleted, state.Version+1, stored, deleted)
+		if err != nil {
+			return err
+		}
+		if err := f.writeFile(path, deleted); err != nil {
+			return err
+		}
+		return f.writeRevision(category, id, revision)
+	})
+}
+
+// restore takes the data stored at path out of the trash, records it and returns the restored document
+func (f *FileStorage) restore(ctx context.Context, category, id, path string) (json.RawMessage, error) {
+	var restored json.RawMessage
+	err := f.withLock(ctx, true, func() error {
+		stored, state, err := f.readVersioned(path)
+		if err != nil {
+			return err
+		}
+		if state.DeletedAt == nil {
+			return ErrNotFound
+		}
+
+		restored, err = markRestored(stored, state.Version+1)
+		if err != nil {
+			return err
+		}
+		revision, err := newRevision(ctx, id, event.RevisionRestored, state.Version+1, stored, restored)
+		if err != nil {
+			return err
+		}
+		if err := f.writeFile(path, restored); err != nil {
+			return err
+		}
+		return f.writeRevision(category, id, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// readVersioned returns the raw document stored at path along with its version and deletion time
+func (f *FileStorage) readVersioned(path string) (json.RawMessage, trashState, error) {
+	var stored json.RawMessage
+	if err := f.readFile(path, &stored); err != nil {
+		return nil, trashState{}, err
+	}
+	state, err := readTrashState(stored)
+	if err != nil {
+		return nil, trashState{}, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return stored, state, nil
+}
+
+// revisionDir returns the directory holding the revisions of an event
+func (f *FileStorage) revisionDir(category, id string) string {
+	return filepath.Join(f.dir, "revisions", category, id)
+}
+
+// writeRevision stores a revision, file names sort in write order
+func (f *FileStorage) writeRevision(category, id string, revision *event.Revision) error {
+	dir := f.revisionDir(category, id)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create revision directory: %w", err)
+	}
+	name := fmt.Sprintf("%020d-%d.json", revision.Timestamp.UnixNano(), revision.Version)
+	return f.writeFile(filepath.Join(dir, name), revision)
+}
+
+// readRevisions returns the revisions of an event, oldest first
+func (f *FileStorage) readRevisions(ctx context.Context, category, id string) ([]*event.Revision, error) {
+	if _, err := f.getPath(category, id); err != nil {
+		return []*event.Revision{}, nil
+	}
+
+	revisions := []*event.Revision{}
+	err := f.withLock(ctx, false, func() error {
+		entries, err := os.ReadDir(f.revisionDir(category, id))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to list revisions of %s: %w", id, err)
+		}
+		// ReadDir sorts by file name
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+				continue
+			}
+			var revision event.Revision
+			if err := f.readFile(filepath.Join(f.revisionDir(category, id), name), &revision); err != nil {
+				return err
+			}
+			revisions = append(revisions, &revision)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// Incidents implementation
+
+func (f *FileStorage) CreateIncident(ctx context.Context, incident *event.Incident) error {
+	log := logger.GetDefault().WithComponent("FileStorage")
+	log.WithField("incident_id", incident.GUID).Info("Creating incident on disk")
+
+	path, err := f.getPath("incidents", incident.GUID)
+	if err != nil {
+		return err
+	}
+
+	if err := f.create(ctx, "incidents", incident.GUID, path, &incident.Version, incident); err != nil {
+		if err == ErrExists {
+			log.WithField("incident_id", incident.GUID).Warn("Incident already exists")
+		} else {
+			log.WithError(err).WithField("incident_id", incident.GUID).Error("Failed to create incident")
+		}
+		return err
+	}
+
+	log.WithField("incident_id", incident.GUID).Info("Incident created successfully on disk")
+	return nil
+}
+
+func (f *FileStorage) GetIncident(ctx context.Context, id string) (*event.Incident, error) {
+	path, err := f.getPath("incidents", id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	var incident event.Incident
+	err = f.withLock(ctx, false, func() error {
+		return f.readFile(path, &incident)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if incident.IsDeleted() {
+		return nil, ErrNotFound
+	}
+	return &incident, nil
+}
+
+func (f *FileStorage) GetAllIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return f.loadIncidents(ctx, false)
+}
+
+// loadIncidents returns the incidents in the trash when deleted is true, the other ones otherwise
+func (f *FileStorage) loadIncidents(ctx context.Context, deleted bool) ([]*event.Incident, error) {
+	log := logger.GetDefault().WithComponent("FileStorage")
+
+	var incidents []*event.Incident
+	err := f.withLock(ctx, false, func() error {
+		paths, err := f.listFiles("incidents")
+		if err != nil {
+			return err
+		}
+		incidents = make([]*event.Incident, 0, len(paths))
+		for _, path := range paths {
+			var incident event.Incident
+			if err := f.readFile(path, &incident); err != nil {
+				log.WithError(err).WithField("path", path).Warn("Failed to load incident, skipping")
+				continue
+			}
+			if incident.IsDeleted() == deleted {
+				incidents = append(incidents, &incident)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to list incidents")
+		return nil, err
+	}
+	return incidents, nil
+}
+
+// ListIncidents returns the incidents matching the filter, filtering the full listing in memory
+func (f *FileStorage) ListIncidents(ctx context.Context, filter EventFilter) (*IncidentPage, error) {
+	all, err := f.GetAllIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterIncidents(all, filter)
+}
+
+func (f *FileStorage) UpdateIncident(ctx context.Context, incident *event.Incident) error {
+	path, err := f.getPath("incidents", incident.GUID)
+	if err != nil {
+		return ErrNotFound
+	}
+	return f.replace(ctx, "incidents", incident.GUID, path, &incident.Version, incident)
+}
+
+func (f *FileStorage) DeleteIncident(ctx context.Context, id string) error {
+	path, err := f.getPath("incidents", id)
+	if err != nil {
+		return ErrNotFound
+	}
+	return f.trash(ctx, "incidents", id, path)
+}
+
+// GetDeletedIncidents returns the incidents in the trash, most recently deleted first
+func (f *FileStorage) GetDeletedIncidents(ctx context.Context) ([]*event.Incident, error) {
+	incidents, err := f.loadIncidents(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	sortTrash(incidents, func(i *event.Incident) *time.Time { return i.DeletedAt })
+	return incidents, nil
+}
+
+// RestoreIncident takes an incident out of the trash
+func (f *FileStorage) RestoreIncident(ctx context.Context, id string) (*event.Incident, error) {
+	path, err := f.getPath("incidents", id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	document, err := f.restore(ctx, "incidents", id, path)
+	if err != nil {
+		return nil, err
+	}
+	var incident event.Incident
+	if err := json.Unmarshal(document, &incident); err != nil {
+		return nil, fmt.Errorf("failed to decode incident %s: %w", id, err)
+	}
+	return &incident, nil
+}
+
+// GetIncidentRevisions returns the revision history of an incident, oldest first
+func (f *FileStorage) GetIncidentRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return f.readRevisions(ctx, "incidents", id)
+}
+
+// Planned Maintenances implementation
+
+func (f *FileStorage) CreatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	path, err := f.getPath("planned_maintenances", pm.GUID)
+	if err != nil {
+		return err
+	}
+	return f.create(ctx, "planned_maintenances", pm.GUID, path, &pm.Version, pm)
+}
+
+func (f *FileStorage) GetPlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	path, err := f.getPath("planned_maintenances", id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	var pm event.PlannedMaintenance
+	err = f.withLock(ctx, false, func() error {
+		return f.readFile(path, &pm)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pm.IsDeleted() {
+		return nil, ErrNotFound
+	}
+	return &pm, nil
+}
+
+func (f *FileStorage) GetAllPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return f.loadPlannedMaintenances(ctx, false)
+}
+
+// loadPlannedMaintenances returns the planned maintenances in the trash when deleted is true, the other ones otherwise
+func (f *FileStorage) loadPlannedMaintenances(ctx context.Context, deleted bool) ([]*event.PlannedMaintenance, error) {
+	log := logger.GetDefault().WithComponent("FileStorage")
+
+	var pms []*event.PlannedMaintenance
+	err := f.withLock(ctx, false, func() error {
+		paths, err := f.listFiles("planned_maintenances")
+		if err != nil {
+			return err
+		}
+		pms = make([]*event.PlannedMaintenance, 0, len(paths))
+		for _, path := range paths {
+			var pm event.PlannedMaintenance
+			if err := f.readFile(path, &pm); err != nil {
+				log.WithError(err).WithField("path", path).Warn("Failed to load planned maintenance, skipping")
+				continue
+			}
+			if pm.IsDeleted() == deleted {
+				pms = append(pms, &pm)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pms, nil
+}
+
+// ListPlannedMaintenances returns the planned maintenances matching the filter, filtering the full listing in memory
+func (f *FileStorage) ListPlannedMaintenances(ctx context.Context, filter EventFilter) (*PlannedMaintenancePage, error) {
+	all, err := f.GetAllPlannedMaintenances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterPlannedMaintenances(all, filter)
+}
+
+func (f *FileStorage) UpdatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	path, err := f.getPath("planned_maintenances", pm.GUID)
+	if err != nil {
+		return ErrNotFound
+	}
+	return f.replace(ctx, "planned_maintenances", pm.GUID, path, &pm.Version, pm)
+}
+
+func (f *FileStorage) DeletePlannedMaintenance(ctx context.Context, id string) error {
+	path, err := f.getPath("planned_maintenances", id)
+	if err != nil {
+		return ErrNotFound
+	}
+	return f.trash(ctx, "planned_maintenances", id, path)
+}
+
+// GetDeletedPlannedMaintenances returns the planned maintenances in the trash, most recently deleted first
+func (f *FileStorage) GetDeletedPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	pms, err := f.loadPlannedMaintenances(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	sortTrash(pms, func(pm *event.PlannedMaintenance) *time.Time { return pm.DeletedAt })
+	return pms, nil
+}
+
+// RestorePlannedMaintenance takes a planned maintenance out of the trash
+func (f *FileStorage) RestorePlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	path, err := f.getPath("planned_maintenances", id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	document, err := f.restore(ctx, "planned_maintenances", id, path)
+	if err != nil {
+		return nil, err
+	}
+	var pm event.PlannedMaintenance
+	if err := json.Unmarshal(document, &pm); err != nil {
+		return nil, fmt.Errorf("failed to decode planned maintenance %s: %w", id, err)
+	}
+	return &pm, nil
+}
+
+// GetPlannedMaintenanceRevisions returns the revision history of a planned maintenance, oldest first
+func (f *FileStorage) GetPlannedMaintenanceRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return f.readRevisions(ctx, "planned_maintenances", id)
+}
+
+// PurgeDeleted permanently removes the events deleted before the given time
+func (f *FileStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	log := logger.GetDefault().WithComponent("FileStorage")
+
+	purged := 0
+	err := f.withLock(ctx, true, func() error {
+		for _, category := range []string{"incidents", "planned_maintenances"} {
+			paths, err := f.listFiles(category)
+			if err != nil {
+				return err
+			}
+			for _, path := range paths {
+				_, state, err := f.readVersioned(path)
+				if err != nil {
+					log.WithError(err).WithField("path", path).Warn("Failed to load event, skipping")
+					continue
+				}
+				if !purgeable(state.DeletedAt, before) {
+					continue
+				}
+				if err := f.removeFile(path); err != nil {
+					return err
+				}
+				purged++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return purged, err
+	}
+	log.WithField("count", purged).Info("Purged deleted events")
+	return purged, nil
+}
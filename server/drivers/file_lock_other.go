@@ -0,0 +1,23 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package drivers
+
+import (
+	"os"
+)
+
+// tryLockFileDescriptor always succeeds on platforms without advisory file locks;
+// only a single server may use the storage directory there
+func tryLockFileDescriptor(f *os.File, exclusive bool) (bool, error) {
+	return true, nil
+}
+
+// unlockFileDescriptor is a no-op on platforms without advisory file locks
+func unlockFileDescriptor(f *os.File) error {
+	return nil
+}
+
+// isSyncUnsupported reports whether a directory fsync error can be safely ignored
+func isSyncUnsupported(err error) bool {
+	return true
+}
@@ -0,0 +1,39 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package drivers
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLockFileDescriptor attempts to take an advisory flock on the file without blocking
+func tryLockFileDescriptor(f *os.File, exclusive bool) (bool, error) {
+	how := syscall.LOCK_SH | syscall.LOCK_NB
+	if exclusive {
+		how = syscall.LOCK_EX | syscall.LOCK_NB
+	}
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		switch err {
+		case nil:
+			return true, nil
+		case syscall.EWOULDBLOCK:
+			return false, nil
+		case syscall.EINTR:
+			continue
+		default:
+			return false, err
+		}
+	}
+}
+
+// unlockFileDescriptor releases the flock held on the file
+func unlockFileDescriptor(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
+
+// isSyncUnsupported reports whether a directory fsync error can be safely ignored
+func isSyncUnsupported(err error) bool {
+	return err == syscall.EINVAL
+}
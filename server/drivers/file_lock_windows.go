@@ -0,0 +1,35 @@
+//go:build windows
+
+package drivers
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLockFileDescriptor attempts to take a LockFileEx lock on the file without blocking
+func tryLockFileDescriptor(f *os.File, exclusive bool) (bool, error) {
+	flags := uint32(windows.LOCKFILE_FAIL_IMMEDIATELY)
+	if exclusive {
+		flags |= windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	ol := new(windows.Overlapped)
+	err := windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, 1, 0, ol)
+	if err == windows.ERROR_LOCK_VIOLATION {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFileDescriptor releases the lock held on the file
+func unlockFileDescriptor(f *os.File) error {
+	ol := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol)
+}
+
+// isSyncUnsupported reports whether a directory fsync error can be safely ignored.
+// Windows does not allow flushing directory handles.
+func isSyncUnsupported(err error) bool {
+	return true
+}
@@ -0,0 +1,239 @@
+package drivers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+)
+
+func newTestFileStorage(t *testing.T) (*FileStorage, string) {
+	t.Helper()
+	dir := t.TempDir()
+	storage, err := NewFileStorage(FileConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("Failed to create file storage: %v", err)
+	}
+	return storage, dir
+}
+
+func TestNewFileStorage_RequiresPath(t *testing.T) {
+	if _, err := NewFileStorage(FileConfig{}); err == nil {
+		t.Fatal("Expected error for empty path")
+	}
+}
+
+func TestFileStorage_IncidentCRUD(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestFileStorage(t)
+
+	comp := component.NewComponent("API", "api", component.NewInstance("Main", "main", component.NewPlatform("Prod", "PROD")))
+	incident := event.NewFiringIncident("Outage", "API is down", []*component.Component{comp}, event.CriticalityMajorOutage)
+
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	if err := storage.CreateIncident(ctx, incident); err != ErrExists {
+		t.Errorf("Expected ErrExists on duplicate create, got %v", err)
+	}
+
+	got, err := storage.GetIncident(ctx, incident.GUID)
+	if err != nil {
+		t.Fatalf("GetIncident failed: %v", err)
+	}
+	if got.Title != "Outage" || got.IncidentCriticality != event.CriticalityMajorOutage {
+		t.Errorf("Unexpected incident: %+v", got)
+	}
+	if len(got.Components) != 1 || got.Components[0].Instance.Platform.Code != "PROD" {
+		t.Errorf("Expected component hierarchy to round-trip, got %+v", got.Components)
+	}
+
+	incident.Title = "Outage (updated)"
+	if err := storage.UpdateIncident(ctx, incident); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	got, _ = storage.GetIncident(ctx, incident.GUID)
+	if got.Title != "Outage (updated)" {
+		t.Errorf("Expected updated title, got %q", got.Title)
+	}
+
+	all, err := storage.GetAllIncidents(ctx)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("Expected 1 incident, got %d (err=%v)", len(all), err)
+	}
+
+	if err := storage.DeleteIncident(ctx, incident.GUID); err != nil {
+		t.Fatalf("DeleteIncident failed: %v", err)
+	}
+	if _, err := storage.GetIncident(ctx, incident.GUID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := storage.DeleteIncident(ctx, incident.GUID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound on second delete, got %v", err)
+	}
+	if err := storage.UpdateIncident(ctx, incident); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound on update of deleted incident, got %v", err)
+	}
+}
+
+func TestFileStorage_PlannedMaintenanceCRUD(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestFileStorage(t)
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	pm := event.NewPlannedMaintenance("Upgrade", "DB upgrade", nil, start, start.Add(2*time.Hour))
+
+	if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+	if err := storage.CreatePlannedMaintenance(ctx, pm); err != ErrExists {
+		t.Errorf("Expected ErrExists on duplicate create, got %v", err)
+	}
+
+	got, err := storage.GetPlannedMaintenance(ctx, pm.GUID)
+	if err != nil {
+		t.Fatalf("GetPlannedMaintenance failed: %v", err)
+	}
+	if !got.StartPlanned.Equal(start) {
+		t.Errorf("Expected start %v, got %v", start, got.StartPlanned)
+	}
+
+	pm.Cancelled = true
+	if err := storage.UpdatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("UpdatePlannedMaintenance failed: %v", err)
+	}
+	all, _ := storage.GetAllPlannedMaintenances(ctx)
+	if len(all) != 1 || !all[0].Cancelled {
+		t.Errorf("Expected 1 cancelled maintenance, got %+v", all)
+	}
+
+	if err := storage.DeletePlannedMaintenance(ctx, pm.GUID); err != nil {
+		t.Fatalf("DeletePlannedMaintenance failed: %v", err)
+	}
+	if _, err := storage.GetPlannedMaintenance(ctx, pm.GUID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestFileStorage_PersistsAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	storage, dir := newTestFileStorage(t)
+
+	incident := event.NewKnownIssue("Slow search", "Search is slow", nil, event.CriticalityDegraded)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	reopened, err := NewFileStorage(FileConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("Failed to reopen file storage: %v", err)
+	}
+	got, err := reopened.GetIncident(ctx, incident.GUID)
+	if err != nil {
+		t.Fatalf("Expected incident after reopen, got %v", err)
+	}
+	if !got.Perpetual {
+		t.Error("Expected perpetual flag to persist")
+	}
+}
+
+func TestFileStorage_RejectsPathTraversal(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestFileStorage(t)
+
+	for _, id := range []string{"", "..", "../escape", `a\b`} {
+		if _, err := storage.GetIncident(ctx, id); err != ErrNotFound {
+			t.Errorf("GetIncident(%q): expected ErrNotFound, got %v", id, err)
+		}
+		incident := &event.Incident{BaseEvent: event.BaseEvent{GUID: id}}
+		if err := storage.CreateIncident(ctx, incident); err == nil {
+			t.Errorf("CreateIncident(%q): expected error", id)
+		}
+	}
+}
+
+func TestFileStorage_ConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	storage, dir := newTestFileStorage(t)
+
+	// A second handle on the same directory simulates another server process
+	other, err := NewFileStorage(FileConfig{Path: dir})
+	if err != nil {
+		t.Fatalf("Failed to open second handle: %v", err)
+	}
+
+	incident := event.NewFiringIncident("Race", "content", nil, event.CriticalityDegraded)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := storage
+			if i%2 == 0 {
+				s = other
+			}
+			updated := *incident
+			updated.Content = strings.Repeat("x", i+1)
+			updated.Version = 0 // blind write, every update must land
+			if err := s.UpdateIncident(ctx, &updated); err != nil {
+				t.Errorf("UpdateIncident failed: %v", err)
+			}
+			if _, err := s.GetIncident(ctx, incident.GUID); err != nil {
+				t.Errorf("GetIncident failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	got, err := storage.GetIncident(ctx, incident.GUID)
+	if err != nil {
+		t.Fatalf("Expected readable incident after concurrent writes, got %v", err)
+	}
+	if strings.Trim(got.Content, "x") != "" {
+		t.Errorf("Unexpected content after concurrent writes: %q", got.Content)
+	}
+	if got.Version != 21 {
+		t.Errorf("Expected version 21 after 20 updates, got %d", got.Version)
+	}
+
+	// Atomic writes must not leave temporary files behind
+	entries, _ := os.ReadDir(filepath.Join(dir, "incidents"))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".tmp-") {
+			t.Errorf("Found leftover temporary file %s", entry.Name())
+		}
+	}
+}
+
+func TestFileStorage_LockWaitHonorsContext(t *testing.T) {
+	storage, dir := newTestFileStorage(t)
+
+	// Hold the directory lock as another process would
+	lockFile, err := os.OpenFile(filepath.Join(dir, fileLockName), os.O_CREATE|os.O_RDWR, 0o640)
+	if err != nil {
+		t.Fatalf("Failed to open lock file: %v", err)
+	}
+	defer lockFile.Close()
+	if locked, err := tryLockFileDescriptor(lockFile, true); err != nil || !locked {
+		t.Fatalf("Failed to take lock: locked=%v err=%v", locked, err)
+	}
+	defer unlockFileDescriptor(lockFile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	incident := event.NewFiringIncident("Blocked", "content", nil, event.CriticalityDegraded)
+	if err := storage.CreateIncident(ctx, incident); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded while lock is held, got %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package drivers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+)
+
+// ErrInvalidCursor is returned when a listing cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortOrder defines the order of filtered listings
+type SortOrder string
+
+const (
+	// SortDescending lists the most recent events first
+	SortDescending SortOrder = "desc"
+	// SortAscending lists the oldest events first
+	SortAscending SortOrder = "asc"
+)
+
+// EventFilter selects the events returned by a filtered listing.
+// Empty fields do not constrain the result. Events are ordered by their start time
+// (start_effective for incidents, start_planned for planned maintenances) then by GUID.
+type EventFilter struct {
+	Statuses      []event.Status
+	Types         []event.TypeEvent
+	Criticalities []event.Criticality
+
+	// Component path, each level requires the previous one like weather paths do
+	PlatformCode  string
+	InstanceCode  string
+	ComponentCode string
+
+	// Time range the event must overlap
+	From *time.Time
+	To   *time.Time
+
+	// Text is matched case-insensitively against title and content
+	Text string
+
+	// Selector is matched against the labels of the event
+	Selector event.Selector
+
+	Sort   SortOrder
+	Cursor string
+	Limit  int // 0 means no limit
+}
+
+// IncidentPage is a page of a filtered incident listing
+type IncidentPage struct {
+	Items      []*event.Incident
+	NextCursor string // empty on the last page
+}
+
+// PlannedMaintenancePage is a page of a filtered planned maintenance listing
+type PlannedMaintenancePage struct {
+	Items      []*event.PlannedMaintenance
+	NextCursor string // empty on the last page
+}
+
+// listCursor is the position after which a page starts
+type listCursor struct {
+	key  int64
+	guid string
+}
+
+func encodeCursor(key int64, guid string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(key, 10) + ":" + guid))
+}
+
+func decodeCursor(cursor string) (*listCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	keyPart, guid, ok := strings.Cut(string(raw), ":")
+	if !ok || guid == "" {
+		return nil, ErrInvalidCursor
+	}
+	key, err := strconv.ParseInt(keyPart, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &listCursor{key: key, guid: guid}, nil
+}
+
+// after reports whether an event sorted at (key, guid) comes after the cursor
+func (c *listCursor) after(key int64, guid string, order SortOrder) bool {
+	if order == SortAscending {
+		return key > c.key || (key == c.key && guid > c.guid)
+	}
+	return key < c.key || (key == c.key && guid < c.guid)
+}
+
+// Validate checks the values a driver cannot interpret
+func (f EventFilter) Validate() error {
+	switch f.Sort {
+	case "", SortAscending, SortDescending:
+	default:
+		return fmt.Errorf("invalid sort order %q", f.Sort)
+	}
+	if f.Limit < 0 {
+		return fmt.Errorf("invalid limit %d", f.Limit)
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return fmt.Errorf("time range end is before its start")
+	}
+	if _, err := decodeCursor(f.Cursor); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f EventFilter) order() SortOrder {
+	if f.Sort == SortAscending {
+		return SortAscending
+	}
+	return SortDescending
+}
+
+// eventSortKey converts an event start time to its sort key (unix nanoseconds, 0 when unset)
+func eventSortKey(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func incidentSortKey(incident *event.Incident) int64 {
+	return eventSortKey(incident.StartEffective)
+}
+
+func plannedMaintenanceSortKey(pm *event.PlannedMaintenance) int64 {
+	return eventSortKey(&pm.StartPlanned)
+}
+
+// MatchesIncident reports whether the incident satisfies every criterion of the filter except pagination
+func (f EventFilter) MatchesIncident(incident *event.Incident) bool {
+	return f.matches(incident, &incident.BaseEvent, incident.StartEffective, incident.EndEffective)
+}
+
+// MatchesPlannedMaintenance reports whether the maintenance satisfies every criterion of the filter except pagination.
+// A recurring maintenance spans its whole series, with no end when the series does not end.
+func (f EventFilter) MatchesPlannedMaintenance(pm *event.PlannedMaintenance) bool {
+	start, end := plannedMaintenanceRange(pm)
+	return f.matches(pm, &pm.BaseEvent, start, end)
+}
+
+// plannedMaintenanceRange returns the time range a maintenance is listed over: its effective times, defaulting
+// to the planned ones, or its whole series when it recurs, with no end when the series does not end
+func plannedMaintenanceRange(pm *event.PlannedMaintenance) (start, end *time.Time) {
+	if pm.IsRecurring() {
+		return &pm.StartPlanned, pm.SeriesEnd()
+	}
+	start, end = pm.StartEffective, pm.EndEffective
+	if start == nil {
+		start = &pm.StartPlanned
+	}
+	if end == nil {
+		end = &pm.EndPlanned
+	}
+	return start, end
+}
+
+func (f EventFilter) matches(evt event.Event, base *event.BaseEvent, start, end *time.Time) bool {
+	if len(f.Statuses) > 0 && !containsValue(f.Statuses, evt.Status()) {
+		return false
+	}
+	if f.Text != "" {
+		text := strings.ToLower(f.Text)
+		if !strings.Contains(strings.ToLower(base.Title), text) && !strings.Contains(strings.ToLower(base.Content), text) {
+			return false
+		}
+	}
+	return f.matchesStored(evt.Type(), evt.Criticality(), base.Components, base.Labels, start, end)
+}
+
+// matchesStored checks the criteria depending neither on the current time nor on the text of the event,
+// which lets indexes preselect the events a listing has to load
+func (f EventFilter) matchesStored(typ event.TypeEvent, criticality event.Criticality, components []*component.Component,
+	labels map[string]string, start, end *time.Time) bool {
+	if len(f.Types) > 0 && !containsValue(f.Types, typ) {
+		return false
+	}
+	if len(f.Criticalities) > 0 && !containsValue(f.Criticalities, criticality) {
+		return false
+	}
+	if f.PlatformCode != "" && !componentsMatchPath(components, f.PlatformCode, f.InstanceCode, f.ComponentCode) {
+		return false
+	}
+	if f.From != nil && end != nil && end.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && start != nil && start.After(*f.To) {
+		return false
+	}
+	return f.Selector.Matches(labels)
+}
+
+func containsValue[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// componentsMatchPath reports whether any component sits under the given path
+func componentsMatchPath(components []*component.Component, platformCode, instanceCode, componentCode string) bool {
+	for _, comp := range components {
+		if comp != nil && componentMatchesPath(comp, platformCode, instanceCode, componentCode) {
+			return true
+		}
+	}
+	return false
+}
+
+// componentMatchesPath reports whether the component sits under the given path
+func componentMatchesPath(comp *component.Component, platformCode, instanceCode, componentCode string) bool {
+	// Get platform code through the hierarchy
+	var compPlatformCode, compInstanceCode, compComponentCode string
+
+	if comp.Instance != nil {
+		compInstanceCode = comp.Instance.Code
+		if comp.Instance.Platform != nil {
+			compPlatformCode = comp.Instance.Platform.Code
+		}
+	}
+	compComponentCode = comp.Code
+
+	// If component code is specified, it must match exactly
+	if componentCode != "" {
+		return compPlatformCode == platformCode && compInstanceCode == instanceCode && compComponentCode == componentCode
+	}
+
+	// If only instance is specified, component must be in that instance
+	if instanceCode != "" {
+		return compPlatformCode == platformCode && compInstanceCode == instanceCode
+	}
+
+	// If only platform is specified, component must be in that platform
+	return compPlatformCode == platformCode
+}
+
+// paginate sorts the matching items, skips those up to the cursor and cuts the page to the limit.
+// Drivers without native query support use it on their full listing.
+func paginate[T any](items []T, filter EventFilter, key func(T) int64, guid func(T) string) ([]T, string, error) {
+	cursor, err := decodeCursor(filter.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	order := filter.order()
+
+	sort.Slice(items, func(i, j int) bool {
+		ki, kj := key(items[i]), key(items[j])
+		if ki != kj {
+			if order == SortAscending {
+				return ki < kj
+			}
+			return ki > kj
+		}
+		if order == SortAscending {
+			return guid(items[i]) < guid(items[j])
+		}
+		return guid(items[i]) > guid(items[j])
+	})
+
+	page := make([]T, 0, len(items))
+	for _, item := range items {
+		if cursor != nil && !cursor.after(key(item), guid(item), order) {
+			continue
+		}
+		page = append(page, item)
+	}
+
+	if filter.Limit > 0 && len(page) > filter.Limit {
+		page = page[:filter.Limit]
+		last := page[len(page)-1]
+		return page, encodeCursor(key(last), guid(last)), nil
+	}
+	return page, "", nil
+}
+
+// filterIncidents applies the filter to a full incident listing
+func filterIncidents(incidents []*event.Incident, filter EventFilter) (*IncidentPage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	matching := make([]*event.Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		if filter.MatchesIncident(incident) {
+			matching = append(matching, incident)
+		}
+	}
+	items, next, err := paginate(matching, filter, incidentSortKey, func(i *event.Incident) string { return i.GUID })
+	if err != nil {
+		return nil, err
+	}
+	return &IncidentPage{Items: items, NextCursor: next}, nil
+}
+
+// filterPlannedMaintenances applies the filter to a full planned maintenance listing
+func filterPlannedMaintenances(pms []*event.PlannedMaintenance, filter EventFilter) (*PlannedMaintenancePage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	matching := make([]*event.PlannedMaintenance, 0, len(pms))
+	for _, pm := range pms {
+		if filter.MatchesPlannedMaintenance(pm) {
+			matching = append(matching, pm)
+		}
+	}
+	items, next, err := paginate(matching, filter, plannedMaintenanceSortKey, func(pm *event.PlannedMaintenance) string { return pm.GUID })
+	if err != nil {
+		return nil, err
+	}
+	return &PlannedMaintenancePage{Items: items, NextCursor: next}, nil
+}
@@ -0,0 +1,320 @@
+package drivers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+)
+
+// seedFilterStorage stores a small set of events covering every filter criterion
+func seedFilterStorage(t *testing.T, storage EventStorage) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	prod := component.NewPlatform("Prod", "PROD")
+	api := component.NewComponent("API", "api", component.NewInstance("Main", "main", prod))
+	db := component.NewComponent("DB", "db", component.NewInstance("Backup", "backup", prod))
+	dev := component.NewComponent("API", "api", component.NewInstance("Main", "main", component.NewPlatform("Dev", "DEV")))
+
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	ongoing := event.NewFiringIncident("API outage", "Requests are failing", []*component.Component{api}, event.CriticalityMajorOutage)
+	ongoing.GUID = "inc-1"
+	ongoing.StartEffective = at(-time.Hour)
+	ongoing.Labels = map[string]string{"team": "payments", "source": "alertmanager", "example.com/tier": "1"}
+
+	resolved := event.NewFiringIncident("Slow database", "Replication lag, Équipe stockage paged", []*component.Component{db}, event.CriticalityDegraded)
+	resolved.GUID = "inc-2"
+	resolved.StartEffective = at(-48 * time.Hour)
+	resolved.EndEffective = at(-47 * time.Hour)
+	resolved.Labels = map[string]string{"team": "storage"}
+
+	known := event.NewKnownIssue("Dev flakiness", "Known API timeouts", []*component.Component{dev}, event.CriticalityPartialOutage)
+	known.GUID = "inc-3"
+	if err := known.Transition(event.StatusMonitoring, "A fix is deployed", "alice", *at(-2 * time.Hour)); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+
+	for _, incident := range []*event.Incident{ongoing, resolved, known} {
+		if err := storage.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+
+	planned := event.NewPlannedMaintenance("DB upgrade", "Upgrading", []*component.Component{db}, now.Add(24*time.Hour), now.Add(26*time.Hour))
+	planned.GUID = "pm-1"
+	canceled := event.NewPlannedMaintenance("API upgrade", "Upgrading", []*component.Component{api}, now.Add(48*time.Hour), now.Add(50*time.Hour))
+	canceled.GUID = "pm-2"
+	canceled.Cancelled = true
+
+	for _, pm := range []*event.PlannedMaintenance{planned, canceled} {
+		if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+			t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+		}
+	}
+}
+
+// filterTestDrivers are the drivers with distinct filtering implementations
+var filterTestDrivers = []struct {
+	name    string
+	storage func(t *testing.T) EventStorage
+}{
+	{"ram", func(t *testing.T) EventStorage { return NewRAMStorage() }},
+	{"sqlite", func(t *testing.T) EventStorage { s, _ := newTestSQLiteStorage(t); return s }},
+}
+
+func mustParseSelector(t *testing.T, selector string) event.Selector {
+	t.Helper()
+	parsed, err := event.ParseSelector(selector)
+	if err != nil {
+		t.Fatalf("ParseSelector failed: %v", err)
+	}
+	return parsed
+}
+
+func incidentGUIDs(incidents []*event.Incident) []string {
+	guids := make([]string, len(incidents))
+	for i, incident := range incidents {
+		guids[i] = incident.GUID
+	}
+	return guids
+}
+
+func equalGUIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestListIncidents_Filters(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		filter   EventFilter
+		expected []string
+	}{
+		{"no filter, most recent first", EventFilter{}, []string{"inc-1", "inc-3", "inc-2"}},
+		{"ascending", EventFilter{Sort: SortAscending}, []string{"inc-2", "inc-3", "inc-1"}},
+		{"status", EventFilter{Statuses: []event.Status{event.StatusResolved}}, []string{"inc-2"}},
+		{"timing status", EventFilter{Statuses: []event.Status{event.StatusOnGoing}}, []string{"inc-1"}},
+		{"lifecycle status", EventFilter{Statuses: []event.Status{event.StatusMonitoring, event.StatusIdentified}}, []string{"inc-3"}},
+		{"type", EventFilter{Types: []event.TypeEvent{event.TypeKnownIssue}}, []string{"inc-3"}},
+		{"criticality", EventFilter{Criticalities: []event.Criticality{event.CriticalityMajorOutage, event.CriticalityDegraded}}, []string{"inc-1", "inc-2"}},
+		{"platform", EventFilter{PlatformCode: "PROD"}, []string{"inc-1", "inc-2"}},
+		{"instance", EventFilter{PlatformCode: "PROD", InstanceCode: "backup"}, []string{"inc-2"}},
+		{"component", EventFilter{PlatformCode: "DEV", InstanceCode: "main", ComponentCode: "api"}, []string{"inc-3"}},
+		{"time range", EventFilter{From: &since}, []string{"inc-1", "inc-3"}},
+		{"text", EventFilter{Text: "TIMEOUTS"}, []string{"inc-3"}},
+		{"non-ASCII text", EventFilter{Text: "équipe"}, []string{"inc-2"}},
+		{"selector equals", EventFilter{Selector: mustParseSelector(t, "team=payments")}, []string{"inc-1"}},
+		{"selector notin", EventFilter{Selector: mustParseSelector(t, "team notin (payments)")}, []string{"inc-3", "inc-2"}},
+		{"selector set", EventFilter{Selector: mustParseSelector(t, "example.com/tier,source in (alertmanager,grafana)")}, []string{"inc-1"}},
+		{"selector does not exist", EventFilter{Selector: mustParseSelector(t, "!team")}, []string{"inc-3"}},
+		{"selector not equals", EventFilter{Selector: mustParseSelector(t, "team!=storage,source!=grafana")}, []string{"inc-1", "inc-3"}},
+	}
+
+	for _, driver := range filterTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			seedFilterStorage(t, storage)
+
+			for _, tt := range tests {
+				page, err := storage.ListIncidents(context.Background(), tt.filter)
+				if err != nil {
+					t.Fatalf("%s: ListIncidents failed: %v", tt.name, err)
+				}
+				if got := incidentGUIDs(page.Items); !equalGUIDs(got, tt.expected) {
+					t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+				}
+				if page.NextCursor != "" {
+					t.Errorf("%s: expected no next cursor without limit, got %q", tt.name, page.NextCursor)
+				}
+			}
+		})
+	}
+}
+
+func TestListIncidents_CursorPagination(t *testing.T) {
+	for _, driver := range filterTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			seedFilterStorage(t, storage)
+
+			var seen []string
+			filter := EventFilter{Limit: 2}
+			for pages := 0; ; pages++ {
+				if pages > 3 {
+					t.Fatal("Pagination did not terminate")
+				}
+				page, err := storage.ListIncidents(context.Background(), filter)
+				if err != nil {
+					t.Fatalf("ListIncidents failed: %v", err)
+				}
+				seen = append(seen, incidentGUIDs(page.Items)...)
+				if page.NextCursor == "" {
+					break
+				}
+				filter.Cursor = page.NextCursor
+			}
+
+			if expected := []string{"inc-1", "inc-3", "inc-2"}; !equalGUIDs(seen, expected) {
+				t.Errorf("Expected pages to cover %v, got %v", expected, seen)
+			}
+		})
+	}
+}
+
+func TestListIncidents_Acknowledged(t *testing.T) {
+	for _, driver := range filterTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			ctx := context.Background()
+
+			incident := event.NewFiringIncident("Outage", "API is down", nil, event.CriticalityMajorOutage)
+			incident.GUID = "inc-ack"
+			if err := incident.Transition(event.StatusInvestigating, "Looking into it", "alice", time.Now()); err != nil {
+				t.Fatalf("Transition failed: %v", err)
+			}
+			if err := storage.CreateIncident(ctx, incident); err != nil {
+				t.Fatalf("CreateIncident failed: %v", err)
+			}
+			acknowledged := EventFilter{Statuses: []event.Status{event.StatusAcknowledged}}
+			if page, err := storage.ListIncidents(ctx, acknowledged); err != nil || len(page.Items) != 0 {
+				t.Fatalf("Expected no acknowledged incident, got %v (%v)", page, err)
+			}
+
+			if err := incident.Acknowledge("bob", time.Now()); err != nil {
+				t.Fatalf("Acknowledge failed: %v", err)
+			}
+			if err := storage.UpdateIncident(ctx, incident); err != nil {
+				t.Fatalf("UpdateIncident failed: %v", err)
+			}
+			page, err := storage.ListIncidents(ctx, acknowledged)
+			if err != nil {
+				t.Fatalf("ListIncidents failed: %v", err)
+			}
+			if got := incidentGUIDs(page.Items); !equalGUIDs(got, []string{"inc-ack"}) {
+				t.Errorf("Expected the acknowledged incident, got %v", got)
+			}
+		})
+	}
+}
+
+func TestListPlannedMaintenances_Filters(t *testing.T) {
+	for _, driver := range filterTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			seedFilterStorage(t, storage)
+
+			page, err := storage.ListPlannedMaintenances(context.Background(), EventFilter{Statuses: []event.Status{event.StatusPlanned}})
+			if err != nil {
+				t.Fatalf("ListPlannedMaintenances failed: %v", err)
+			}
+			if len(page.Items) != 1 || page.Items[0].GUID != "pm-1" {
+				t.Errorf("Expected only pm-1 to be planned, got %d items", len(page.Items))
+			}
+
+			page, err = storage.ListPlannedMaintenances(context.Background(), EventFilter{Sort: SortAscending, Limit: 1})
+			if err != nil {
+				t.Fatalf("ListPlannedMaintenances failed: %v", err)
+			}
+			if len(page.Items) != 1 || page.Items[0].GUID != "pm-1" || page.NextCursor == "" {
+				t.Errorf("Expected first page to hold pm-1 with a next cursor, got %d items, cursor %q", len(page.Items), page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestListPlannedMaintenances_Recurring(t *testing.T) {
+	for _, driver := range filterTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			seedFilterStorage(t, storage)
+			ctx := context.Background()
+
+			// A weekly series which started a month ago, in the middle of an occurrence now
+			start := time.Now().AddDate(0, 0, -28).Add(-time.Hour)
+			series := event.NewPlannedMaintenance("Weekly patching", "Rolling reboot", nil, start, start.Add(2*time.Hour))
+			series.GUID = "pm-3"
+			series.Recurrence = "FREQ=WEEKLY"
+			if err := storage.CreatePlannedMaintenance(ctx, series); err != nil {
+				t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+			}
+
+			page, err := storage.ListPlannedMaintenances(ctx, EventFilter{Statuses: []event.Status{event.StatusOnGoing}})
+			if err != nil {
+				t.Fatalf("ListPlannedMaintenances failed: %v", err)
+			}
+			if len(page.Items) != 1 || page.Items[0].GUID != "pm-3" {
+				t.Errorf("Expected the series to be ongoing, got %d items", len(page.Items))
+			}
+
+			// The series does not end, so it overlaps any window after its start
+			from := time.Now().AddDate(1, 0, 0)
+			page, err = storage.ListPlannedMaintenances(ctx, EventFilter{From: &from})
+			if err != nil {
+				t.Fatalf("ListPlannedMaintenances failed: %v", err)
+			}
+			if len(page.Items) != 1 || page.Items[0].GUID != "pm-3" {
+				t.Errorf("Expected only the open-ended series next year, got %d items", len(page.Items))
+			}
+
+			// Cancelling the current occurrence leaves the series planned, with the limit applied after filtering
+			if err := series.SetOverride(event.OccurrenceOverride{RecurrenceID: start.AddDate(0, 0, 28), Cancelled: true}); err != nil {
+				t.Fatalf("SetOverride failed: %v", err)
+			}
+			if err := storage.UpdatePlannedMaintenance(ctx, series); err != nil {
+				t.Fatalf("UpdatePlannedMaintenance failed: %v", err)
+			}
+			page, err = storage.ListPlannedMaintenances(ctx, EventFilter{Statuses: []event.Status{event.StatusPlanned}, Sort: SortAscending, Limit: 1})
+			if err != nil {
+				t.Fatalf("ListPlannedMaintenances failed: %v", err)
+			}
+			if len(page.Items) != 1 || page.Items[0].GUID != "pm-3" || page.NextCursor == "" {
+				t.Fatalf("Expected the series first with a next cursor, got %d items, cursor %q", len(page.Items), page.NextCursor)
+			}
+			page, err = storage.ListPlannedMaintenances(ctx, EventFilter{Statuses: []event.Status{event.StatusPlanned}, Sort: SortAscending, Limit: 1, Cursor: page.NextCursor})
+			if err != nil {
+				t.Fatalf("ListPlannedMaintenances failed: %v", err)
+			}
+			if len(page.Items) != 1 || page.Items[0].GUID != "pm-1" || page.NextCursor != "" {
+				t.Errorf("Expected pm-1 on the last page, got %d items, cursor %q", len(page.Items), page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestEventFilter_Validate(t *testing.T) {
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	invalid := []EventFilter{
+		{Sort: "sideways"},
+		{Limit: -1},
+		{From: &from, To: &to},
+		{Cursor: "not a cursor"},
+	}
+	for _, filter := range invalid {
+		if err := filter.Validate(); err == nil {
+			t.Errorf("Expected validation error for %+v", filter)
+		}
+	}
+
+	if err := (EventFilter{Cursor: encodeCursor(42, "inc-1")}).Validate(); err != nil {
+		t.Errorf("Expected encoded cursor to be valid, got %v", err)
+	}
+}
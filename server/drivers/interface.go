@@ -1,22 +1,68 @@
 package drivers
 
 import (
+	"context"
+	"time"
+
 	"github.com/gmllt/clariti/models/event"
 )
 
-// EventStorage defines the interface for event storage drivers
+// EventStorage defines the interface for event storage drivers.
+// Every method must honor the deadline and cancellation of the given context.
+//
+// Writes are versioned: Create* stores the event at version 1 and Update* increments the stored version,
+// setting the new version on the given event. When the given event carries a non-zero version, Update*
+// only succeeds if it matches the stored one and returns ErrVersionConflict otherwise.
+//
+// GetAll* return the live events ordered by GUID, and an empty list rather than nil when there are none.
+// Lookups, updates and deletions of unknown events return ErrNotFound.
+//
+// Every create, update and delete also appends an immutable revision attributed to AuthorFromContext(ctx).
+// Get*Revisions return the history of an event oldest first, including after it was deleted,
+// and an empty list for events without any recorded write.
+//
+// Delete* moves an event to the trash: it is kept at the next version with DeletedAt and DeletedBy set,
+// and every other read ignores it until Restore* takes it out again. Creating an event with the GUID of
+// a deleted one returns ErrExists, and an event created with DeletedAt set, such as a migrated one, goes straight
+// to the trash. Restore* returns ErrNotFound for events that are not in the trash.
+// PurgeDeleted permanently removes the events deleted before the given time, their history is kept.
 type EventStorage interface {
 	// Incidents
-	CreateIncident(incident *event.Incident) error
-	GetIncident(id string) (*event.Incident, error)
-	GetAllIncidents() ([]*event.Incident, error)
-	UpdateIncident(incident *event.Incident) error
-	DeleteIncident(id string) error
+	CreateIncident(ctx context.Context, incident *event.Incident) error
+	GetIncident(ctx context.Context, id string) (*event.Incident, error)
+	GetAllIncidents(ctx context.Context) ([]*event.Incident, error)
+	ListIncidents(ctx context.Context, filter EventFilter) (*IncidentPage, error)
+	UpdateIncident(ctx context.Context, incident *event.Incident) error
+	DeleteIncident(ctx context.Context, id string) error
+	GetDeletedIncidents(ctx context.Context) ([]*event.Incident, error)
+	RestoreIncident(ctx context.Context, id string) (*event.Incident, error)
+	GetIncidentRevisions(ctx context.Context, id string) ([]*event.Revision, error)
 
 	// Planned Maintenances
-	CreatePlannedMaintenance(pm *event.PlannedMaintenance) error
-	GetPlannedMaintenance(id string) (*event.PlannedMaintenance, error)
-	GetAllPlannedMaintenances() ([]*event.PlannedMaintenance, error)
-	UpdatePlannedMaintenance(pm *event.PlannedMaintenance) error
-	DeletePlannedMaintenance(id string) error
+	CreatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error
+	GetPlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error)
+	GetAllPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error)
+	ListPlannedMaintenances(ctx context.Context, filter EventFilter) (*PlannedMaintenancePage, error)
+	UpdatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error
+	DeletePlannedMaintenance(ctx context.Context, id string) error
+	GetDeletedPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error)
+	RestorePlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error)
+	GetPlannedMaintenanceRevisions(ctx context.Context, id string) ([]*event.Revision, error)
+
+	// Trash
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+}
+
+// ActiveEventStorage is implemented by drivers that can select events which have not ended yet
+// without loading the whole history. Results may include events that are not active anymore,
+// callers still apply their own activity rules.
+type ActiveEventStorage interface {
+	GetActiveIncidents(ctx context.Context) ([]*event.Incident, error)
+	GetActivePlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error)
+}
+
+// IndexedEventStorage is implemented by drivers maintaining an index next to the events, such as the S3 manifest.
+// RebuildIndex recreates it from the stored events and returns the number of indexed events.
+type IndexedEventStorage interface {
+	RebuildIndex(ctx context.Context) (int, error)
 }
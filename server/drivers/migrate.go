@@ -0,0 +1,199 @@
+package drivers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+)
+
+// ErrMigrationMismatch is returned when the migrated events differ from the source ones
+var ErrMigrationMismatch = errors.New("migrated events do not match the source")
+
+// MigrateOptions tunes a migration between two storages
+type MigrateOptions struct {
+	// DryRun reads both storages and reports what would be copied without writing anything
+	DryRun bool
+	// Progress is called after each event with the number of events of the kind handled so far
+	Progress func(kind string, done, total int)
+}
+
+// MigrationKindReport summarizes the migration of one kind of events
+type MigrationKindReport struct {
+	Total    int    // events in the source, deleted ones included
+	Copied   int    // events written to the destination, or that would be in a dry run
+	Skipped  int    // events already in the destination
+	Checksum string // digest of the source events, equal to the one of their copies once verified
+}
+
+// MigrationReport summarizes a migration between two storages
+type MigrationReport struct {
+	DryRun              bool
+	Incidents           MigrationKindReport
+	PlannedMaintenances MigrationKindReport
+}
+
+// Migrate copies every incident and planned maintenance, deleted ones included, from one storage to another.
+// Events keep their GUID, version and trash state, but their revision history restarts with a creation
+// attributed to AuthorFromContext(ctx). Events whose GUID already exists in the destination are skipped,
+// so an interrupted migration can be run again. Once copied, the destination is read back and compared
+// with the source, returning ErrMigrationMismatch when an event differs.
+func Migrate(ctx context.Context, from, to EventStorage, opts MigrateOptions) (*MigrationReport, error) {
+	report := &MigrationReport{DryRun: opts.DryRun}
+
+	incidents, err := migrateKind(ctx, "incidents", opts,
+		func() ([]*event.Incident, error) { return allIncidents(ctx, from) },
+		func() ([]*event.Incident, error) { return allIncidents(ctx, to) },
+		func(i *event.Incident) string { return i.GUID },
+		func(i *event.Incident) error { return to.CreateIncident(ctx, i) })
+	if err != nil {
+		return report, err
+	}
+	report.Incidents = *incidents
+
+	maintenances, err := migrateKind(ctx, "planned_maintenances", opts,
+		func() ([]*event.PlannedMaintenance, error) { return allPlannedMaintenances(ctx, from) },
+		func() ([]*event.PlannedMaintenance, error) { return allPlannedMaintenances(ctx, to) },
+		func(pm *event.PlannedMaintenance) string { return pm.GUID },
+		func(pm *event.PlannedMaintenance) error { return to.CreatePlannedMaintenance(ctx, pm) })
+	if err != nil {
+		return report, err
+	}
+	report.PlannedMaintenances = *maintenances
+
+	return report, nil
+}
+
+// migrateKind copies the events of one kind and verifies their copies
+func migrateKind[T any](ctx context.Context, kind string, opts MigrateOptions,
+	loadSource, loadDestination func() ([]T, error), guid func(T) string, create func(T) error) (*MigrationKindReport, error) {
+	log := logger.GetDefault().WithComponent("Migration").WithField("kind", kind)
+
+	source, err := loadSource()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source %s: %w", kind, err)
+	}
+	destination, err := loadDestination()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read destination %s: %w", kind, err)
+	}
+	existing := make(map[string]bool, len(destination))
+	for _, item := range destination {
+		existing[guid(item)] = true
+	}
+
+	report := &MigrationKindReport{Total: len(source)}
+	log.WithField("total", len(source)).WithField("existing", len(existing)).Info("Migrating events")
+	for i, item := range source {
+		switch {
+		case existing[guid(item)]:
+			report.Skipped++
+		case opts.DryRun:
+			report.Copied++
+		default:
+			if err := create(item); err == ErrExists {
+				report.Skipped++
+			} else if err != nil {
+				return report, fmt.Errorf("failed to copy %s %s: %w", kind, guid(item), err)
+			} else {
+				report.Copied++
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(kind, i+1, len(source))
+		}
+	}
+
+	// Digests are taken after copying since creation sets the version of events stored without one
+	sourceDigests, err := eventDigests(source, guid)
+	if err != nil {
+		return report, err
+	}
+	report.Checksum = checksum(sourceDigests)
+	if opts.DryRun {
+		return report, nil
+	}
+
+	if destination, err = loadDestination(); err != nil {
+		return report, fmt.Errorf("failed to read back destination %s: %w", kind, err)
+	}
+	destinationDigests, err := eventDigests(destination, guid)
+	if err != nil {
+		return report, err
+	}
+	var mismatches []string
+	for id, digest := range sourceDigests {
+		if destinationDigests[id] != digest {
+			mismatches = append(mismatches, id)
+		}
+	}
+	if len(mismatches) > 0 {
+		sort.Strings(mismatches)
+		log.WithField("mismatches", len(mismatches)).Error("Migrated events differ from the source")
+		return report, fmt.Errorf("%w: %d of %d %s differ, first %s", ErrMigrationMismatch, len(mismatches), len(source), kind, mismatches[0])
+	}
+
+	log.WithField("copied", report.Copied).WithField("skipped", report.Skipped).Info("Events migrated and verified")
+	return report, nil
+}
+
+// allIncidents returns the live and deleted incidents of a storage
+func allIncidents(ctx context.Context, storage EventStorage) ([]*event.Incident, error) {
+	incidents, err := storage.GetAllIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := storage.GetDeletedIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(incidents, deleted...), nil
+}
+
+// allPlannedMaintenances returns the live and deleted planned maintenances of a storage
+func allPlannedMaintenances(ctx context.Context, storage EventStorage) ([]*event.PlannedMaintenance, error) {
+	maintenances, err := storage.GetAllPlannedMaintenances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := storage.GetDeletedPlannedMaintenances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(maintenances, deleted...), nil
+}
+
+// eventDigests returns the SHA-256 of the JSON document of each event, keyed by GUID
+func eventDigests[T any](items []T, guid func(T) string) (map[string]string, error) {
+	digests := make(map[string]string, len(items))
+	for _, item := range items {
+		document, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event %s: %w", guid(item), err)
+		}
+		sum := sha256.Sum256(document)
+		digests[guid(item)] = hex.EncodeToString(sum[:])
+	}
+	return digests, nil
+}
+
+// checksum combines event digests into a single one, independent of the listing order
+func checksum(digests map[string]string) string {
+	guids := make([]string, 0, len(digests))
+	for guid := range digests {
+		guids = append(guids, guid)
+	}
+	sort.Strings(guids)
+
+	hash := sha256.New()
+	for _, guid := range guids {
+		fmt.Fprintf(hash, "%s %s\n", guid, digests[guid])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,128 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+// newMigrationSource returns a storage holding a live and a deleted event of each kind
+func newMigrationSource(t *testing.T) EventStorage {
+	ctx := WithAuthor(context.Background(), "alice")
+	source, _ := newTestFileStorage(t)
+
+	for _, title := range []string{"Live outage", "Deleted outage"} {
+		incident := event.NewFiringIncident(title, "API down", nil, event.CriticalityMajorOutage)
+		if err := source.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+		if title == "Deleted outage" {
+			if err := source.DeleteIncident(ctx, incident.GUID); err != nil {
+				t.Fatalf("DeleteIncident failed: %v", err)
+			}
+		}
+	}
+	pm := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	if err := source.CreatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+	pm.Title = "Upgrade to v2"
+	if err := source.UpdatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("UpdatePlannedMaintenance failed: %v", err)
+	}
+	return source
+}
+
+func TestMigrate(t *testing.T) {
+	for _, driver := range versionTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			ctx := WithAuthor(context.Background(), "migration")
+			source := newMigrationSource(t)
+			destination := driver.storage(t)
+
+			report, err := Migrate(ctx, source, destination, MigrateOptions{})
+			if err != nil {
+				t.Fatalf("Migrate failed: %v", err)
+			}
+			if report.Incidents.Total != 2 || report.Incidents.Copied != 2 || report.Incidents.Checksum == "" {
+				t.Errorf("Expected 2 copied incidents, got %+v", report.Incidents)
+			}
+			if report.PlannedMaintenances.Copied != 1 {
+				t.Errorf("Expected 1 copied planned maintenance, got %+v", report.PlannedMaintenances)
+			}
+
+			// Trash state and versions are preserved
+			live, err := destination.GetAllIncidents(ctx)
+			if err != nil || len(live) != 1 || live[0].Title != "Live outage" {
+				t.Fatalf("Expected the live incident to be copied, got %d, %v", len(live), err)
+			}
+			trash, err := destination.GetDeletedIncidents(ctx)
+			if err != nil || len(trash) != 1 || trash[0].DeletedBy != "alice" || trash[0].Version != 2 {
+				t.Fatalf("Expected the deleted incident in the trash, got %d, %v", len(trash), err)
+			}
+			maintenances, err := destination.GetAllPlannedMaintenances(ctx)
+			if err != nil || len(maintenances) != 1 || maintenances[0].Version != 2 {
+				t.Fatalf("Expected the planned maintenance at version 2, got %d, %v", len(maintenances), err)
+			}
+
+			// Running again copies nothing and still verifies
+			again, err := Migrate(ctx, source, destination, MigrateOptions{})
+			if err != nil {
+				t.Fatalf("Second Migrate failed: %v", err)
+			}
+			if again.Incidents.Copied != 0 || again.Incidents.Skipped != 2 || again.Incidents.Checksum != report.Incidents.Checksum {
+				t.Errorf("Expected every incident to be skipped with the same checksum, got %+v", again.Incidents)
+			}
+		})
+	}
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	ctx := context.Background()
+	source := newMigrationSource(t)
+	destination := NewRAMStorage()
+
+	var progress int
+	report, err := Migrate(ctx, source, destination, MigrateOptions{
+		DryRun:   true,
+		Progress: func(kind string, done, total int) { progress++ },
+	})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if !report.DryRun || report.Incidents.Copied != 2 || report.PlannedMaintenances.Copied != 1 {
+		t.Errorf("Expected 3 events to be reported, got %+v", report)
+	}
+	if progress != 3 {
+		t.Errorf("Expected progress for 3 events, got %d", progress)
+	}
+	if all, _ := destination.GetAllIncidents(ctx); len(all) != 0 {
+		t.Errorf("Expected a dry run to write nothing, got %d incidents", len(all))
+	}
+}
+
+func TestMigrate_Mismatch(t *testing.T) {
+	ctx := context.Background()
+	source := newMigrationSource(t)
+	destination := NewRAMStorage()
+
+	// An event already in the destination with a different content fails the verification
+	live, err := source.GetAllIncidents(ctx)
+	if err != nil || len(live) != 1 {
+		t.Fatalf("GetAllIncidents failed: %v", err)
+	}
+	conflicting := *live[0]
+	conflicting.Title = "Edited elsewhere"
+	if err := destination.CreateIncident(ctx, &conflicting); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	if _, err := Migrate(ctx, source, destination, MigrateOptions{}); err == nil {
+		t.Fatal("Expected a verification error")
+	} else if !errors.Is(err, ErrMigrationMismatch) {
+		t.Errorf("Expected ErrMigrationMismatch, got %v", err)
+	}
+}
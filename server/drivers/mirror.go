@@ -0,0 +1,364 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/metrics"
+)
+
+// MirrorStorage writes every event to two storages and reads them from the primary one.
+// The primary is the source of truth: a failed primary write fails the call and is not mirrored, while a
+// failed secondary write is only logged and counted as a divergence, to be repaired with Reconcile.
+// Secondary updates are unconditional, the version check is left to the primary.
+type MirrorStorage struct {
+	primary   EventStorage
+	secondary EventStorage
+}
+
+// NewMirrorStorage creates a storage mirroring the writes of primary to secondary
+func NewMirrorStorage(primary, secondary EventStorage) *MirrorStorage {
+	logger.GetDefault().WithComponent("MirrorStorage").
+		WithField("primary", fmt.Sprintf("%T", primary)).
+		WithField("secondary", fmt.Sprintf("%T", secondary)).
+		Info("Mirror storage driver initialized")
+	return &MirrorStorage{primary: primary, secondary: secondary}
+}
+
+// Primary returns the storage reads are served from
+func (m *MirrorStorage) Primary() EventStorage {
+	return m.primary
+}
+
+// Secondary returns the storage writes are mirrored to
+func (m *MirrorStorage) Secondary() EventStorage {
+	return m.secondary
+}
+
+// diverged records a secondary write that failed while the primary one succeeded
+func (m *MirrorStorage) diverged(kind, operation, guid string, err error) {
+	logger.GetDefault().WithComponent("MirrorStorage").
+		WithError(err).WithField("kind", kind).WithField("operation", operation).WithField("guid", guid).
+		Warn("Secondary storage diverged from the primary")
+	metrics.RecordMirrorDivergence(kind, operation)
+}
+
+// Incidents
+
+func (m *MirrorStorage) CreateIncident(ctx context.Context, incident *event.Incident) error {
+	if err := m.primary.CreateIncident(ctx, incident); err != nil {
+		return err
+	}
+	// The copy carries the version set by the primary, which the secondary keeps
+	mirrored := *incident
+	if err := m.secondary.CreateIncident(ctx, &mirrored); err != nil {
+		m.diverged("incidents", "create", incident.GUID, err)
+	}
+	return nil
+}
+
+func (m *MirrorStorage) GetIncident(ctx context.Context, id string) (*event.Incident, error) {
+	return m.primary.GetIncident(ctx, id)
+}
+
+func (m *MirrorStorage) GetAllIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return m.primary.GetAllIncidents(ctx)
+}
+
+func (m *MirrorStorage) ListIncidents(ctx context.Context, filter EventFilter) (*IncidentPage, error) {
+	return m.primary.ListIncidents(ctx, filter)
+}
+
+func (m *MirrorStorage) UpdateIncident(ctx context.Context, incident *event.Incident) error {
+	if err := m.primary.UpdateIncident(ctx, incident); err != nil {
+		return err
+	}
+	mirrored := *incident
+	mirrored.Version = 0
+	if err := m.secondary.UpdateIncident(ctx, &mirrored); err != nil {
+		m.diverged("incidents", "update", incident.GUID, err)
+	}
+	return nil
+}
+
+func (m *MirrorStorage) DeleteIncident(ctx context.Context, id string) error {
+	if err := m.primary.DeleteIncident(ctx, id); err != nil {
+		return err
+	}
+	if err := m.secondary.DeleteIncident(ctx, id); err != nil {
+		m.diverged("incidents", "delete", id, err)
+	}
+	return nil
+}
+
+func (m *MirrorStorage) GetDeletedIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return m.primary.GetDeletedIncidents(ctx)
+}
+
+func (m *MirrorStorage) RestoreIncident(ctx context.Context, id string) (*event.Incident, error) {
+	incident, err := m.primary.RestoreIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.secondary.RestoreIncident(ctx, id); err != nil {
+		m.diverged("incidents", "restore", id, err)
+	}
+	return incident, nil
+}
+
+func (m *MirrorStorage) GetIncidentRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return m.primary.GetIncidentRevisions(ctx, id)
+}
+
+// Planned Maintenances
+
+func (m *MirrorStorage) CreatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	if err := m.primary.CreatePlannedMaintenance(ctx, pm); err != nil {
+		return err
+	}
+	mirrored := *pm
+	if err := m.secondary.CreatePlannedMaintenance(ctx, &mirrored); err != nil {
+		m.diverged("planned_maintenances", "create", pm.GUID, err)
+	}
+	return nil
+}
+
+func (m *MirrorStorage) GetPlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	return m.primary.GetPlannedMaintenance(ctx, id)
+}
+
+func (m *MirrorStorage) GetAllPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return m.primary.GetAllPlannedMaintenances(ctx)
+}
+
+func (m *MirrorStorage) ListPlannedMaintenances(ctx context.Context, filter EventFilter) (*PlannedMaintenancePage, error) {
+	return m.primary.ListPlannedMaintenances(ctx, filter)
+}
+
+func (m *MirrorStorage) UpdatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	if err := m.primary.UpdatePlannedMaintenance(ctx, pm); err != nil {
+		return err
+	}
+	mirrored := *pm
+	mirrored.Version = 0
+	if err := m.secondary.UpdatePlannedMaintenance(ctx, &mirrored); err != nil {
+		m.diverged("planned_maintenances", "update", pm.GUID, err)
+	}
+	return nil
+}
+
+func (m *MirrorStorage) DeletePlannedMaintenance(ctx context.Context, id string) error {
+	if err := m.primary.DeletePlannedMaintenance(ctx, id); err != nil {
+		return err
+	}
+	if err := m.secondary.DeletePlannedMaintenance(ctx, id); err != nil {
+		m.diverged("planned_maintenances", "delete", id, err)
+	}
+	return nil
+}
+
+func (m *MirrorStorage) GetDeletedPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return m.primary.GetDeletedPlannedMaintenances(ctx)
+}
+
+func (m *MirrorStorage) RestorePlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	pm, err := m.primary.RestorePlannedMaintenance(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.secondary.RestorePlannedMaintenance(ctx, id); err != nil {
+		m.diverged("planned_maintenances", "restore", id, err)
+	}
+	return pm, nil
+}
+
+func (m *MirrorStorage) GetPlannedMaintenanceRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return m.primary.GetPlannedMaintenanceRevisions(ctx, id)
+}
+
+// Trash
+
+// PurgeDeleted purges both storages and returns the number of events purged from the primary
+func (m *MirrorStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	purged, err := m.primary.PurgeDeleted(ctx, before)
+	if err != nil {
+		return purged, err
+	}
+	secondaryPurged, err := m.secondary.PurgeDeleted(ctx, before)
+	if err == nil && secondaryPurged != purged {
+		err = fmt.Errorf("purged %d events from the secondary and %d from the primary", secondaryPurged, purged)
+	}
+	if err != nil {
+		m.diverged("trash", "purge", "", err)
+	}
+	return purged, nil
+}
+
+// GetActiveIncidents returns the active incidents of the primary, all of them when it cannot select them
+func (m *MirrorStorage) GetActiveIncidents(ctx context.Context) ([]*event.Incident, error) {
+	if active, ok := m.primary.(ActiveEventStorage); ok {
+		return active.GetActiveIncidents(ctx)
+	}
+	return m.primary.GetAllIncidents(ctx)
+}
+
+// GetActivePlannedMaintenances returns the active planned maintenances of the primary, all of them when it cannot select them
+func (m *MirrorStorage) GetActivePlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	if active, ok := m.primary.(ActiveEventStorage); ok {
+		return active.GetActivePlannedMaintenances(ctx)
+	}
+	return m.primary.GetAllPlannedMaintenances(ctx)
+}
+
+// RebuildIndex rebuilds the index of both storages that maintain one and returns the number of events indexed last
+func (m *MirrorStorage) RebuildIndex(ctx context.Context) (int, error) {
+	count, rebuilt := 0, false
+	for _, storage := range []EventStorage{m.secondary, m.primary} {
+		indexed, ok := storage.(IndexedEventStorage)
+		if !ok {
+			continue
+		}
+		n, err := indexed.RebuildIndex(ctx)
+		if err != nil {
+			return 0, err
+		}
+		count, rebuilt = n, true
+	}
+	if !rebuilt {
+		return 0, errors.New("neither mirrored storage maintains an index")
+	}
+	return count, nil
+}
+
+// Close closes both storages when they hold resources
+func (m *MirrorStorage) Close() error {
+	var errs []error
+	for _, storage := range []EventStorage{m.primary, m.secondary} {
+		if closer, ok := storage.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReconcileKindReport summarizes the repair of one kind of events of a secondary storage
+type ReconcileKindReport struct {
+	Checked           int // events of the primary, deleted ones included
+	Created           int // events missing from the secondary
+	Updated           int // events whose content or trash state differed
+	Deleted           int // live secondary events missing from the primary, moved to the trash
+	VersionMismatches int // events with the same content at another version, left as is
+}
+
+// ReconcileReport summarizes the repair of a secondary storage
+type ReconcileReport struct {
+	DryRun              bool
+	Incidents           ReconcileKindReport
+	PlannedMaintenances ReconcileKindReport
+}
+
+// Reconcile makes the secondary storage match the primary one and reports what was repaired, or would be in a dry run.
+// Events are compared by content and trash state; deletion times and versions are not, since the secondary
+// records its own. Missing events are created at the primary version, differing ones are overwritten at the
+// next secondary version.
+func (m *MirrorStorage) Reconcile(ctx context.Context, dryRun bool) (*ReconcileReport, error) {
+	report := &ReconcileReport{DryRun: dryRun}
+	if err := reconcileKind(incidentKind(ctx, m.primary), incidentKind(ctx, m.secondary), dryRun, &report.Incidents); err != nil {
+		return report, err
+	}
+	if err := reconcileKind(plannedMaintenanceKind(ctx, m.primary), plannedMaintenanceKind(ctx, m.secondary), dryRun, &report.PlannedMaintenances); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// reconcileKind repairs the events of one kind of the secondary storage
+func reconcileKind[T any](primary, secondary archiveKind[T], dryRun bool, report *ReconcileKindReport) error {
+	log := logger.GetDefault().WithComponent("MirrorStorage").WithField("kind", primary.name)
+
+	source, err := primary.load()
+	if err != nil {
+		return fmt.Errorf("failed to read primary %s: %w", primary.name, err)
+	}
+	mirrored, err := secondary.load()
+	if err != nil {
+		return fmt.Errorf("failed to read secondary %s: %w", secondary.name, err)
+	}
+	byGUID := make(map[string]T, len(mirrored))
+	for _, item := range mirrored {
+		byGUID[secondary.base(item).GUID] = item
+	}
+
+	report.Checked = len(source)
+	inPrimary := make(map[string]bool, len(source))
+	for _, item := range source {
+		base := primary.base(item)
+		inPrimary[base.GUID] = true
+
+		current, exists := byGUID[base.GUID]
+		if !exists {
+			report.Created++
+			if !dryRun {
+				if err := secondary.create(secondary.clone(item)); err != nil {
+					return fmt.Errorf("failed to create %s %s: %w", secondary.name, base.GUID, err)
+				}
+			}
+			continue
+		}
+
+		differs, err := mirrorDiffers(secondary.base(current), base, current, item)
+		if err != nil {
+			return err
+		}
+		if !differs {
+			if secondary.base(current).Version != base.Version {
+				report.VersionMismatches++
+			}
+			continue
+		}
+		report.Updated++
+		if !dryRun {
+			if err := replaceEvent(secondary, current, item); err != nil {
+				return fmt.Errorf("failed to replace %s %s: %w", secondary.name, base.GUID, err)
+			}
+		}
+	}
+
+	for guid, item := range byGUID {
+		if inPrimary[guid] || secondary.base(item).IsDeleted() {
+			continue
+		}
+		report.Deleted++
+		if !dryRun {
+			if err := secondary.remove(guid); err != nil {
+				return fmt.Errorf("failed to delete %s %s: %w", secondary.name, guid, err)
+			}
+		}
+	}
+
+	log.WithField("created", report.Created).WithField("updated", report.Updated).WithField("deleted", report.Deleted).
+		WithField("dry_run", dryRun).Info("Secondary storage reconciled")
+	return nil
+}
+
+// mirrorDiffers reports whether a mirrored event differs from its primary copy by content or trash state
+func mirrorDiffers(mirroredBase, primaryBase *event.BaseEvent, mirrored, primary interface{}) (bool, error) {
+	if mirroredBase.IsDeleted() != primaryBase.IsDeleted() {
+		return true, nil
+	}
+	changes, err := event.Diff(mirrored, primary)
+	if err != nil {
+		return false, err
+	}
+	for _, change := range changes {
+		if change.Field != "deleted_at" && change.Field != "deleted_by" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
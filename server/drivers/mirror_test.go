@@ -0,0 +1,171 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+// failingStorage is a RAM storage whose incident writes fail
+type failingStorage struct {
+	*RAMStorage
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (f *failingStorage) CreateIncident(ctx context.Context, incident *event.Incident) error {
+	return errWriteFailed
+}
+
+func (f *failingStorage) UpdateIncident(ctx context.Context, incident *event.Incident) error {
+	return errWriteFailed
+}
+
+func TestMirrorStorage_Writes(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := NewRAMStorage(), NewRAMStorage()
+	mirror := NewMirrorStorage(primary, secondary)
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := mirror.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	incident.Title = "Outage, updated"
+	if err := mirror.UpdateIncident(ctx, incident); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	for name, storage := range map[string]EventStorage{"primary": primary, "secondary": secondary} {
+		stored, err := storage.GetIncident(ctx, incident.GUID)
+		if err != nil {
+			t.Fatalf("Expected the incident in the %s storage, got %v", name, err)
+		}
+		if stored.Title != "Outage, updated" || stored.Version != incident.Version {
+			t.Errorf("Expected the %s incident updated at version %d, got %q at version %d", name, incident.Version, stored.Title, stored.Version)
+		}
+	}
+
+	// The version check is left to the primary
+	stale := *incident
+	stale.Version = 1
+	if err := mirror.UpdateIncident(ctx, &stale); err != ErrVersionConflict {
+		t.Errorf("Expected a version conflict, got %v", err)
+	}
+
+	if err := mirror.DeleteIncident(ctx, incident.GUID); err != nil {
+		t.Fatalf("DeleteIncident failed: %v", err)
+	}
+	if _, err := secondary.GetIncident(ctx, incident.GUID); err != ErrNotFound {
+		t.Errorf("Expected the incident deleted from the secondary, got %v", err)
+	}
+	if _, err := mirror.RestoreIncident(ctx, incident.GUID); err != nil {
+		t.Fatalf("RestoreIncident failed: %v", err)
+	}
+	if _, err := secondary.GetIncident(ctx, incident.GUID); err != nil {
+		t.Errorf("Expected the incident restored in the secondary, got %v", err)
+	}
+
+	// Reads are served by the primary
+	onlyPrimary := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, time.Now(), time.Now().Add(time.Hour))
+	if err := primary.CreatePlannedMaintenance(ctx, onlyPrimary); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+	if _, err := mirror.GetPlannedMaintenance(ctx, onlyPrimary.GUID); err != nil {
+		t.Errorf("Expected the primary planned maintenance, got %v", err)
+	}
+}
+
+func TestMirrorStorage_SecondaryFailure(t *testing.T) {
+	ctx := context.Background()
+	primary := NewRAMStorage()
+	mirror := NewMirrorStorage(primary, &failingStorage{NewRAMStorage()})
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := mirror.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("Expected a failed secondary write not to fail the call, got %v", err)
+	}
+	if _, err := primary.GetIncident(ctx, incident.GUID); err != nil {
+		t.Errorf("Expected the incident in the primary storage, got %v", err)
+	}
+
+	// A failed primary write is not mirrored
+	failing := NewMirrorStorage(&failingStorage{NewRAMStorage()}, primary)
+	other := event.NewFiringIncident("Other outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := failing.CreateIncident(ctx, other); err != errWriteFailed {
+		t.Errorf("Expected the primary error, got %v", err)
+	}
+	if _, err := primary.GetIncident(ctx, other.GUID); err != ErrNotFound {
+		t.Errorf("Expected the incident not mirrored, got %v", err)
+	}
+}
+
+func TestMirrorStorage_Reconcile(t *testing.T) {
+	ctx := WithAuthor(context.Background(), "reconcile")
+	primary, secondary := NewRAMStorage(), NewRAMStorage()
+	mirror := NewMirrorStorage(primary, secondary)
+
+	synced := event.NewFiringIncident("Synced", "API down", nil, event.CriticalityMajorOutage)
+	changed := event.NewFiringIncident("Changed", "API down", nil, event.CriticalityMajorOutage)
+	trashed := event.NewFiringIncident("Trashed", "API down", nil, event.CriticalityMajorOutage)
+	for _, incident := range []*event.Incident{synced, changed, trashed} {
+		if err := mirror.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+
+	// Diverge the secondary: a missing incident, a stale one, a trashed one still live and an extra one
+	missing := event.NewFiringIncident("Missing", "API down", nil, event.CriticalityMajorOutage)
+	if err := primary.CreateIncident(ctx, missing); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	changed.Title = "Changed, updated"
+	if err := primary.UpdateIncident(ctx, changed); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	if err := primary.DeleteIncident(ctx, trashed.GUID); err != nil {
+		t.Fatalf("DeleteIncident failed: %v", err)
+	}
+	extra := event.NewFiringIncident("Extra", "API down", nil, event.CriticalityMajorOutage)
+	if err := secondary.CreateIncident(ctx, extra); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	report, err := mirror.Reconcile(ctx, true)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	want := ReconcileKindReport{Checked: 4, Created: 1, Updated: 2, Deleted: 1}
+	if !report.DryRun || report.Incidents != want {
+		t.Errorf("Expected dry run report %+v, got %+v", want, report.Incidents)
+	}
+	if _, err := secondary.GetIncident(ctx, missing.GUID); err != ErrNotFound {
+		t.Errorf("Expected a dry run not to write, got %v", err)
+	}
+
+	if _, err := mirror.Reconcile(ctx, false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if stored, err := secondary.GetIncident(ctx, missing.GUID); err != nil || stored.Version != missing.Version {
+		t.Errorf("Expected the missing incident created at its version, got %v", err)
+	}
+	if stored, err := secondary.GetIncident(ctx, changed.GUID); err != nil || stored.Title != "Changed, updated" {
+		t.Errorf("Expected the changed incident updated, got %v", err)
+	}
+	if _, err := secondary.GetIncident(ctx, trashed.GUID); err != ErrNotFound {
+		t.Errorf("Expected the trashed incident deleted, got %v", err)
+	}
+	if _, err := secondary.GetIncident(ctx, extra.GUID); err != ErrNotFound {
+		t.Errorf("Expected the extra incident deleted, got %v", err)
+	}
+
+	// A reconciled secondary has nothing left to repair
+	report, err = mirror.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if report.Incidents.Created+report.Incidents.Updated+report.Incidents.Deleted != 0 {
+		t.Errorf("Expected nothing to repair, got %+v", report.Incidents)
+	}
+}
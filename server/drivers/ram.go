@@ -1,23 +1,42 @@
 package drivers
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/gmllt/clariti/logger"
 	"github.com/gmllt/clariti/models/event"
 )
 
 var (
-	ErrNotFound = errors.New("not found")
-	ErrExists   = errors.New("already exists")
+	ErrNotFound        = errors.New("not found")
+	ErrExists          = errors.New("already exists")
+	ErrVersionConflict = errors.New("version conflict")
 )
 
 // RAMStorage implements EventStorage interface using in-memory storage
 type RAMStorage struct {
-	mu                  sync.RWMutex
-	incidents           map[string]*event.Incident
-	plannedMaintenances map[string]*event.PlannedMaintenance
+	mu                          sync.RWMutex
+	incidents                   map[string]*event.Incident
+	plannedMaintenances         map[string]*event.PlannedMaintenance
+	incidentRevisions           map[string][]*event.Revision
+	plannedMaintenanceRevisions map[string][]*event.Revision
+
+	// Snapshot state, unused unless created by NewRAMStorageWithSnapshot
+	snapshotMu     sync.Mutex
+	snapshotPath   string
+	lastSnapshot   []byte
+	stopSnapshots  chan struct{}
+	snapshotsDone  chan struct{}
+	closeSnapshots sync.Once
+}
+
+// sortByGUID orders a listing by GUID, the order the other drivers read their storage in
+func sortByGUID[T any](items []T, guid func(T) string) {
+	sort.Slice(items, func(i, j int) bool { return guid(items[i]) < guid(items[j]) })
 }
 
 // NewRAMStorage creates a new in-memory storage driver
@@ -26,8 +45,10 @@ func NewRAMStorage() *RAMStorage {
 	log.Info("Initializing RAM storage driver")
 
 	storage := &RAMStorage{
-		incidents:           make(map[string]*event.Incident),
-		plannedMaintenances: make(map[string]*event.PlannedMaintenance),
+		incidents:                   make(map[string]*event.Incident),
+		plannedMaintenances:         make(map[string]*event.PlannedMaintenance),
+		incidentRevisions:           make(map[string][]*event.Revision),
+		plannedMaintenanceRevisions: make(map[string][]*event.Revision),
 	}
 
 	log.Info("RAM storage driver initialized successfully")
@@ -35,7 +56,11 @@ func NewRAMStorage() *RAMStorage {
 }
 
 // Incidents implementation
-func (r *RAMStorage) CreateIncident(incident *event.Incident) error {
+func (r *RAMStorage) CreateIncident(ctx context.Context, incident *event.Incident) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	log := logger.GetDefault().WithComponent("RAMStorage")
 	log.WithField("incident_id", incident.GUID).Info("Creating incident in RAM")
 
@@ -47,12 +72,24 @@ func (r *RAMStorage) CreateIncident(incident *event.Incident) error {
 		return ErrExists
 	}
 
+	previous := incident.Version
+	incident.Version = initialVersion(previous)
+	revision, err := newRevision(ctx, incident.GUID, event.RevisionCreated, incident.Version, nil, incident)
+	if err != nil {
+		incident.Version = previous
+		return err
+	}
 	r.incidents[incident.GUID] = incident
+	r.incidentRevisions[incident.GUID] = append(r.incidentRevisions[incident.GUID], revision)
 	log.WithField("incident_id", incident.GUID).WithField("total_incidents", len(r.incidents)).Info("Incident created successfully in RAM")
 	return nil
 }
 
-func (r *RAMStorage) GetIncident(id string) (*event.Incident, error) {
+func (r *RAMStorage) GetIncident(ctx context.Context, id string) (*event.Incident, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	log := logger.GetDefault().WithComponent("RAMStorage")
 	log.WithField("incident_id", id).Debug("Getting incident from RAM")
 
@@ -60,7 +97,7 @@ func (r *RAMStorage) GetIncident(id string) (*event.Incident, error) {
 	defer r.mu.RUnlock()
 
 	incident, exists := r.incidents[id]
-	if !exists {
+	if !exists || incident.IsDeleted() {
 		log.WithField("incident_id", id).Debug("Incident not found in RAM")
 		return nil, ErrNotFound
 	}
@@ -69,91 +106,332 @@ func (r *RAMStorage) GetIncident(id string) (*event.Incident, error) {
 	return incident, nil
 }
 
-func (r *RAMStorage) GetAllIncidents() ([]*event.Incident, error) {
+func (r *RAMStorage) GetAllIncidents(ctx context.Context) ([]*event.Incident, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	incidents := make([]*event.Incident, 0, len(r.incidents))
 	for _, incident := range r.incidents {
-		incidents = append(incidents, incident)
+		if !incident.IsDeleted() {
+			incidents = append(incidents, incident)
+		}
 	}
+	sortByGUID(incidents, func(i *event.Incident) string { return i.GUID })
 	return incidents, nil
 }
 
-func (r *RAMStorage) UpdateIncident(incident *event.Incident) error {
+// ListIncidents returns the incidents matching the filter
+func (r *RAMStorage) ListIncidents(ctx context.Context, filter EventFilter) (*IncidentPage, error) {
+	all, err := r.GetAllIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterIncidents(all, filter)
+}
+
+func (r *RAMStorage) UpdateIncident(ctx context.Context, incident *event.Incident) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.incidents[incident.GUID]; !exists {
+	existing, exists := r.incidents[incident.GUID]
+	if !exists || existing.IsDeleted() {
 		return ErrNotFound
 	}
+	version, err := nextVersion(existing.Version, incident.Version)
+	if err != nil {
+		return err
+	}
+	expected := incident.Version
+	incident.Version = version
+	revision, err := newRevision(ctx, incident.GUID, event.RevisionUpdated, version, existing, incident)
+	if err != nil {
+		incident.Version = expected
+		return err
+	}
 	r.incidents[incident.GUID] = incident
+	r.incidentRevisions[incident.GUID] = append(r.incidentRevisions[incident.GUID], revision)
 	return nil
 }
 
-func (r *RAMStorage) DeleteIncident(id string) error {
+func (r *RAMStorage) DeleteIncident(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.incidents[id]; !exists {
+	existing, exists := r.incidents[id]
+	if !exists || existing.IsDeleted() {
 		return ErrNotFound
 	}
-	delete(r.incidents, id)
+	deleted := *existing
+	setDeleted(ctx, &deleted.BaseEvent, true)
+	revision, err := newRevision(ctx, id, event.RevisionDeleted, deleted.Version, existing, &deleted)
+	if err != nil {
+		return err
+	}
+	r.incidents[id] = &deleted
+	r.incidentRevisions[id] = append(r.incidentRevisions[id], revision)
 	return nil
 }
 
+// GetDeletedIncidents returns the incidents in the trash, most recently deleted first
+func (r *RAMStorage) GetDeletedIncidents(ctx context.Context) ([]*event.Incident, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	incidents := []*event.Incident{}
+	for _, incident := range r.incidents {
+		if incident.IsDeleted() {
+			incidents = append(incidents, incident)
+		}
+	}
+	sortTrash(incidents, func(i *event.Incident) *time.Time { return i.DeletedAt })
+	return incidents, nil
+}
+
+// RestoreIncident takes an incident out of the trash
+func (r *RAMStorage) RestoreIncident(ctx context.Context, id string) (*event.Incident, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.incidents[id]
+	if !exists || !existing.IsDeleted() {
+		return nil, ErrNotFound
+	}
+	restored := *existing
+	setDeleted(ctx, &restored.BaseEvent, false)
+	revision, err := newRevision(ctx, id, event.RevisionRestored, restored.Version, existing, &restored)
+	if err != nil {
+		return nil, err
+	}
+	r.incidents[id] = &restored
+	r.incidentRevisions[id] = append(r.incidentRevisions[id], revision)
+	return &restored, nil
+}
+
+// GetIncidentRevisions returns the revision history of an incident, oldest first
+func (r *RAMStorage) GetIncidentRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]*event.Revision{}, r.incidentRevisions[id]...), nil
+}
+
 // Planned Maintenances implementation
-func (r *RAMStorage) CreatePlannedMaintenance(pm *event.PlannedMaintenance) error {
+func (r *RAMStorage) CreatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.plannedMaintenances[pm.GUID]; exists {
 		return ErrExists
 	}
+	previous := pm.Version
+	pm.Version = initialVersion(previous)
+	revision, err := newRevision(ctx, pm.GUID, event.RevisionCreated, pm.Version, nil, pm)
+	if err != nil {
+		pm.Version = previous
+		return err
+	}
 	r.plannedMaintenances[pm.GUID] = pm
+	r.plannedMaintenanceRevisions[pm.GUID] = append(r.plannedMaintenanceRevisions[pm.GUID], revision)
 	return nil
 }
 
-func (r *RAMStorage) GetPlannedMaintenance(id string) (*event.PlannedMaintenance, error) {
+func (r *RAMStorage) GetPlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	pm, exists := r.plannedMaintenances[id]
-	if !exists {
+	if !exists || pm.IsDeleted() {
 		return nil, ErrNotFound
 	}
 	return pm, nil
 }
 
-func (r *RAMStorage) GetAllPlannedMaintenances() ([]*event.PlannedMaintenance, error) {
+func (r *RAMStorage) GetAllPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	pms := make([]*event.PlannedMaintenance, 0, len(r.plannedMaintenances))
 	for _, pm := range r.plannedMaintenances {
-		pms = append(pms, pm)
+		if !pm.IsDeleted() {
+			pms = append(pms, pm)
+		}
 	}
+	sortByGUID(pms, func(pm *event.PlannedMaintenance) string { return pm.GUID })
 	return pms, nil
 }
 
-func (r *RAMStorage) UpdatePlannedMaintenance(pm *event.PlannedMaintenance) error {
+// ListPlannedMaintenances returns the planned maintenances matching the filter
+func (r *RAMStorage) ListPlannedMaintenances(ctx context.Context, filter EventFilter) (*PlannedMaintenancePage, error) {
+	all, err := r.GetAllPlannedMaintenances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterPlannedMaintenances(all, filter)
+}
+
+func (r *RAMStorage) UpdatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.plannedMaintenances[pm.GUID]; !exists {
+	existing, exists := r.plannedMaintenances[pm.GUID]
+	if !exists || existing.IsDeleted() {
 		return ErrNotFound
 	}
+	version, err := nextVersion(existing.Version, pm.Version)
+	if err != nil {
+		return err
+	}
+	expected := pm.Version
+	pm.Version = version
+	revision, err := newRevision(ctx, pm.GUID, event.RevisionUpdated, version, existing, pm)
+	if err != nil {
+		pm.Version = expected
+		return err
+	}
 	r.plannedMaintenances[pm.GUID] = pm
+	r.plannedMaintenanceRevisions[pm.GUID] = append(r.plannedMaintenanceRevisions[pm.GUID], revision)
 	return nil
 }
 
-func (r *RAMStorage) DeletePlannedMaintenance(id string) error {
+func (r *RAMStorage) DeletePlannedMaintenance(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.plannedMaintenances[id]; !exists {
+	existing, exists := r.plannedMaintenances[id]
+	if !exists || existing.IsDeleted() {
 		return ErrNotFound
 	}
-	delete(r.plannedMaintenances, id)
+	deleted := *existing
+	setDeleted(ctx, &deleted.BaseEvent, true)
+	revision, err := newRevision(ctx, id, event.RevisionDeleted, deleted.Version, existing, &deleted)
+	if err != nil {
+		return err
+	}
+	r.plannedMaintenances[id] = &deleted
+	r.plannedMaintenanceRevisions[id] = append(r.plannedMaintenanceRevisions[id], revision)
 	return nil
 }
+
+// GetDeletedPlannedMaintenances returns the planned maintenances in the trash, most recently deleted first
+func (r *RAMStorage) GetDeletedPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pms := []*event.PlannedMaintenance{}
+	for _, pm := range r.plannedMaintenances {
+		if pm.IsDeleted() {
+			pms = append(pms, pm)
+		}
+	}
+	sortTrash(pms, func(pm *event.PlannedMaintenance) *time.Time { return pm.DeletedAt })
+	return pms, nil
+}
+
+// RestorePlannedMaintenance takes a planned maintenance out of the trash
+func (r *RAMStorage) RestorePlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.plannedMaintenances[id]
+	if !exists || !existing.IsDeleted() {
+		return nil, ErrNotFound
+	}
+	restored := *existing
+	setDeleted(ctx, &restored.BaseEvent, false)
+	revision, err := newRevision(ctx, id, event.RevisionRestored, restored.Version, existing, &restored)
+	if err != nil {
+		return nil, err
+	}
+	r.plannedMaintenances[id] = &restored
+	r.plannedMaintenanceRevisions[id] = append(r.plannedMaintenanceRevisions[id], revision)
+	return &restored, nil
+}
+
+// GetPlannedMaintenanceRevisions returns the revision history of a planned maintenance, oldest first
+func (r *RAMStorage) GetPlannedMaintenanceRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]*event.Revision{}, r.plannedMaintenanceRevisions[id]...), nil
+}
+
+// PurgeDeleted permanently removes the events deleted before the given time
+func (r *RAMStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, incident := range r.incidents {
+		if purgeable(incident.DeletedAt, before) {
+			delete(r.incidents, id)
+			purged++
+		}
+	}
+	for id, pm := range r.plannedMaintenances {
+		if purgeable(pm.DeletedAt, before) {
+			delete(r.plannedMaintenances, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
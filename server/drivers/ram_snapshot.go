@@ -0,0 +1,197 @@
+package drivers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+)
+
+// Format and version of the RAM storage snapshots
+const (
+	RAMSnapshotFormat  = "clariti-ram-snapshot"
+	RAMSnapshotVersion = 1
+)
+
+// RAMConfig holds the RAM storage snapshot configuration
+type RAMConfig struct {
+	SnapshotPath     string        // File the events are saved to and reloaded from at startup
+	SnapshotInterval time.Duration // How often the snapshot is saved, only on Close when zero
+}
+
+// ramSnapshot is the document a RAM storage is saved to, events are sorted by GUID
+type ramSnapshot struct {
+	Format                      string                       `json:"format"`
+	Version                     int                          `json:"version"`
+	Incidents                   []*event.Incident            `json:"incidents"`
+	PlannedMaintenances         []*event.PlannedMaintenance  `json:"planned_maintenances"`
+	IncidentRevisions           map[string][]*event.Revision `json:"incident_revisions"`
+	PlannedMaintenanceRevisions map[string][]*event.Revision `json:"planned_maintenance_revisions"`
+}
+
+// NewRAMStorageWithSnapshot creates an in-memory storage driver persisted to a snapshot file.
+// The events, deleted ones and revisions included, are reloaded from the file when it exists, then saved
+// to it every SnapshotInterval when they changed and on Close. A crash loses the writes since the last snapshot.
+func NewRAMStorageWithSnapshot(cfg RAMConfig) (*RAMStorage, error) {
+	log := logger.GetDefault().WithComponent("RAMStorage")
+
+	if cfg.SnapshotPath == "" {
+		return nil, fmt.Errorf("snapshot path is required")
+	}
+
+	storage := NewRAMStorage()
+	storage.snapshotPath = cfg.SnapshotPath
+	if err := storage.loadSnapshot(); err != nil {
+		log.WithError(err).WithField("path", cfg.SnapshotPath).Error("Failed to load RAM snapshot")
+		return nil, err
+	}
+
+	storage.stopSnapshots = make(chan struct{})
+	storage.snapshotsDone = make(chan struct{})
+	go storage.saveSnapshotsPeriodically(cfg.SnapshotInterval)
+
+	log.WithField("path", cfg.SnapshotPath).WithField("interval", cfg.SnapshotInterval).Info("RAM snapshots enabled")
+	return storage, nil
+}
+
+// loadSnapshot replaces the events with the ones of the snapshot file, a missing file leaves the storage empty
+func (r *RAMStorage) loadSnapshot() error {
+	log := logger.GetDefault().WithComponent("RAMStorage")
+
+	data, err := os.ReadFile(r.snapshotPath)
+	if os.IsNotExist(err) {
+		log.WithField("path", r.snapshotPath).Info("No RAM snapshot found, starting empty")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot ramSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snapshot.Format != RAMSnapshotFormat {
+		return fmt.Errorf("unexpected snapshot format %q", snapshot.Format)
+	}
+	if snapshot.Version != RAMSnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, incident := range snapshot.Incidents {
+		r.incidents[incident.GUID] = incident
+	}
+	for _, pm := range snapshot.PlannedMaintenances {
+		r.plannedMaintenances[pm.GUID] = pm
+	}
+	for guid, revisions := range snapshot.IncidentRevisions {
+		r.incidentRevisions[guid] = revisions
+	}
+	for guid, revisions := range snapshot.PlannedMaintenanceRevisions {
+		r.plannedMaintenanceRevisions[guid] = revisions
+	}
+	r.lastSnapshot = data
+
+	log.WithField("incidents", len(r.incidents)).WithField("planned_maintenances", len(r.plannedMaintenances)).Info("RAM snapshot loaded")
+	return nil
+}
+
+// SaveSnapshot writes the events to the snapshot file, unless they did not change since the last snapshot.
+// It does nothing for storages created without a snapshot file.
+func (r *RAMStorage) SaveSnapshot() error {
+	if r.snapshotPath == "" {
+		return nil
+	}
+
+	r.snapshotMu.Lock()
+	defer r.snapshotMu.Unlock()
+
+	data, err := r.encodeSnapshot()
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, r.lastSnapshot) {
+		return nil
+	}
+	if err := writeFileAtomic(r.snapshotPath, data); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	r.lastSnapshot = data
+
+	logger.GetDefault().WithComponent("RAMStorage").WithField("path", r.snapshotPath).WithField("size_bytes", len(data)).Debug("RAM snapshot saved")
+	return nil
+}
+
+// encodeSnapshot returns the snapshot document of the current events
+func (r *RAMStorage) encodeSnapshot() ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := ramSnapshot{
+		Format:                      RAMSnapshotFormat,
+		Version:                     RAMSnapshotVersion,
+		Incidents:                   make([]*event.Incident, 0, len(r.incidents)),
+		PlannedMaintenances:         make([]*event.PlannedMaintenance, 0, len(r.plannedMaintenances)),
+		IncidentRevisions:           r.incidentRevisions,
+		PlannedMaintenanceRevisions: r.plannedMaintenanceRevisions,
+	}
+	for _, incident := range r.incidents {
+		snapshot.Incidents = append(snapshot.Incidents, incident)
+	}
+	for _, pm := range r.plannedMaintenances {
+		snapshot.PlannedMaintenances = append(snapshot.PlannedMaintenances, pm)
+	}
+	sort.Slice(snapshot.Incidents, func(i, j int) bool { return snapshot.Incidents[i].GUID < snapshot.Incidents[j].GUID })
+	sort.Slice(snapshot.PlannedMaintenances, func(i, j int) bool {
+		return snapshot.PlannedMaintenances[i].GUID < snapshot.PlannedMaintenances[j].GUID
+	})
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// saveSnapshotsPeriodically saves the snapshot every interval until Close, never when interval is not positive
+func (r *RAMStorage) saveSnapshotsPeriodically(interval time.Duration) {
+	defer close(r.snapshotsDone)
+	if interval <= 0 {
+		<-r.stopSnapshots
+		return
+	}
+
+	log := logger.GetDefault().WithComponent("RAMStorage")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stopSnapshots:
+			return
+		case <-ticker.C:
+			if err := r.SaveSnapshot(); err != nil {
+				log.WithError(err).Error("Failed to save RAM snapshot")
+			}
+		}
+	}
+}
+
+// Close stops the periodic snapshots and saves a last one. It does nothing for storages created without a snapshot file.
+func (r *RAMStorage) Close() error {
+	if r.snapshotPath == "" {
+		return nil
+	}
+	r.closeSnapshots.Do(func() {
+		close(r.stopSnapshots)
+		<-r.snapshotsDone
+	})
+	return r.SaveSnapshot()
+}
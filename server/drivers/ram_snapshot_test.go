@@ -0,0 +1,117 @@
+package drivers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/config"
+)
+
+func TestRAMStorage_Snapshot(t *testing.T) {
+	ctx := WithAuthor(context.Background(), "alice")
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+
+	storage, err := NewRAMStorageWithSnapshot(RAMConfig{SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("NewRAMStorageWithSnapshot failed: %v", err)
+	}
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	deleted := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	if err := storage.CreatePlannedMaintenance(ctx, deleted); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+	if err := storage.DeletePlannedMaintenance(ctx, deleted.GUID); err != nil {
+		t.Fatalf("DeletePlannedMaintenance failed: %v", err)
+	}
+	if err := storage.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reloaded, err := NewRAMStorageWithSnapshot(RAMConfig{SnapshotPath: path})
+	if err != nil {
+		t.Fatalf("Failed to reload the snapshot: %v", err)
+	}
+	defer reloaded.Close()
+	if stored, err := reloaded.GetIncident(ctx, incident.GUID); err != nil || stored.Title != incident.Title || stored.Version != 1 {
+		t.Errorf("Expected the incident reloaded at version 1, got %v", err)
+	}
+	if trash, err := reloaded.GetDeletedPlannedMaintenances(ctx); err != nil || len(trash) != 1 || trash[0].DeletedBy != "alice" {
+		t.Errorf("Expected the deleted maintenance reloaded in the trash, got %d, %v", len(trash), err)
+	}
+	if revisions, err := reloaded.GetPlannedMaintenanceRevisions(ctx, deleted.GUID); err != nil || len(revisions) != 2 {
+		t.Errorf("Expected 2 revisions reloaded, got %d, %v", len(revisions), err)
+	}
+}
+
+func TestRAMStorage_PeriodicSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	storage, err := NewRAMStorageWithSnapshot(RAMConfig{SnapshotPath: path, SnapshotInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewRAMStorageWithSnapshot failed: %v", err)
+	}
+	defer storage.Close()
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(context.Background(), incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	// The snapshot is written without waiting for Close
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		snapshot := NewRAMStorage()
+		snapshot.snapshotPath = path
+		if err := snapshot.loadSnapshot(); err == nil {
+			if _, err := snapshot.GetIncident(context.Background(), incident.GUID); err == nil {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the incident in a periodic snapshot")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRAMStorage_InvalidSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(`{"format":"something-else","version":1}`), 0o600); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	if _, err := NewRAMStorageWithSnapshot(RAMConfig{SnapshotPath: path}); err == nil {
+		t.Error("Expected an error for a snapshot of another format")
+	}
+}
+
+func TestNewStorage_RAMSnapshot(t *testing.T) {
+	cfg := &config.Config{
+		Storage: config.StorageConfig{
+			Driver: "ram",
+			RAM: config.RAMConfig{
+				SnapshotPath: filepath.Join(t.TempDir(), "snapshot.json"),
+			},
+		},
+	}
+
+	storage, err := NewStorage(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ram, ok := storage.(*RAMStorage)
+	if !ok {
+		t.Fatalf("expected RAMStorage, got %T", storage)
+	}
+	if err := ram.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(cfg.Storage.RAM.SnapshotPath); err != nil {
+		t.Errorf("Expected a snapshot written on Close, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+)
+
+// RetentionPolicy selects the ended events removed from a storage
+type RetentionPolicy struct {
+	// ResolvedIncidents is how long incidents are kept after their effective end, zero keeps them
+	ResolvedIncidents time.Duration
+	// CancelledMaintenances is how long cancelled maintenances are kept after their planned end, zero keeps them
+	CancelledMaintenances time.Duration
+	// Archive receives a copy of the removed events, they are only moved to the trash when nil
+	Archive EventStorage
+}
+
+// RetentionReport counts the events removed by a retention policy
+type RetentionReport struct {
+	Incidents           int `json:"incidents"`
+	PlannedMaintenances int `json:"planned_maintenances"`
+}
+
+// ApplyRetention removes the events past their retention at now from the storage.
+// Removed events are copied to the archive of the policy, keeping their GUID and version, then moved to
+// the trash of the storage, both attributed to AuthorFromContext(ctx). An event archived again after
+// being restored replaces its previous archived copy.
+func ApplyRetention(ctx context.Context, storage EventStorage, policy RetentionPolicy, now time.Time) (*RetentionReport, error) {
+	report := &RetentionReport{}
+
+	if policy.ResolvedIncidents > 0 {
+		cutoff := now.Add(-policy.ResolvedIncidents)
+		incidents, err := storage.GetAllIncidents(ctx)
+		if err != nil {
+			return report, fmt.Errorf("failed to read incidents: %w", err)
+		}
+		report.Incidents, err = retainKind(ctx, "incidents", incidents,
+			func(i *event.Incident) bool { return i.EndEffective != nil && i.EndEffective.Before(cutoff) },
+			func(i *event.Incident) string { return i.GUID },
+			func(i *event.Incident) error {
+				if policy.Archive == nil {
+					return nil
+				}
+				err := policy.Archive.CreateIncident(ctx, i)
+				if err == ErrExists {
+					archived := *i
+					archived.Version = 0
+					err = policy.Archive.UpdateIncident(ctx, &archived)
+				}
+				return err
+			},
+			func(id string) error { return storage.DeleteIncident(ctx, id) })
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if policy.CancelledMaintenances > 0 {
+		cutoff := now.Add(-policy.CancelledMaintenances)
+		maintenances, err := storage.GetAllPlannedMaintenances(ctx)
+		if err != nil {
+			return report, fmt.Errorf("failed to read planned maintenances: %w", err)
+		}
+		report.PlannedMaintenances, err = retainKind(ctx, "planned_maintenances", maintenances,
+			func(pm *event.PlannedMaintenance) bool { return pm.Cancelled && pm.EndPlanned.Before(cutoff) },
+			func(pm *event.PlannedMaintenance) string { return pm.GUID },
+			func(pm *event.PlannedMaintenance) error {
+				if policy.Archive == nil {
+					return nil
+				}
+				err := policy.Archive.CreatePlannedMaintenance(ctx, pm)
+				if err == ErrExists {
+					archived := *pm
+					archived.Version = 0
+					err = policy.Archive.UpdatePlannedMaintenance(ctx, &archived)
+				}
+				return err
+			},
+			func(id string) error { return storage.DeletePlannedMaintenance(ctx, id) })
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// retainKind archives then deletes the expired events of one kind and returns how many were removed
+func retainKind[T any](ctx context.Context, kind string, items []T,
+	expired func(T) bool, guid func(T) string, archive func(T) error, remove func(string) error) (int, error) {
+	log := logger.GetDefault().WithComponent("Retention").WithField("kind", kind)
+
+	removed := 0
+	for _, item := range items {
+		if !expired(item) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		if err := archive(item); err != nil {
+			return removed, fmt.Errorf("failed to archive %s %s: %w", kind, guid(item), err)
+		}
+		// An event deleted meanwhile has already left the storage
+		if err := remove(guid(item)); err != nil && err != ErrNotFound {
+			return removed, fmt.Errorf("failed to delete %s %s: %w", kind, guid(item), err)
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		log.WithField("removed", removed).Info("Events past their retention removed")
+	}
+	return removed, nil
+}
@@ -0,0 +1,132 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/config"
+)
+
+func TestApplyRetention(t *testing.T) {
+	ctx := WithAuthor(context.Background(), "retention")
+	storage := NewRAMStorage()
+	archive := NewRAMStorage()
+	now := time.Now()
+
+	ended := func(age time.Duration) *time.Time {
+		end := now.Add(-age)
+		return &end
+	}
+	old := event.NewFiringIncident("Old outage", "API down", nil, event.CriticalityMajorOutage)
+	old.EndEffective = ended(40 * 24 * time.Hour)
+	recent := event.NewFiringIncident("Recent outage", "API down", nil, event.CriticalityMajorOutage)
+	recent.EndEffective = ended(time.Hour)
+	ongoing := event.NewFiringIncident("Ongoing outage", "API down", nil, event.CriticalityMajorOutage)
+	for _, incident := range []*event.Incident{old, recent, ongoing} {
+		if err := storage.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+
+	cancelled := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, now.Add(-50*24*time.Hour), now.Add(-49*24*time.Hour))
+	cancelled.Cancelled = true
+	completed := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, now.Add(-50*24*time.Hour), now.Add(-49*24*time.Hour))
+	for _, pm := range []*event.PlannedMaintenance{cancelled, completed} {
+		if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+			t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+		}
+	}
+
+	policy := RetentionPolicy{ResolvedIncidents: 30 * 24 * time.Hour, CancelledMaintenances: 30 * 24 * time.Hour, Archive: archive}
+	report, err := ApplyRetention(ctx, storage, policy, now)
+	if err != nil {
+		t.Fatalf("ApplyRetention failed: %v", err)
+	}
+	if report.Incidents != 1 || report.PlannedMaintenances != 1 {
+		t.Errorf("Expected 1 incident and 1 planned maintenance removed, got %+v", report)
+	}
+
+	if _, err := storage.GetIncident(ctx, old.GUID); err != ErrNotFound {
+		t.Errorf("Expected the old incident removed, got %v", err)
+	}
+	if trash, _ := storage.GetDeletedIncidents(ctx); len(trash) != 1 || trash[0].DeletedBy != "retention" {
+		t.Errorf("Expected the old incident in the trash, got %d incidents", len(trash))
+	}
+	if incidents, _ := storage.GetAllIncidents(ctx); len(incidents) != 2 {
+		t.Errorf("Expected the recent and ongoing incidents kept, got %d", len(incidents))
+	}
+	if archived, err := archive.GetIncident(ctx, old.GUID); err != nil || archived.Version != old.Version {
+		t.Errorf("Expected the old incident archived at its version, got %v", err)
+	}
+	if _, err := archive.GetPlannedMaintenance(ctx, cancelled.GUID); err != nil {
+		t.Errorf("Expected the cancelled maintenance archived, got %v", err)
+	}
+	if _, err := storage.GetPlannedMaintenance(ctx, completed.GUID); err != nil {
+		t.Errorf("Expected the completed maintenance kept, got %v", err)
+	}
+
+	// A restored incident is archived again over its previous copy
+	restored, err := storage.RestoreIncident(ctx, old.GUID)
+	if err != nil {
+		t.Fatalf("RestoreIncident failed: %v", err)
+	}
+	restored.Title = "Old outage, restored"
+	if err := storage.UpdateIncident(ctx, restored); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	if report, err := ApplyRetention(ctx, storage, policy, now); err != nil || report.Incidents != 1 {
+		t.Fatalf("Expected the restored incident removed again, got %+v, %v", report, err)
+	}
+	if archived, err := archive.GetIncident(ctx, old.GUID); err != nil || archived.Title != restored.Title {
+		t.Errorf("Expected the archived copy replaced, got %v", err)
+	}
+}
+
+func TestApplyRetention_Delete(t *testing.T) {
+	ctx := context.Background()
+	storage := NewRAMStorage()
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	end := time.Now().Add(-2 * time.Hour)
+	incident.EndEffective = &end
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	report, err := ApplyRetention(ctx, storage, RetentionPolicy{ResolvedIncidents: time.Hour}, time.Now())
+	if err != nil || report.Incidents != 1 {
+		t.Fatalf("Expected the incident removed, got %+v, %v", report, err)
+	}
+	if trash, _ := storage.GetDeletedIncidents(ctx); len(trash) != 1 {
+		t.Errorf("Expected the incident in the trash, got %d incidents", len(trash))
+	}
+}
+
+func TestNewArchiveStorage_SQLite(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Storage: config.StorageConfig{
+			Driver: "sqlite",
+			SQLite: config.SQLiteConfig{
+				Path: filepath.Join(dir, "clariti.db"),
+			},
+		},
+	}
+
+	storage, err := NewArchiveStorage(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := storage.(*SQLiteStorage); !ok {
+		t.Fatalf("expected SQLiteStorage, got %T", storage)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "clariti-archive.db")); len(matches) != 1 {
+		t.Errorf("Expected the archive in a sibling database, got %v", matches)
+	}
+	if cfg.Storage.SQLite.Path != filepath.Join(dir, "clariti.db") {
+		t.Errorf("Expected the configuration left untouched, got %s", cfg.Storage.SQLite.Path)
+	}
+}
@@ -0,0 +1,38 @@
+package drivers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+type authorContextKey struct{}
+
+// WithAuthor returns a context attributing the writes made with it to author in the revision history
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorContextKey{}, author)
+}
+
+// AuthorFromContext returns the author set by WithAuthor, empty when unknown
+func AuthorFromContext(ctx context.Context) string {
+	author, _ := ctx.Value(authorContextKey{}).(string)
+	return author
+}
+
+// newRevision records a write to the event identified by guid.
+// before is nil for creations and after is nil for deletions.
+func newRevision(ctx context.Context, guid string, action event.RevisionAction, version int64, before, after interface{}) (*event.Revision, error) {
+	changes, err := event.Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+	return &event.Revision{
+		EventGUID: guid,
+		Version:   version,
+		Action:    action,
+		Author:    AuthorFromContext(ctx),
+		Timestamp: time.Now().UTC(),
+		Changes:   changes,
+	}, nil
+}
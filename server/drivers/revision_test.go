@@ -0,0 +1,77 @@
+package drivers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+func TestIncidentRevisions(t *testing.T) {
+	for _, driver := range versionTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			ctx := WithAuthor(context.Background(), "alice")
+
+			incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+			if err := storage.CreateIncident(ctx, incident); err != nil {
+				t.Fatalf("CreateIncident failed: %v", err)
+			}
+			updated := *incident
+			updated.Title = "Partial outage"
+			if err := storage.UpdateIncident(context.Background(), &updated); err != nil {
+				t.Fatalf("UpdateIncident failed: %v", err)
+			}
+			if err := storage.DeleteIncident(ctx, incident.GUID); err != nil {
+				t.Fatalf("DeleteIncident failed: %v", err)
+			}
+
+			revisions, err := storage.GetIncidentRevisions(ctx, incident.GUID)
+			if err != nil {
+				t.Fatalf("GetIncidentRevisions failed: %v", err)
+			}
+			if len(revisions) != 3 {
+				t.Fatalf("Expected 3 revisions, got %d", len(revisions))
+			}
+
+			expected := []struct {
+				action  event.RevisionAction
+				version int64
+				author  string
+			}{
+				{event.RevisionCreated, 1, "alice"},
+				{event.RevisionUpdated, 2, ""},
+				{event.RevisionDeleted, 3, "alice"},
+			}
+			for i, want := range expected {
+				got := revisions[i]
+				if got.Action != want.action || got.Version != want.version || got.Author != want.author {
+					t.Errorf("Revision %d: expected %s v%d by %q, got %s v%d by %q",
+						i, want.action, want.version, want.author, got.Action, got.Version, got.Author)
+				}
+				if got.EventGUID != incident.GUID {
+					t.Errorf("Revision %d: expected event %s, got %s", i, incident.GUID, got.EventGUID)
+				}
+			}
+
+			changes := revisions[1].Changes
+			if len(changes) != 1 || changes[0].Field != "title" || string(changes[0].After) != `"Partial outage"` {
+				t.Errorf("Expected a single title change in the update revision, got %+v", changes)
+			}
+		})
+	}
+}
+
+func TestPlannedMaintenanceRevisions_Unknown(t *testing.T) {
+	for _, driver := range versionTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			revisions, err := driver.storage(t).GetPlannedMaintenanceRevisions(context.Background(), "missing")
+			if err != nil {
+				t.Fatalf("GetPlannedMaintenanceRevisions failed: %v", err)
+			}
+			if len(revisions) != 0 {
+				t.Errorf("Expected no revisions for an unknown maintenance, got %d", len(revisions))
+			}
+		})
+	}
+}
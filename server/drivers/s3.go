@@ -4,14 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
 	"github.com/gmllt/clariti/logger"
 	"github.com/gmllt/clariti/models/event"
 )
@@ -22,6 +27,8 @@ type S3Storage struct {
 	bucket  string
 	prefix  string
 	timeout time.Duration
+	keyring *Keyring // nil unless objects are encrypted client-side
+	syncing sync.Map // categories whose index is being synced in the background
 }
 
 // S3Config holds S3-specific configuration
@@ -32,6 +39,7 @@ type S3Config struct {
 	SecretAccessKey string
 	Endpoint        string
 	Prefix          string
+	Keyring         *Keyring // Optional, encrypts every object before it is uploaded
 }
 
 // NewS3Storage creates a new S3 storage driver
@@ -91,13 +99,25 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 		prefix += "/"
 	}
 
-	log.WithField("prefix", prefix).Info("S3 storage driver initialized successfully")
-	return &S3Storage{
+	storage := &S3Storage{
 		client:  s3Client,
 		bucket:  cfg.Bucket,
 		prefix:  prefix,
 		timeout: 30 * time.Second,
-	}, nil
+		keyring: cfg.Keyring,
+	}
+	if cfg.Keyring != nil {
+		log.WithField("key_id", cfg.Keyring.ActiveKeyID()).Info("Client-side encryption enabled")
+	}
+	if err == nil {
+		// Creating a missing index reads every event, listings read the event objects until it is ready
+		for _, category := range s3Categories {
+			storage.syncIndexInBackground(category)
+		}
+	}
+
+	log.WithField("prefix", prefix).Info("S3 storage driver initialized successfully")
+	return storage, nil
 }
 
 // Helper methods for S3 operations
@@ -106,10 +126,16 @@ func (s *S3Storage) getKey(category, id string) string {
 	return fmt.Sprintf("%s%s/%s.json", s.prefix, category, id)
 }
 
+// objectName returns the key of an object relative to the prefix, which encrypted objects are bound to
+// so that they can be moved to another prefix
+func (s *S3Storage) objectName(key string) string {
+	return strings.TrimPrefix(key, s.prefix)
+}
+
 // createBucketIfNotExists creates the bucket if it doesn't exist
-func (s *S3Storage) createBucketIfNotExists() error {
+func (s *S3Storage) createBucketIfNotExists(ctx context.Context) error {
 	log := logger.GetDefault().WithComponent("S3Storage")
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
 	log.WithField("bucket", s.bucket).Info("Creating bucket")
@@ -129,40 +155,116 @@ func (s *S3Storage) createBucketIfNotExists() error {
 	return nil
 }
 
-func (s *S3Storage) putObject(key string, data interface{}) error {
+func (s *S3Storage) putObject(ctx context.Context, key string, data interface{}) error {
+	_, err := s.putObjectIfMatch(ctx, key, data, "")
+	return err
+}
+
+// putObjectIfMatch uploads data, only replacing the object if its ETag still matches when etag is not empty,
+// and returns the ETag of the new object. A lost race surfaces as ErrVersionConflict.
+func (s *S3Storage) putObjectIfMatch(ctx context.Context, key string, data interface{}, etag string) (string, error) {
+	return s.putObjectConditional(ctx, key, data, etag, "")
+}
+
+// putObjectIfAbsent uploads data unless an object already exists at key, in which case ErrExists is returned,
+// and returns the ETag of the new object
+func (s *S3Storage) putObjectIfAbsent(ctx context.Context, key string, data interface{}) (string, error) {
+	etag, err := s.putObjectConditional(ctx, key, data, "", "*")
+	if err == ErrVersionConflict {
+		return "", ErrExists
+	}
+	return etag, err
+}
+
+// putObjectConditional uploads data with the given If-Match and If-None-Match preconditions, ignored when empty,
+// and returns the ETag of the new object
+func (s *S3Storage) putObjectConditional(ctx context.Context, key string, data interface{}, ifMatch, ifNoneMatch string) (string, error) {
 	log := logger.GetDefault().WithComponent("S3Storage")
 	log.WithField("key", key).Debug("Putting object to S3")
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		log.WithError(err).WithField("key", key).Error("Failed to marshal data")
-		return fmt.Errorf("failed to marshal data: %w", err)
+		return "", fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	log.WithField("key", key).WithField("size_bytes", len(jsonData)).Debug("Uploading object to S3")
-	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+	input := &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
 		Key:         aws.String(key),
 		Body:        bytes.NewReader(jsonData),
 		ContentType: aws.String("application/json"),
-	})
+	}
+	if s.keyring != nil {
+		sealed, metadata, err := s.keyring.seal(s.objectName(key), jsonData)
+		if err != nil {
+			log.WithError(err).WithField("key", key).Error("Failed to encrypt data")
+			return "", fmt.Errorf("failed to encrypt object %s: %w", key, err)
+		}
+		input.Body = bytes.NewReader(sealed)
+		input.ContentType = aws.String("application/octet-stream")
+		input.Metadata = metadata
+	}
+	if ifMatch != "" {
+		input.IfMatch = aws.String(ifMatch)
+	}
+	if ifNoneMatch != "" {
+		input.IfNoneMatch = aws.String(ifNoneMatch)
+	}
+
+	log.WithField("key", key).WithField("size_bytes", len(jsonData)).Debug("Uploading object to S3")
+	result, err := s.client.PutObject(ctx, input)
 	if err != nil {
+		if isPreconditionFailed(err) {
+			log.WithField("key", key).Warn("Object was modified concurrently")
+			return "", ErrVersionConflict
+		}
 		log.WithError(err).WithField("key", key).Error("Failed to put object to S3")
-		return fmt.Errorf("failed to put object %s: %w", key, err)
+		return "", fmt.Errorf("failed to put object %s: %w", key, err)
 	}
 
 	log.WithField("key", key).Info("Object uploaded successfully")
-	return nil
+	return aws.ToString(result.ETag), nil
+}
+
+// isPreconditionFailed reports whether a conditional request failed because the object changed.
+// ConditionalRequestConflict is returned while a concurrent conditional write to the same key is in progress.
+func isPreconditionFailed(err error) bool {
+	return hasErrorCode(err, "PreconditionFailed", "ConditionalRequestConflict")
+}
+
+// isNotFound reports whether a request failed because the object does not exist
+func isNotFound(err error) bool {
+	return hasErrorCode(err, "NoSuchKey", "NotFound")
+}
+
+// hasErrorCode reports whether err is an S3 API error with one of the given codes
+func hasErrorCode(err error, codes ...string) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.ErrorCode() == code {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *S3Storage) getObject(key string, dest interface{}) error {
+func (s *S3Storage) getObject(ctx context.Context, key string, dest interface{}) error {
+	_, err := s.getObjectETag(ctx, key, dest)
+	return err
+}
+
+// getObjectETag decodes the object into dest and returns its ETag
+func (s *S3Storage) getObjectETag(ctx context.Context, key string, dest interface{}) (string, error) {
 	log := logger.GetDefault().WithComponent("S3Storage")
 	log.WithField("key", key).Debug("Getting object from S3")
 
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
 	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
@@ -170,46 +272,86 @@ func (s *S3Storage) getObject(key string, dest interface{}) error {
 		Key:    aws.String(key),
 	})
 	if err != nil {
-		// Check if it's a NoSuchKey error (object not found)
-		if strings.Contains(err.Error(), "NoSuchKey") || strings.Contains(err.Error(), "NotFound") {
+		if isNotFound(err) {
 			log.WithField("key", key).Debug("Object not found in S3")
-			return ErrNotFound
+			return "", ErrNotFound
 		}
 		log.WithError(err).WithField("key", key).Error("Failed to get object from S3")
-		return fmt.Errorf("failed to get object %s: %w", key, err)
+		return "", fmt.Errorf("failed to get object %s: %w", key, err)
 	}
 	defer result.Body.Close()
 
+	data, err := io.ReadAll(result.Body)
+	if err != nil {
+		log.WithError(err).WithField("key", key).Error("Failed to read object")
+		return "", fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	if s.keyring != nil {
+		if data, err = s.keyring.open(s.objectName(key), data, result.Metadata); err != nil {
+			log.WithError(err).WithField("key", key).Error("Failed to decrypt object")
+			return "", fmt.Errorf("failed to decrypt object %s: %w", key, err)
+		}
+	}
+
 	log.WithField("key", key).Debug("Decoding object from S3")
-	if err := json.NewDecoder(result.Body).Decode(dest); err != nil {
+	if err := json.Unmarshal(data, dest); err != nil {
 		log.WithError(err).WithField("key", key).Error("Failed to decode object")
-		return fmt.Errorf("failed to decode object %s: %w", key, err)
+		return "", fmt.Errorf("failed to decode object %s: %w", key, err)
 	}
 
 	log.WithField("key", key).Debug("Object retrieved successfully")
-	return nil
+	return aws.ToString(result.ETag), nil
 }
 
-func (s *S3Storage) deleteObject(key string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+func (s *S3Storage) deleteObject(ctx context.Context, key string) error {
+	return s.deleteObjectIfMatch(ctx, key, "")
+}
+
+// deleteObjectIfMatch deletes an object, only if its ETag still matches when etag is not empty.
+// A lost race surfaces as ErrVersionConflict.
+func (s *S3Storage) deleteObjectIfMatch(ctx context.Context, key, etag string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+	input := &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
-	})
-	if err != nil {
+	}
+	if etag != "" {
+		input.IfMatch = aws.String(etag)
+	}
+	if _, err := s.client.DeleteObject(ctx, input); err != nil {
+		if isPreconditionFailed(err) {
+			return ErrVersionConflict
+		}
+		if etag != "" && isNotFound(err) {
+			return ErrNotFound
+		}
 		return fmt.Errorf("failed to delete object %s: %w", key, err)
 	}
 
 	return nil
 }
 
-func (s *S3Storage) listObjects(prefix string) ([]string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+func (s *S3Storage) listObjects(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := s.listObjectETags(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// listObjectETags returns the ETag of every object under prefix, keyed by object key
+func (s *S3Storage) listObjectETags(ctx context.Context, prefix string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
 
-	var keys []string
+	objects := map[string]string{}
 	fullPrefix := s.prefix + prefix
 
 	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
@@ -225,36 +367,190 @@ func (s *S3Storage) listObjects(prefix string) ([]string, error) {
 
 		for _, obj := range page.Contents {
 			if obj.Key != nil {
-				keys = append(keys, *obj.Key)
+				objects[*obj.Key] = aws.ToString(obj.ETag)
 			}
 		}
 	}
 
-	return keys, nil
+	return objects, nil
+}
+
+// recordRevision stores a revision of the event next to the events, under revisions/<category>/<guid>/.
+// Keys start with the write time so that listing them returns the history in order.
+func (s *S3Storage) recordRevision(ctx context.Context, category, guid string, action event.RevisionAction, version int64, before, after interface{}) error {
+	revision, err := newRevision(ctx, guid, action, version, before, after)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%srevisions/%s/%s/%020d-%d.json", s.prefix, category, guid, revision.Timestamp.UnixNano(), version)
+	return s.putObject(ctx, key, revision)
+}
+
+// getRevisions returns the revisions of an event, oldest first
+func (s *S3Storage) getRevisions(ctx context.Context, category, guid string) ([]*event.Revision, error) {
+	keys, err := s.listObjects(ctx, fmt.Sprintf("revisions/%s/%s/", category, guid))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+
+	revisions := make([]*event.Revision, 0, len(keys))
+	for _, key := range keys {
+		var revision event.Revision
+		if err := s.getObject(ctx, key, &revision); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, nil
+}
+
+// loadDocuments returns the documents of the events of a category selected by keep.
+// A complete index selects the event objects to fetch, without it every event object is fetched
+// and callers filter the documents themselves.
+func (s *S3Storage) loadDocuments(ctx context.Context, category string, keep func(*s3IndexEntry) bool) ([]json.RawMessage, error) {
+	log := logger.GetDefault().WithComponent("S3Storage")
+
+	var keys []string
+	var entries map[string]*s3IndexEntry
+	if idx, ok := s.readableIndex(ctx, category); ok {
+		entries = idx.Events
+		for _, guid := range idx.guids(keep) {
+			keys = append(keys, s.getKey(category, guid))
+		}
+	} else {
+		var err error
+		if keys, err = s.listObjects(ctx, category+"/"); err != nil {
+			return nil, err
+		}
+	}
+
+	log.WithField("count", len(keys)).WithField("category", category).Info("Found event objects, loading data")
+	objects := s.getObjects(ctx, keys)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	documents := make([]json.RawMessage, 0, len(objects))
+	errorCount := 0
+	stale := false
+	for _, object := range objects {
+		if object.err == ErrNotFound {
+			// Purged since the index or the listing was read
+			stale = stale || entries != nil
+			continue
+		}
+		if object.err != nil {
+			log.WithError(object.err).WithField("key", object.key).Warn("Failed to load event, skipping")
+			errorCount++
+			continue
+		}
+		if entry, ok := entries[eventGUID(object.key)]; ok && entry.ETag != object.etag {
+			stale = true
+		}
+		documents = append(documents, object.document)
+	}
+
+	if stale {
+		// Written since the index was read, or missed by the index
+		s.syncIndexInBackground(category)
+	}
+	if errorCount > 0 {
+		log.WithField("errors", errorCount).WithField("loaded", len(documents)).Warn("Some events failed to load")
+	}
+	return documents, nil
+}
+
+// notDeleted selects the indexed events out of the trash
+func notDeleted(entry *s3IndexEntry) bool {
+	return entry.DeletedAt == nil
+}
+
+// inTrash selects the indexed events in the trash
+func inTrash(entry *s3IndexEntry) bool {
+	return entry.DeletedAt != nil
+}
+
+// trashObject moves the event stored at key to the trash and records the deletion.
+// The conditional put fails if another writer replaced the object since it was read.
+func (s *S3Storage) trashObject(ctx context.Context, category, key, guid string) error {
+	var stored json.RawMessage
+	etag, err := s.getObjectETag(ctx, key, &stored)
+	if err != nil {
+		return err
+	}
+	state, err := readTrashState(stored)
+	if err != nil {
+		return err
+	}
+	if state.DeletedAt != nil {
+		return ErrNotFound
+	}
+
+	deleted, err := markDeleted(ctx, stored, state.Version+1)
+	if err != nil {
+		return err
+	}
+	etag, err = s.putObjectIfMatch(ctx, key, deleted, etag)
+	if err != nil {
+		return err
+	}
+	s.indexEvent(ctx, category, guid, deleted, etag)
+	return s.recordRevision(ctx, category, guid, event.RevisionDeleted, state.Version+1, stored, deleted)
+}
+
+// restoreObject takes the event stored at key out of the trash, records it and returns the restored document
+func (s *S3Storage) restoreObject(ctx context.Context, category, key, guid string) (json.RawMessage, error) {
+	var stored json.RawMessage
+	etag, err := s.getObjectETag(ctx, key, &stored)
+	if err != nil {
+		return nil, err
+	}
+	state, err := readTrashState(stored)
+	if err != nil {
+		return nil, err
+	}
+	if state.DeletedAt == nil {
+		return nil, ErrNotFound
+	}
+
+	restored, err := markRestored(stored, state.Version+1)
+	if err != nil {
+		return nil, err
+	}
+	etag, err = s.putObjectIfMatch(ctx, key, restored, etag)
+	if err != nil {
+		return nil, err
+	}
+	s.indexEvent(ctx, category, guid, restored, etag)
+	if err := s.recordRevision(ctx, category, guid, event.RevisionRestored, state.Version+1, stored, restored); err != nil {
+		return nil, err
+	}
+	return restored, nil
 }
 
 // Incidents implementation
 
-func (s *S3Storage) CreateIncident(incident *event.Incident) error {
+func (s *S3Storage) CreateIncident(ctx context.Context, incident *event.Incident) error {
 	log := logger.GetDefault().WithComponent("S3Storage")
 	log.WithField("incident_id", incident.GUID).Info("Creating incident in S3")
 
-	// Check if incident already exists
+	// The conditional put fails if the incident exists, even when created concurrently by another server
 	key := s.getKey("incidents", incident.GUID)
-	log.WithField("incident_id", incident.GUID).WithField("key", key).Debug("Checking if incident exists")
-
-	var existing event.Incident
-	if err := s.getObject(key, &existing); err == nil {
-		log.WithField("incident_id", incident.GUID).Warn("Incident already exists")
-		return ErrExists
-	} else if err != ErrNotFound {
-		log.WithError(err).WithField("incident_id", incident.GUID).Error("Error checking incident existence")
+	previous := incident.Version
+	incident.Version = initialVersion(previous)
+	etag, err := s.putObjectIfAbsent(ctx, key, incident)
+	if err != nil {
+		incident.Version = previous
+		if err == ErrExists {
+			log.WithField("incident_id", incident.GUID).Warn("Incident already exists")
+		} else {
+			log.WithError(err).WithField("incident_id", incident.GUID).Error("Failed to create incident")
+		}
 		return err
 	}
-
-	log.WithField("incident_id", incident.GUID).Debug("Incident does not exist, proceeding with creation")
-	if err := s.putObject(key, incident); err != nil {
-		log.WithError(err).WithField("incident_id", incident.GUID).Error("Failed to create incident")
+	s.indexEvent(ctx, "incidents", incident.GUID, incident, etag)
+	if err := s.recordRevision(ctx, "incidents", incident.GUID, event.RevisionCreated, incident.Version, nil, incident); err != nil {
+		log.WithError(err).WithField("incident_id", incident.GUID).Error("Failed to record incident revision")
 		return err
 	}
 
@@ -262,142 +558,356 @@ func (s *S3Storage) CreateIncident(incident *event.Incident) error {
 	return nil
 }
 
-func (s *S3Storage) GetIncident(id string) (*event.Incident, error) {
+func (s *S3Storage) GetIncident(ctx context.Context, id string) (*event.Incident, error) {
 	key := s.getKey("incidents", id)
 	var incident event.Incident
-	if err := s.getObject(key, &incident); err != nil {
+	if err := s.getObject(ctx, key, &incident); err != nil {
 		return nil, err
 	}
+	if incident.IsDeleted() {
+		return nil, ErrNotFound
+	}
 	return &incident, nil
 }
 
-func (s *S3Storage) GetAllIncidents() ([]*event.Incident, error) {
+func (s *S3Storage) GetAllIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return s.loadIncidents(ctx, false, notDeleted)
+}
+
+// GetActiveIncidents returns the incidents that have not ended yet, only fetching those the index selects
+func (s *S3Storage) GetActiveIncidents(ctx context.Context) ([]*event.Incident, error) {
+	now := time.Now()
+	return s.loadIncidents(ctx, false, func(entry *s3IndexEntry) bool {
+		return notDeleted(entry) && entry.active(now)
+	})
+}
+
+// loadIncidents returns the incidents in the trash when deleted is true, the other ones otherwise.
+// keep preselects them from the index, the result may include incidents it would not keep.
+func (s *S3Storage) loadIncidents(ctx context.Context, deleted bool, keep func(*s3IndexEntry) bool) ([]*event.Incident, error) {
 	log := logger.GetDefault().WithComponent("S3Storage")
 	log.Info("Retrieving all incidents from S3")
 
-	keys, err := s.listObjects("incidents/")
+	documents, err := s.loadDocuments(ctx, "incidents", keep)
 	if err != nil {
-		log.WithError(err).Error("Failed to list incident objects")
+		log.WithError(err).Error("Failed to load incident objects")
 		return nil, err
 	}
 
-	log.WithField("count", len(keys)).Info("Found incident objects, loading data")
-	var incidents []*event.Incident
-	errorCount := 0
-
-	for _, key := range keys {
+	incidents := make([]*event.Incident, 0, len(documents))
+	for _, document := range documents {
 		var incident event.Incident
-		if err := s.getObject(key, &incident); err != nil {
-			log.WithError(err).WithField("key", key).Warn("Failed to load incident, skipping")
-			errorCount++
+		if err := json.Unmarshal(document, &incident); err != nil {
+			log.WithError(err).Warn("Failed to decode incident, skipping")
 			continue
 		}
-		incidents = append(incidents, &incident)
-	}
-
-	if errorCount > 0 {
-		log.WithField("errors", errorCount).WithField("loaded", len(incidents)).Warn("Some incidents failed to load")
+		if incident.IsDeleted() == deleted {
+			incidents = append(incidents, &incident)
+		}
 	}
 
 	log.WithField("count", len(incidents)).Info("All incidents retrieved successfully")
 	return incidents, nil
 }
 
-func (s *S3Storage) UpdateIncident(incident *event.Incident) error {
-	// Check if incident exists
+// ListIncidents returns the incidents matching the filter. S3 cannot filter server-side,
+// the index preselects the incidents to fetch and the filter is applied to them.
+func (s *S3Storage) ListIncidents(ctx context.Context, filter EventFilter) (*IncidentPage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	all, err := s.loadIncidents(ctx, false, func(entry *s3IndexEntry) bool {
+		return notDeleted(entry) && entry.matches(filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filterIncidents(all, filter)
+}
+
+func (s *S3Storage) UpdateIncident(ctx context.Context, incident *event.Incident) error {
+	// Check if incident exists and read the version the update is based on
 	key := s.getKey("incidents", incident.GUID)
 	var existing event.Incident
-	if err := s.getObject(key, &existing); err != nil {
-		if err == ErrNotFound {
-			return ErrNotFound
-		}
+	etag, err := s.getObjectETag(ctx, key, &existing)
+	if err != nil {
+		return err
+	}
+	if existing.IsDeleted() {
+		return ErrNotFound
+	}
+	version, err := nextVersion(existing.Version, incident.Version)
+	if err != nil {
 		return err
 	}
 
-	return s.putObject(key, incident)
+	// The conditional put fails if another writer replaced the object since it was read
+	expected := incident.Version
+	incident.Version = version
+	if etag, err = s.putObjectIfMatch(ctx, key, incident, etag); err != nil {
+		incident.Version = expected
+		return err
+	}
+	s.indexEvent(ctx, "incidents", incident.GUID, incident, etag)
+	return s.recordRevision(ctx, "incidents", incident.GUID, event.RevisionUpdated, version, &existing, incident)
 }
 
-func (s *S3Storage) DeleteIncident(id string) error {
-	// Check if incident exists
-	key := s.getKey("incidents", id)
-	var existing event.Incident
-	if err := s.getObject(key, &existing); err != nil {
-		if err == ErrNotFound {
-			return ErrNotFound
-		}
-		return err
+func (s *S3Storage) DeleteIncident(ctx context.Context, id string) error {
+	return s.trashObject(ctx, "incidents", s.getKey("incidents", id), id)
+}
+
+// GetDeletedIncidents returns the incidents in the trash, most recently deleted first
+func (s *S3Storage) GetDeletedIncidents(ctx context.Context) ([]*event.Incident, error) {
+	items, err := s.loadIncidents(ctx, true, inTrash)
+	if err != nil {
+		return nil, err
+	}
+	sortTrash(items, func(item *event.Incident) *time.Time { return item.DeletedAt })
+	return items, nil
+}
+
+// RestoreIncident takes an incident out of the trash
+func (s *S3Storage) RestoreIncident(ctx context.Context, id string) (*event.Incident, error) {
+	document, err := s.restoreObject(ctx, "incidents", s.getKey("incidents", id), id)
+	if err != nil {
+		return nil, err
 	}
+	var item event.Incident
+	if err := json.Unmarshal(document, &item); err != nil {
+		return nil, fmt.Errorf("failed to decode incident %s: %w", id, err)
+	}
+	return &item, nil
+}
 
-	return s.deleteObject(key)
+// GetIncidentRevisions returns the revision history of an incident, oldest first
+func (s *S3Storage) GetIncidentRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return s.getRevisions(ctx, "incidents", id)
 }
 
 // Planned Maintenances implementation
 
-func (s *S3Storage) CreatePlannedMaintenance(pm *event.PlannedMaintenance) error {
-	// Check if planned maintenance already exists
+func (s *S3Storage) CreatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	// The conditional put fails if the planned maintenance exists, even when created concurrently by another server
 	key := s.getKey("planned_maintenances", pm.GUID)
-	var existing event.PlannedMaintenance
-	if err := s.getObject(key, &existing); err == nil {
-		return ErrExists
-	} else if err != ErrNotFound {
+	previous := pm.Version
+	pm.Version = initialVersion(previous)
+	etag, err := s.putObjectIfAbsent(ctx, key, pm)
+	if err != nil {
+		pm.Version = previous
 		return err
 	}
-
-	return s.putObject(key, pm)
+	s.indexEvent(ctx, "planned_maintenances", pm.GUID, pm, etag)
+	return s.recordRevision(ctx, "planned_maintenances", pm.GUID, event.RevisionCreated, pm.Version, nil, pm)
 }
 
-func (s *S3Storage) GetPlannedMaintenance(id string) (*event.PlannedMaintenance, error) {
+func (s *S3Storage) GetPlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
 	key := s.getKey("planned_maintenances", id)
 	var pm event.PlannedMaintenance
-	if err := s.getObject(key, &pm); err != nil {
+	if err := s.getObject(ctx, key, &pm); err != nil {
 		return nil, err
 	}
+	if pm.IsDeleted() {
+		return nil, ErrNotFound
+	}
 	return &pm, nil
 }
 
-func (s *S3Storage) GetAllPlannedMaintenances() ([]*event.PlannedMaintenance, error) {
-	keys, err := s.listObjects("planned_maintenances/")
+func (s *S3Storage) GetAllPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return s.loadPlannedMaintenances(ctx, false, notDeleted)
+}
+
+// GetActivePlannedMaintenances returns the planned maintenances that have not ended yet,
+// only fetching those the index selects
+func (s *S3Storage) GetActivePlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	now := time.Now()
+	return s.loadPlannedMaintenances(ctx, false, func(entry *s3IndexEntry) bool {
+		return notDeleted(entry) && entry.active(now)
+	})
+}
+
+// loadPlannedMaintenances returns the planned maintenances in the trash when deleted is true, the other ones otherwise.
+// keep preselects them from the index, the result may include planned maintenances it would not keep.
+func (s *S3Storage) loadPlannedMaintenances(ctx context.Context, deleted bool, keep func(*s3IndexEntry) bool) ([]*event.PlannedMaintenance, error) {
+	documents, err := s.loadDocuments(ctx, "planned_maintenances", keep)
 	if err != nil {
 		return nil, err
 	}
 
-	var pms []*event.PlannedMaintenance
-	for _, key := range keys {
+	pms := make([]*event.PlannedMaintenance, 0, len(documents))
+	for _, document := range documents {
 		var pm event.PlannedMaintenance
-		if err := s.getObject(key, &pm); err != nil {
+		if err := json.Unmarshal(document, &pm); err != nil {
 			// Log error but continue with other planned maintenances
 			continue
 		}
-		pms = append(pms, &pm)
+		if pm.IsDeleted() == deleted {
+			pms = append(pms, &pm)
+		}
 	}
 
 	return pms, nil
 }
 
-func (s *S3Storage) UpdatePlannedMaintenance(pm *event.PlannedMaintenance) error {
-	// Check if planned maintenance exists
+// ListPlannedMaintenances returns the planned maintenances matching the filter. S3 cannot filter server-side,
+// the index preselects the planned maintenances to fetch and the filter is applied to them.
+func (s *S3Storage) ListPlannedMaintenances(ctx context.Context, filter EventFilter) (*PlannedMaintenancePage, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	all, err := s.loadPlannedMaintenances(ctx, false, func(entry *s3IndexEntry) bool {
+		return notDeleted(entry) && entry.matches(filter)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return filterPlannedMaintenances(all, filter)
+}
+
+func (s *S3Storage) UpdatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	// Check if planned maintenance exists and read the version the update is based on
 	key := s.getKey("planned_maintenances", pm.GUID)
 	var existing event.PlannedMaintenance
-	if err := s.getObject(key, &existing); err != nil {
-		if err == ErrNotFound {
-			return ErrNotFound
-		}
+	etag, err := s.getObjectETag(ctx, key, &existing)
+	if err != nil {
+		return err
+	}
+	if existing.IsDeleted() {
+		return ErrNotFound
+	}
+	version, err := nextVersion(existing.Version, pm.Version)
+	if err != nil {
 		return err
 	}
 
-	return s.putObject(key, pm)
+	// The conditional put fails if another writer replaced the object since it was read
+	expected := pm.Version
+	pm.Version = version
+	if etag, err = s.putObjectIfMatch(ctx, key, pm, etag); err != nil {
+		pm.Version = expected
+		return err
+	}
+	s.indexEvent(ctx, "planned_maintenances", pm.GUID, pm, etag)
+	return s.recordRevision(ctx, "planned_maintenances", pm.GUID, event.RevisionUpdated, version, &existing, pm)
 }
 
-func (s *S3Storage) DeletePlannedMaintenance(id string) error {
-	// Check if planned maintenance exists
-	key := s.getKey("planned_maintenances", id)
-	var existing event.PlannedMaintenance
-	if err := s.getObject(key, &existing); err != nil {
-		if err == ErrNotFound {
-			return ErrNotFound
+func (s *S3Storage) DeletePlannedMaintenance(ctx context.Context, id string) error {
+	return s.trashObject(ctx, "planned_maintenances", s.getKey("planned_maintenances", id), id)
+}
+
+// GetDeletedPlannedMaintenances returns the planned maintenances in the trash, most recently deleted first
+func (s *S3Storage) GetDeletedPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	items, err := s.loadPlannedMaintenances(ctx, true, inTrash)
+	if err != nil {
+		return nil, err
+	}
+	sortTrash(items, func(item *event.PlannedMaintenance) *time.Time { return item.DeletedAt })
+	return items, nil
+}
+
+// RestorePlannedMaintenance takes a planned maintenance out of the trash
+func (s *S3Storage) RestorePlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	document, err := s.restoreObject(ctx, "planned_maintenances", s.getKey("planned_maintenances", id), id)
+	if err != nil {
+		return nil, err
+	}
+	var item event.PlannedMaintenance
+	if err := json.Unmarshal(document, &item); err != nil {
+		return nil, fmt.Errorf("failed to decode planned maintenance %s: %w", id, err)
+	}
+	return &item, nil
+}
+
+// GetPlannedMaintenanceRevisions returns the revision history of a planned maintenance, oldest first
+func (s *S3Storage) GetPlannedMaintenanceRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return s.getRevisions(ctx, "planned_maintenances", id)
+}
+
+// purgeObject deletes the event stored at key if it is still in the trash since before the given time.
+// The conditional delete leaves the event alone if another writer restored it meanwhile.
+func (s *S3Storage) purgeObject(ctx context.Context, key string, before time.Time) (bool, error) {
+	var stored json.RawMessage
+	etag, err := s.getObjectETag(ctx, key, &stored)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	state, err := readTrashState(stored)
+	if err != nil {
+		return false, err
+	}
+	if !purgeable(state.DeletedAt, before) {
+		return false, nil
+	}
+
+	switch err := s.deleteObjectIfMatch(ctx, key, etag); err {
+	case nil:
+		return true, nil
+	case ErrVersionConflict, ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// PurgeDeleted permanently removes the events deleted before the given time
+func (s *S3Storage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	log := logger.GetDefault().WithComponent("S3Storage")
+
+	purged := 0
+	for _, category := range s3Categories {
+		documents, err := s.loadDocuments(ctx, category, func(entry *s3IndexEntry) bool {
+			return purgeable(entry.DeletedAt, before)
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		var guids []string
+		for _, document := range documents {
+			var tombstone struct {
+				trashState
+				GUID string `json:"guid"`
+			}
+			if err := json.Unmarshal(document, &tombstone); err != nil {
+				log.WithError(err).WithField("category", category).Warn("Failed to decode event, skipping")
+				continue
+			}
+			if purgeable(tombstone.DeletedAt, before) {
+				guids = append(guids, tombstone.GUID)
+			}
+		}
+
+		var removed []string
+		var purgeErr error
+		for _, guid := range guids {
+			var deleted bool
+			if deleted, purgeErr = s.purgeObject(ctx, s.getKey(category, guid), before); purgeErr != nil {
+				break
+			}
+			if deleted {
+				removed = append(removed, guid)
+			}
+		}
+		if len(removed) > 0 {
+			err := s.updateIndex(ctx, category, func(idx *s3Index) (bool, error) {
+				for _, guid := range removed {
+					delete(idx.Events, guid)
+				}
+				return true, nil
+			})
+			if err != nil {
+				// The sync removes the entries of purged objects
+				s.syncIndexInBackground(category)
+			}
+		}
+		purged += len(removed)
+		if purgeErr != nil {
+			return purged, purgeErr
 		}
-		return err
 	}
 
-	return s.deleteObject(key)
+	log.WithField("count", purged).Info("Purged deleted events")
+	return purged, nil
 }
@@ -0,0 +1,178 @@
+package drivers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ErrUnknownKey is returned when an object is encrypted with a key missing from the keyring
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Object metadata of the S3 client-side encryption, stored as x-amz-meta-* headers
+const (
+	metadataKeyID   = "clariti-key-id"   // ID of the master key the data key is wrapped with
+	metadataDataKey = "clariti-data-key" // Base64 of the nonce and the wrapped data key
+)
+
+// dataKeySize is the size of the AES-256 key generated for every object
+const dataKeySize = 32
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Keyring holds the master keys of the S3 client-side encryption.
+// The active key wraps the data key of every written object, the other ones are only used to read objects
+// written before a rotation.
+type Keyring struct {
+	keys   map[string]cipher.AEAD
+	active string
+}
+
+// ParseKeyring parses master keys given as "id:base64-key" entries separated by newlines or commas.
+// The first entry is the active key, blank lines and lines starting with # are ignored.
+// Keys must decode to 16, 24 or 32 bytes.
+func ParseKeyring(data string) (*Keyring, error) {
+	keyring := &Keyring{keys: make(map[string]cipher.AEAD)}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, entry := range strings.Split(line, ",") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			id, encoded, ok := strings.Cut(entry, ":")
+			if !ok || !keyIDPattern.MatchString(id) {
+				return nil, fmt.Errorf("invalid key entry, expected id:base64-key with an id made of letters, digits, '.', '_' or '-'")
+			}
+			if _, exists := keyring.keys[id]; exists {
+				return nil, fmt.Errorf("duplicate key id %s", id)
+			}
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %s: %w", id, err)
+			}
+			aead, err := newAEAD(key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %s: %w", id, err)
+			}
+			keyring.keys[id] = aead
+			if keyring.active == "" {
+				keyring.active = id
+			}
+		}
+	}
+	if keyring.active == "" {
+		return nil, fmt.Errorf("no encryption key found")
+	}
+	return keyring, nil
+}
+
+// LoadKeyring reads the master keys from keyFile, or from the environment variable envVar when keyFile is empty
+func LoadKeyring(keyFile, envVar string) (*Keyring, error) {
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+		return ParseKeyring(string(data))
+	}
+	data, ok := os.LookupEnv(envVar)
+	if !ok {
+		return nil, fmt.Errorf("encryption key variable %s is not set", envVar)
+	}
+	return ParseKeyring(data)
+}
+
+// ActiveKeyID returns the ID of the key new objects are encrypted with
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a new data key wrapped by the active key and returns the sealed body and its metadata.
+// The object name is authenticated so that a sealed body cannot be swapped with the one of another object.
+func (k *Keyring) seal(name string, plaintext []byte) ([]byte, map[string]string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := sealWith(aead, plaintext, []byte(name))
+	if err != nil {
+		return nil, nil, err
+	}
+	wrapped, err := sealWith(k.keys[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, map[string]string{
+		metadataKeyID:   k.active,
+		metadataDataKey: base64.StdEncoding.EncodeToString(wrapped),
+	}, nil
+}
+
+// open decrypts a body sealed for the object name with any key of the keyring.
+// Bodies without encryption metadata, written before encryption was enabled, are returned as is.
+func (k *Keyring) open(name string, body []byte, metadata map[string]string) ([]byte, error) {
+	id, ok := metadata[metadataKeyID]
+	if !ok {
+		return body, nil
+	}
+	master, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownKey, id)
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(metadata[metadataDataKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	dataKey, err := openWith(master, wrapped, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	plaintext, err := openWith(aead, body, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt object: %w", err)
+	}
+	return plaintext, nil
+}
+
+// sealWith encrypts plaintext with a random nonce, prepended to the ciphertext
+func sealWith(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// openWith decrypts data produced by sealWith
+func openWith(aead cipher.AEAD, data, additionalData []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,130 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+// newTestKey returns a random key entry for ParseKeyring
+func newTestKey(t *testing.T, id string) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(key)
+}
+
+func TestParseKeyring(t *testing.T) {
+	keyring, err := ParseKeyring("# rotated keys\n" + newTestKey(t, "2026-10") + "\n\n" + newTestKey(t, "2026-01") + "," + newTestKey(t, "2025-07"))
+	if err != nil {
+		t.Fatalf("ParseKeyring failed: %v", err)
+	}
+	if keyring.ActiveKeyID() != "2026-10" || len(keyring.keys) != 3 {
+		t.Errorf("Expected 3 keys with 2026-10 active, got %d with %s active", len(keyring.keys), keyring.ActiveKeyID())
+	}
+
+	invalid := map[string]string{
+		"empty":          "# no key\n",
+		"missing id":     base64.StdEncoding.EncodeToString(make([]byte, 32)),
+		"invalid id":     "key 1:" + base64.StdEncoding.EncodeToString(make([]byte, 32)),
+		"invalid base64": "k1:not base64",
+		"invalid size":   "k1:" + base64.StdEncoding.EncodeToString(make([]byte, 20)),
+		"duplicate id":   newTestKey(t, "k1") + "," + newTestKey(t, "k1"),
+	}
+	for name, data := range invalid {
+		if _, err := ParseKeyring(data); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestS3Storage_Encryption(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeS3(t)
+	oldKey, newKey := newTestKey(t, "k1"), newTestKey(t, "k2")
+	keyring, err := ParseKeyring(oldKey)
+	if err != nil {
+		t.Fatalf("ParseKeyring failed: %v", err)
+	}
+	storage := fake.storage(t, keyring)
+
+	incident := event.NewFiringIncident("Outage", "Database password leaked in logs", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	key := storage.getKey("incidents", incident.GUID)
+	for _, objectKey := range fake.keys("") {
+		object, _ := fake.object(objectKey)
+		if bytes.Contains(object.data, []byte("password")) || object.metadata.Get("X-Amz-Meta-Clariti-Key-Id") != "k1" {
+			t.Errorf("Expected %s encrypted with k1, got key %q", objectKey, object.metadata.Get("X-Amz-Meta-Clariti-Key-Id"))
+		}
+	}
+	if stored, err := storage.GetIncident(ctx, incident.GUID); err != nil || stored.Content != incident.Content {
+		t.Errorf("Expected the incident decrypted, got %v", err)
+	}
+
+	// Without the keys the objects cannot be read
+	if _, err := fake.storage(t, nil).GetIncident(ctx, incident.GUID); err == nil {
+		t.Errorf("Expected an error reading an encrypted object without keys")
+	}
+
+	// After a rotation old objects are still readable and writes use the new key
+	rotated, err := ParseKeyring(newKey + "\n" + oldKey)
+	if err != nil {
+		t.Fatalf("ParseKeyring failed: %v", err)
+	}
+	storage = fake.storage(t, rotated)
+	stored, err := storage.GetIncident(ctx, incident.GUID)
+	if err != nil {
+		t.Fatalf("Expected the incident readable with the old key, got %v", err)
+	}
+	stored.Title = "Outage, rotated"
+	if err := storage.UpdateIncident(ctx, stored); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	if object, _ := fake.object(key); object.metadata.Get("X-Amz-Meta-Clariti-Key-Id") != "k2" {
+		t.Errorf("Expected the incident re-encrypted with k2, got %q", object.metadata.Get("X-Amz-Meta-Clariti-Key-Id"))
+	}
+
+	retired, err := ParseKeyring(newKey)
+	if err != nil {
+		t.Fatalf("ParseKeyring failed: %v", err)
+	}
+	storage = fake.storage(t, retired)
+	if stored, err := storage.GetIncident(ctx, incident.GUID); err != nil || stored.Title != "Outage, rotated" {
+		t.Errorf("Expected the rewritten incident readable without the old key, got %v", err)
+	}
+	if _, err := storage.GetIncidentRevisions(ctx, incident.GUID); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey for revisions encrypted with the retired key, got %v", err)
+	}
+}
+
+func TestS3Storage_EncryptionReadsPlaintext(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeS3(t)
+
+	pm := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	if err := fake.storage(t, nil).CreatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+
+	keyring, err := ParseKeyring(newTestKey(t, "k1"))
+	if err != nil {
+		t.Fatalf("ParseKeyring failed: %v", err)
+	}
+	storage := fake.storage(t, keyring)
+	if _, err := storage.GetPlannedMaintenance(ctx, pm.GUID); err != nil {
+		t.Errorf("Expected objects written before encryption readable, got %v", err)
+	}
+	if maintenances, err := storage.GetAllPlannedMaintenances(ctx); err != nil || len(maintenances) != 1 {
+		t.Errorf("Expected the plaintext maintenance listed, got %d, %v", len(maintenances), err)
+	}
+}
@@ -0,0 +1,238 @@
+package drivers
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is an in-process S3 server holding a single bucket in memory.
+// It implements the requests the S3 driver sends, including the If-Match and If-None-Match preconditions.
+type fakeS3 struct {
+	mu      sync.Mutex
+	url     string
+	bucket  string
+	objects map[string]fakeS3Object
+}
+
+type fakeS3Object struct {
+	data     []byte
+	etag     string
+	metadata http.Header // x-amz-meta-* headers
+}
+
+// newTestS3Storage returns an S3 driver backed by a new fake S3 server
+func newTestS3Storage(t *testing.T) (*S3Storage, *fakeS3) {
+	t.Helper()
+	fake := newFakeS3(t)
+	return fake.storage(t, nil), fake
+}
+
+// newFakeS3 starts a fake S3 server, stopped at the end of the test
+func newFakeS3(t *testing.T) *fakeS3 {
+	t.Helper()
+	fake := &fakeS3{bucket: "clariti", objects: map[string]fakeS3Object{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.url = server.URL
+	return fake
+}
+
+// storage returns a new S3 driver using the fake server, encrypting objects when keyring is not nil
+func (f *fakeS3) storage(t *testing.T, keyring *Keyring) *S3Storage {
+	t.Helper()
+	storage, err := NewS3Storage(S3Config{
+		Region:          "us-east-1",
+		Bucket:          f.bucket,
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+		Endpoint:        f.url,
+		Keyring:         keyring,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create S3 storage: %v", err)
+	}
+	waitForIndexSync(t, storage)
+	return storage
+}
+
+// waitForIndexSync waits for the background index syncs of the storage to finish
+func waitForIndexSync(t *testing.T, storage *S3Storage) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		syncing := false
+		storage.syncing.Range(func(key, value any) bool {
+			syncing = true
+			return false
+		})
+		if !syncing {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Index sync did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		f.writeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if key == "" {
+		if r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+			f.list(w, r.URL.Query().Get("prefix"))
+			return
+		}
+		f.writeError(w, http.StatusNotImplemented, "NotImplemented")
+		return
+	}
+
+	stored, exists := f.objects[key]
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		checksum := make([]byte, 4)
+		binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(stored.data))
+		w.Header().Set("ETag", stored.etag)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amz-Checksum-Crc32", base64.StdEncoding.EncodeToString(checksum))
+		for name, values := range stored.metadata {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(stored.data)
+		}
+	case http.MethodPut:
+		if !f.preconditions(w, r, stored, exists) {
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			f.writeError(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		sum := md5.Sum(data)
+		object := fakeS3Object{data: data, etag: `"` + hex.EncodeToString(sum[:]) + `"`, metadata: http.Header{}}
+		for name, values := range r.Header {
+			if strings.HasPrefix(name, "X-Amz-Meta-") {
+				object.metadata[name] = values
+			}
+		}
+		f.objects[key] = object
+		w.Header().Set("ETag", object.etag)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		if !f.preconditions(w, r, stored, exists) {
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// preconditions checks the If-Match and If-None-Match headers of a write, writing the error response when they fail
+func (f *fakeS3) preconditions(w http.ResponseWriter, r *http.Request, stored fakeS3Object, exists bool) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch == "*" && exists {
+		f.writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+		return false
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if !exists {
+			f.writeError(w, http.StatusNotFound, "NoSuchKey")
+			return false
+		}
+		if ifMatch != stored.etag {
+			f.writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return false
+		}
+	}
+	return true
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	type content struct {
+		Key  string
+		ETag string
+		Size int
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		IsTruncated bool
+		Contents    []content
+	}{Name: f.bucket, Prefix: prefix}
+
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		result.Contents = append(result.Contents, content{Key: key, ETag: f.objects[key].etag, Size: len(f.objects[key].data)})
+	}
+	result.KeyCount = len(keys)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	xml.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeS3) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+// object returns the stored object at key
+func (f *fakeS3) object(key string) (fakeS3Object, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[key]
+	return object, ok
+}
+
+// keys returns the stored object keys with the given prefix
+func (f *fakeS3) keys(prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
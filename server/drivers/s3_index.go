@@ -0,0 +1,456 @@
+package drivers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+)
+
+const (
+	// s3IndexFormat is bumped whenever the index layout changes, older indexes are replaced by the next sync
+	s3IndexFormat = 2
+	// s3IndexUpdateAttempts bounds the retries of an index update losing the race against other writers
+	s3IndexUpdateAttempts = 10
+	// s3IndexRetryDelay spaces the retries of an index update, growing with each attempt and randomized
+	s3IndexRetryDelay = 20 * time.Millisecond
+	// s3IndexSyncTimeout bounds a background sync, which reads every event object of a new index
+	s3IndexSyncTimeout = 10 * time.Minute
+	// s3FetchConcurrency bounds the event objects fetched at the same time
+	s3FetchConcurrency = 16
+)
+
+// errOutdatedIndex is returned when the stored index has an older format
+var errOutdatedIndex = errors.New("outdated index")
+
+// s3Categories are the event categories stored in the bucket, each one with its own index
+var s3Categories = []string{"incidents", "planned_maintenances"}
+
+// s3Index is the manifest of a category: a compact entry per event keyed by GUID, deleted ones included.
+// The event objects stay the source of truth, entries only select the objects a listing or the weather loads.
+// An incomplete index is being created and is not used for reading until a sync catches up with the writes
+// made meanwhile.
+type s3Index struct {
+	Format    int                      `json:"format"`
+	Complete  bool                     `json:"complete"`
+	UpdatedAt time.Time                `json:"updated_at"`
+	Events    map[string]*s3IndexEntry `json:"events"`
+}
+
+// s3IndexEntry holds the ETag of an event object and the fields filters select on without reading it
+type s3IndexEntry struct {
+	ETag         string            `json:"etag"`
+	Version      int64             `json:"version"`
+	Type         event.TypeEvent   `json:"type"`
+	Criticality  event.Criticality `json:"criticality"`
+	Components   []s3IndexPath     `json:"components,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Start        *time.Time        `json:"start,omitempty"` // Time range the event is listed over
+	End          *time.Time        `json:"end,omitempty"`
+	EndEffective *time.Time        `json:"end_effective,omitempty"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// s3IndexPath is the location of a component of an indexed event
+type s3IndexPath struct {
+	Platform  string `json:"platform,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Component string `json:"component"`
+}
+
+func newS3Index() *s3Index {
+	return &s3Index{Format: s3IndexFormat, Events: map[string]*s3IndexEntry{}}
+}
+
+// newS3IndexEntry builds the entry of the document of an event object of a category
+func newS3IndexEntry(category string, document json.RawMessage, etag string) (*s3IndexEntry, error) {
+	entry := &s3IndexEntry{ETag: etag}
+	var base *event.BaseEvent
+	if category == "incidents" {
+		var incident event.Incident
+		if err := json.Unmarshal(document, &incident); err != nil {
+			return nil, fmt.Errorf("failed to decode incident: %w", err)
+		}
+		entry.Type, entry.Criticality = incident.Type(), incident.Criticality()
+		entry.Start, entry.End = incident.StartEffective, incident.EndEffective
+		base = &incident.BaseEvent
+	} else {
+		var pm event.PlannedMaintenance
+		if err := json.Unmarshal(document, &pm); err != nil {
+			return nil, fmt.Errorf("failed to decode planned maintenance: %w", err)
+		}
+		entry.Type, entry.Criticality = pm.Type(), pm.Criticality()
+		entry.Start, entry.End = plannedMaintenanceRange(&pm)
+		base = &pm.BaseEvent
+	}
+
+	entry.Version = base.Version
+	entry.Labels = base.Labels
+	entry.EndEffective = base.EndEffective
+	entry.DeletedAt = base.DeletedAt
+	for _, comp := range base.Components {
+		if comp == nil {
+			continue
+		}
+		location := s3IndexPath{Component: comp.Code}
+		if comp.Instance != nil {
+			location.Instance = comp.Instance.Code
+			if comp.Instance.Platform != nil {
+				location.Platform = comp.Instance.Platform.Code
+			}
+		}
+		entry.Components = append(entry.Components, location)
+	}
+	return entry, nil
+}
+
+// matches reports whether the event may match the filter, the criteria depending on the current time
+// or the text being left to the loaded events
+func (e *s3IndexEntry) matches(filter EventFilter) bool {
+	components := make([]*component.Component, len(e.Components))
+	for i, location := range e.Components {
+		components[i] = component.NewComponent("", location.Component,
+			component.NewInstance("", location.Instance, component.NewPlatform("", location.Platform)))
+	}
+	return filter.matchesStored(e.Type, e.Criticality, components, e.Labels, e.Start, e.End)
+}
+
+// active reports whether the event has not ended at now, like the active selection of the SQLite driver
+func (e *s3IndexEntry) active(now time.Time) bool {
+	return e.EndEffective == nil || !e.EndEffective.Before(now)
+}
+
+// apply records the entry of an event. It returns false when the index already holds it,
+// or a newer version written by a concurrent writer.
+func (idx *s3Index) apply(guid string, entry *s3IndexEntry) bool {
+	if indexed, ok := idx.Events[guid]; ok {
+		if indexed.Version > entry.Version || indexed.ETag == entry.ETag {
+			return false
+		}
+	}
+	idx.Events[guid] = entry
+	return true
+}
+
+// guids returns the GUIDs of the entries selected by keep, in order like a listing of the event objects
+func (idx *s3Index) guids(keep func(*s3IndexEntry) bool) []string {
+	guids := make([]string, 0, len(idx.Events))
+	for guid, entry := range idx.Events {
+		if keep == nil || keep(entry) {
+			guids = append(guids, guid)
+		}
+	}
+	sort.Strings(guids)
+	return guids
+}
+
+func (s *S3Storage) getIndexKey(category string) string {
+	return fmt.Sprintf("%sindex/%s.json", s.prefix, category)
+}
+
+// eventGUID returns the GUID of the event stored at key
+func eventGUID(key string) string {
+	return strings.TrimSuffix(path.Base(key), ".json")
+}
+
+// readIndex returns the index of a category and its ETag.
+// It returns ErrNotFound when the index is missing and errOutdatedIndex, with the ETag, when its format is older.
+func (s *S3Storage) readIndex(ctx context.Context, category string) (*s3Index, string, error) {
+	var data json.RawMessage
+	etag, err := s.getObjectETag(ctx, s.getIndexKey(category), &data)
+	if err != nil {
+		return nil, "", err
+	}
+	// Older formats may not decode as the current one
+	var header struct {
+		Format int `json:"format"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil || header.Format != s3IndexFormat {
+		return nil, etag, errOutdatedIndex
+	}
+	var idx s3Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s index: %w", category, err)
+	}
+	if idx.Events == nil {
+		idx.Events = map[string]*s3IndexEntry{}
+	}
+	return &idx, etag, nil
+}
+
+// readableIndex returns the index of a category when listings can rely on it.
+// A missing or outdated index is synced in the background meanwhile.
+func (s *S3Storage) readableIndex(ctx context.Context, category string) (*s3Index, bool) {
+	log := logger.GetDefault().WithComponent("S3Storage")
+
+	idx, _, err := s.readIndex(ctx, category)
+	switch {
+	case err == ErrNotFound || err == errOutdatedIndex:
+		log.WithField("category", category).Debug("No index, listing event objects")
+		s.syncIndexInBackground(category)
+		return nil, false
+	case err != nil:
+		log.WithError(err).WithField("category", category).Warn("Failed to read index, listing event objects")
+		return nil, false
+	case !idx.Complete:
+		log.WithField("category", category).Debug("Index is being created, listing event objects")
+		s.syncIndexInBackground(category)
+		return nil, false
+	}
+	return idx, true
+}
+
+// indexEvent records the event object written at key with the given ETag in the index of its category.
+// An index that cannot be updated is synced in the background, listings reading the event objects meanwhile.
+func (s *S3Storage) indexEvent(ctx context.Context, category, guid string, document interface{}, etag string) {
+	log := logger.GetDefault().WithComponent("S3Storage")
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		log.WithError(err).WithField("category", category).Error("Failed to encode indexed event, syncing index")
+		s.syncIndexInBackground(category)
+		return
+	}
+	entry, err := newS3IndexEntry(category, data, etag)
+	if err != nil {
+		log.WithError(err).WithField("category", category).Error("Failed to index event, syncing index")
+		s.syncIndexInBackground(category)
+		return
+	}
+
+	err = s.updateIndex(ctx, category, func(idx *s3Index) (bool, error) {
+		return idx.apply(guid, entry), nil
+	})
+	switch {
+	case err == ErrNotFound || err == errOutdatedIndex:
+		// The sync creating the index catches up with this write
+		s.syncIndexInBackground(category)
+	case err != nil:
+		log.WithError(err).WithField("category", category).Warn("Failed to update index, syncing it in the background")
+		s.syncIndexInBackground(category)
+	}
+}
+
+// updateIndex applies change to the index of a category with a conditional put, retrying after a randomized
+// delay when another writer won the race. It returns ErrNotFound or errOutdatedIndex when there is no index to update.
+func (s *S3Storage) updateIndex(ctx context.Context, category string, change func(idx *s3Index) (bool, error)) error {
+	// The event is already written, finish its indexing even if the caller goes away
+	ctx = context.WithoutCancel(ctx)
+
+	for attempt := 0; attempt < s3IndexUpdateAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt)*s3IndexRetryDelay + time.Duration(rand.Int63n(int64(s3IndexRetryDelay))))
+		}
+		idx, etag, err := s.readIndex(ctx, category)
+		if err != nil {
+			return err
+		}
+
+		changed, err := change(idx)
+		if err != nil || !changed {
+			return err
+		}
+		idx.UpdatedAt = time.Now().UTC()
+		if _, err := s.putObjectIfMatch(ctx, s.getIndexKey(category), idx, etag); err != ErrVersionConflict {
+			return err
+		}
+	}
+	return fmt.Errorf("index of %s still modified concurrently after %d attempts", category, s3IndexUpdateAttempts)
+}
+
+// syncIndexInBackground starts syncing the index of a category, unless this storage is already syncing it
+func (s *S3Storage) syncIndexInBackground(category string) {
+	if _, running := s.syncing.LoadOrStore(category, struct{}{}); running {
+		return
+	}
+	go func() {
+		defer s.syncing.Delete(category)
+		log := logger.GetDefault().WithComponent("S3Storage")
+
+		ctx, cancel := context.WithTimeout(context.Background(), s3IndexSyncTimeout)
+		defer cancel()
+		count, err := s.syncIndex(ctx, category, false)
+		if err != nil {
+			log.WithError(err).WithField("category", category).Warn("Failed to sync index, listings will read every event")
+			return
+		}
+		log.WithField("category", category).WithField("count", count).Info("Index synced")
+	}()
+}
+
+// syncIndex brings the index of a category in line with the event objects and returns the number of indexed events.
+// A missing or outdated index, or every index when rebuild is true, is first created from the event objects;
+// then the objects whose ETag differs from their entry are read again and the entries of purged objects removed,
+// catching up with the writes the index missed, including those made while it was created.
+func (s *S3Storage) syncIndex(ctx context.Context, category string, rebuild bool) (int, error) {
+	idx, etag, err := s.readIndex(ctx, category)
+	if err == ErrNotFound || err == errOutdatedIndex || (err == nil && rebuild) {
+		if err := s.createIndex(ctx, category, etag, rebuild); err != nil {
+			return 0, err
+		}
+		idx, _, err = s.readIndex(ctx, category)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// The index is read before the objects are listed, so an object missing from the listing has been purged
+	entries, purged, err := s.staleEntries(ctx, category, idx)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	err = s.updateIndex(ctx, category, func(idx *s3Index) (bool, error) {
+		changed := !idx.Complete
+		idx.Complete = true
+		for guid, entry := range entries {
+			if idx.apply(guid, entry) {
+				changed = true
+			}
+		}
+		for guid, etag := range purged {
+			// Unless the event was created again since
+			if entry, ok := idx.Events[guid]; ok && entry.ETag == etag {
+				delete(idx.Events, guid)
+				changed = true
+			}
+		}
+		count = len(idx.Events)
+		return changed, nil
+	})
+	return count, err
+}
+
+// createIndex stores a new incomplete index of a category read from its event objects.
+// It replaces the outdated index with the given ETag, or any index when replace is true.
+// An index created meanwhile by another server is left untouched.
+func (s *S3Storage) createIndex(ctx context.Context, category, etag string, replace bool) error {
+	log := logger.GetDefault().WithComponent("S3Storage")
+
+	idx := newS3Index()
+	entries, _, err := s.staleEntries(ctx, category, idx)
+	if err != nil {
+		return err
+	}
+	idx.Events = entries
+	idx.UpdatedAt = time.Now().UTC()
+
+	switch {
+	case replace:
+		err = s.putObject(ctx, s.getIndexKey(category), idx)
+	case etag != "":
+		_, err = s.putObjectIfMatch(ctx, s.getIndexKey(category), idx, etag)
+	default:
+		_, err = s.putObjectIfAbsent(ctx, s.getIndexKey(category), idx)
+	}
+	if err == ErrExists || err == ErrVersionConflict {
+		log.WithField("category", category).Debug("Index created meanwhile by another server")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store %s index: %w", category, err)
+	}
+	log.WithField("category", category).WithField("count", len(entries)).Info("Index created")
+	return nil
+}
+
+// staleEntries lists the event objects of a category and reads those whose ETag differs from their entry in idx.
+// It returns their new entries and the ETag of the entries of idx whose object no longer exists, keyed by GUID.
+func (s *S3Storage) staleEntries(ctx context.Context, category string, idx *s3Index) (map[string]*s3IndexEntry, map[string]string, error) {
+	objects, err := s.listObjectETags(ctx, category+"/")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	listed := make(map[string]bool, len(objects))
+	for key, etag := range objects {
+		guid := eventGUID(key)
+		listed[guid] = true
+		if entry, ok := idx.Events[guid]; !ok || entry.ETag != etag {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	entries := make(map[string]*s3IndexEntry, len(keys))
+	for _, object := range s.getObjects(ctx, keys) {
+		if object.err == ErrNotFound {
+			// Purged since it was listed
+			continue
+		}
+		if object.err != nil {
+			return nil, nil, object.err
+		}
+		entry, err := newS3IndexEntry(category, object.document, object.etag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to index %s: %w", object.key, err)
+		}
+		entries[eventGUID(object.key)] = entry
+	}
+
+	purged := map[string]string{}
+	for guid, entry := range idx.Events {
+		if !listed[guid] {
+			purged[guid] = entry.ETag
+		}
+	}
+	return entries, purged, nil
+}
+
+// s3Object is an event object fetched by getObjects, err is set when it could not be read
+type s3Object struct {
+	key      string
+	etag     string
+	document json.RawMessage
+	err      error
+}
+
+// getObjects fetches the objects at keys, s3FetchConcurrency at a time, and returns them in the order of keys
+func (s *S3Storage) getObjects(ctx context.Context, keys []string) []s3Object {
+	objects := make([]s3Object, len(keys))
+	slots := make(chan struct{}, s3FetchConcurrency)
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, key string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			object := s3Object{key: key}
+			object.etag, object.err = s.getObjectETag(ctx, key, &object.document)
+			objects[i] = object
+		}(i, key)
+	}
+	wg.Wait()
+	return objects
+}
+
+// RebuildIndex recreates the index of every category from the event objects, recovering a stale or lost index,
+// then catches up with the writes made while it ran
+func (s *S3Storage) RebuildIndex(ctx context.Context) (int, error) {
+	log := logger.GetDefault().WithComponent("S3Storage")
+
+	total := 0
+	for _, category := range s3Categories {
+		count, err := s.syncIndex(ctx, category, true)
+		if err != nil {
+			return total, fmt.Errorf("failed to rebuild %s index: %w", category, err)
+		}
+		log.WithField("category", category).WithField("count", count).Info("Index rebuilt")
+		total += count
+	}
+	return total, nil
+}
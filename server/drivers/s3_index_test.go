@@ -0,0 +1,235 @@
+package drivers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+)
+
+func TestS3Index_Apply(t *testing.T) {
+	idx := newS3Index()
+
+	if !idx.apply("b", &s3IndexEntry{ETag: "b1", Version: 1}) || !idx.apply("a", &s3IndexEntry{ETag: "a2", Version: 2}) {
+		t.Fatal("Expected new events to be indexed")
+	}
+	if !idx.apply("a", &s3IndexEntry{ETag: "a3", Version: 3}) {
+		t.Error("Expected a newer version to replace the indexed one")
+	}
+	// A writer finishing late must not roll the index back
+	if idx.apply("a", &s3IndexEntry{ETag: "a2", Version: 2}) {
+		t.Error("Expected an older version to be ignored")
+	}
+	if idx.apply("a", &s3IndexEntry{ETag: "a3", Version: 3}) {
+		t.Error("Expected an indexed object not to change the index")
+	}
+	if idx.Events["a"].ETag != "a3" {
+		t.Errorf("Expected version 3 to stay indexed, got %s", idx.Events["a"].ETag)
+	}
+
+	guids := idx.guids(func(entry *s3IndexEntry) bool { return entry.Version > 1 })
+	if len(guids) != 1 || guids[0] != "a" {
+		t.Errorf("Expected the selected GUIDs, got %v", guids)
+	}
+	if guids := idx.guids(nil); len(guids) != 2 || guids[0] != "a" {
+		t.Errorf("Expected every GUID ordered, got %v", guids)
+	}
+}
+
+func TestS3IndexEntry_Matches(t *testing.T) {
+	platform := component.NewPlatform("Production", "prod")
+	api := component.NewComponent("API", "api", component.NewInstance("EU", "eu", platform))
+	incident := event.NewFiringIncident("Outage", "API down", []*component.Component{api}, event.CriticalityMajorOutage)
+	incident.Labels = map[string]string{"team": "core"}
+
+	document, err := json.Marshal(incident)
+	if err != nil {
+		t.Fatalf("Failed to encode incident: %v", err)
+	}
+	entry, err := newS3IndexEntry("incidents", document, `"etag"`)
+	if err != nil {
+		t.Fatalf("newS3IndexEntry failed: %v", err)
+	}
+
+	selector, _ := event.ParseSelector("team=core")
+	otherSelector, _ := event.ParseSelector("team=edge")
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   bool
+	}{
+		{"no criteria", EventFilter{}, true},
+		{"criticality", EventFilter{Criticalities: []event.Criticality{event.CriticalityMajorOutage}}, true},
+		{"other criticality", EventFilter{Criticalities: []event.Criticality{event.CriticalityDegraded}}, false},
+		{"component path", EventFilter{PlatformCode: "prod", InstanceCode: "eu", ComponentCode: "api"}, true},
+		{"other instance", EventFilter{PlatformCode: "prod", InstanceCode: "us"}, false},
+		{"selector", EventFilter{Selector: selector}, true},
+		{"other selector", EventFilter{Selector: otherSelector}, false},
+		{"time range after its start", EventFilter{From: &future}, true},
+		// Left to the loaded incidents
+		{"text", EventFilter{Text: "unrelated"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entry.matches(tt.filter); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			if got := tt.filter.MatchesIncident(incident); got && !entry.matches(tt.filter) {
+				t.Error("Expected the entry to select every matching incident")
+			}
+		})
+	}
+}
+
+func TestS3Storage_IndexIsCompact(t *testing.T) {
+	ctx := context.Background()
+	storage, fake := newTestS3Storage(t)
+
+	content := strings.Repeat("Detailed status update. ", 200)
+	incident := event.NewFiringIncident("Outage", content, nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	index, ok := fake.object(storage.getIndexKey("incidents"))
+	if !ok {
+		t.Fatal("Expected the incident index to be stored")
+	}
+	if strings.Contains(string(index.data), "Detailed status update") {
+		t.Error("Expected the index not to hold the event contents")
+	}
+
+	idx, _, err := storage.readIndex(ctx, "incidents")
+	if err != nil {
+		t.Fatalf("readIndex failed: %v", err)
+	}
+	object, _ := fake.object(storage.getKey("incidents", incident.GUID))
+	entry := idx.Events[incident.GUID]
+	if !idx.Complete || entry == nil || entry.ETag != object.etag || entry.Version != incident.Version {
+		t.Errorf("Expected the incident indexed with its ETag and version, got %+v", entry)
+	}
+}
+
+func TestS3Storage_IndexRecreatedWhenMissing(t *testing.T) {
+	ctx := context.Background()
+	storage, fake := newTestS3Storage(t)
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	if err := storage.deleteObject(ctx, storage.getIndexKey("incidents")); err != nil {
+		t.Fatalf("Failed to delete the index: %v", err)
+	}
+
+	// Without index the listing reads the event objects and the index is created in the background
+	incidents, err := storage.GetAllIncidents(ctx)
+	if err != nil || len(incidents) != 1 {
+		t.Fatalf("Expected the incident listed without index, got %d, %v", len(incidents), err)
+	}
+	waitForIndexSync(t, storage)
+
+	if _, ok := fake.object(storage.getIndexKey("incidents")); !ok {
+		t.Fatal("Expected the missing index to be recreated")
+	}
+	idx, _, err := storage.readIndex(ctx, "incidents")
+	if err != nil || !idx.Complete || idx.Events[incident.GUID] == nil {
+		t.Errorf("Expected the recreated index to hold the incident, got %+v, %v", idx, err)
+	}
+}
+
+func TestS3Storage_IndexCatchesUpMissedWrites(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestS3Storage(t)
+
+	kept := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	purged := event.NewFiringIncident("Slowness", "API slow", nil, event.CriticalityDegraded)
+	for _, incident := range []*event.Incident{kept, purged} {
+		if err := storage.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+
+	// Writes the index missed, such as those of a server that failed to update it
+	missed := event.NewFiringIncident("Login errors", "SSO down", nil, event.CriticalityPartialOutage)
+	missed.Version = 1
+	if err := storage.putObject(ctx, storage.getKey("incidents", missed.GUID), missed); err != nil {
+		t.Fatalf("Failed to write the incident object: %v", err)
+	}
+	if err := storage.deleteObject(ctx, storage.getKey("incidents", purged.GUID)); err != nil {
+		t.Fatalf("Failed to delete the incident object: %v", err)
+	}
+
+	count, err := storage.syncIndex(ctx, "incidents", false)
+	if err != nil || count != 2 {
+		t.Fatalf("Expected 2 indexed incidents after the sync, got %d, %v", count, err)
+	}
+	idx, _, err := storage.readIndex(ctx, "incidents")
+	if err != nil {
+		t.Fatalf("readIndex failed: %v", err)
+	}
+	if idx.Events[missed.GUID] == nil || idx.Events[purged.GUID] != nil {
+		t.Errorf("Expected the index to hold the missed incident and drop the purged one, got %v", idx.Events)
+	}
+
+	incidents, err := storage.GetAllIncidents(ctx)
+	if err != nil || len(incidents) != 2 {
+		t.Errorf("Expected 2 listed incidents, got %d, %v", len(incidents), err)
+	}
+}
+
+func TestS3Storage_IndexReplacesOutdatedFormat(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestS3Storage(t)
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	// The first format held the whole documents
+	outdated := map[string]interface{}{
+		"format": 1,
+		"events": map[string]interface{}{incident.GUID: incident},
+	}
+	if err := storage.putObject(ctx, storage.getIndexKey("incidents"), outdated); err != nil {
+		t.Fatalf("Failed to write an outdated index: %v", err)
+	}
+	if _, _, err := storage.readIndex(ctx, "incidents"); err != errOutdatedIndex {
+		t.Fatalf("Expected errOutdatedIndex, got %v", err)
+	}
+
+	if count, err := storage.syncIndex(ctx, "incidents", false); err != nil || count != 1 {
+		t.Fatalf("Expected 1 indexed incident after the sync, got %d, %v", count, err)
+	}
+	if idx, _, err := storage.readIndex(ctx, "incidents"); err != nil || idx.Events[incident.GUID] == nil {
+		t.Errorf("Expected the index replaced by the current format, got %v", err)
+	}
+}
+
+func TestS3Storage_GetActiveSkipsEndedEvents(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestS3Storage(t)
+
+	firing := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	resolved := event.NewFiringIncident("Slowness", "API slow", nil, event.CriticalityDegraded)
+	ended := time.Now().Add(-time.Hour)
+	resolved.EndEffective = &ended
+	for _, incident := range []*event.Incident{firing, resolved} {
+		if err := storage.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+
+	active, err := storage.GetActiveIncidents(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveIncidents failed: %v", err)
+	}
+	if len(active) != 1 || active[0].GUID != firing.GUID {
+		t.Errorf("Expected only the firing incident, got %d incidents", len(active))
+	}
+}
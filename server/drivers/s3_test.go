@@ -0,0 +1,140 @@
+package drivers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+// concurrently runs fn from n goroutines started together and returns their errors
+func concurrently(n int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+func TestS3Storage_ConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestS3Storage(t)
+
+	guid := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage).GUID
+	errs := concurrently(8, func(i int) error {
+		incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+		incident.GUID = guid
+		return storage.CreateIncident(ctx, incident)
+	})
+
+	created := 0
+	for _, err := range errs {
+		switch err {
+		case nil:
+			created++
+		case ErrExists:
+		default:
+			t.Errorf("Expected ErrExists for the losing creations, got %v", err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("Expected exactly one creation to win, got %d", created)
+	}
+
+	revisions, err := storage.GetIncidentRevisions(ctx, guid)
+	if err != nil || len(revisions) != 1 {
+		t.Errorf("Expected a single creation revision, got %d, %v", len(revisions), err)
+	}
+}
+
+func TestS3Storage_ConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestS3Storage(t)
+
+	pm := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+
+	// Every editor starts from version 1, only one of them may win
+	errs := concurrently(8, func(i int) error {
+		edit := *pm
+		edit.Title = "Edit"
+		return storage.UpdatePlannedMaintenance(ctx, &edit)
+	})
+
+	updated := 0
+	for _, err := range errs {
+		switch err {
+		case nil:
+			updated++
+		case ErrVersionConflict:
+		default:
+			t.Errorf("Expected ErrVersionConflict for the losing updates, got %v", err)
+		}
+	}
+	if updated != 1 {
+		t.Errorf("Expected exactly one update to win, got %d", updated)
+	}
+
+	stored, err := storage.GetPlannedMaintenance(ctx, pm.GUID)
+	if err != nil || stored.Version != 2 {
+		t.Errorf("Expected the planned maintenance at version 2, got %v", err)
+	}
+}
+
+func TestS3Storage_PurgeSkipsRestored(t *testing.T) {
+	ctx := context.Background()
+	storage, fake := newTestS3Storage(t)
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	if err := storage.DeleteIncident(ctx, incident.GUID); err != nil {
+		t.Fatalf("DeleteIncident failed: %v", err)
+	}
+
+	// The index still lists the tombstone while another server restores the incident
+	key := storage.getKey("incidents", incident.GUID)
+	var stored json.RawMessage
+	etag, err := storage.getObjectETag(ctx, key, &stored)
+	if err != nil {
+		t.Fatalf("Failed to read the tombstone: %v", err)
+	}
+	tombstone, err := newS3IndexEntry("incidents", stored, etag)
+	if err != nil {
+		t.Fatalf("Failed to index the tombstone: %v", err)
+	}
+	if _, err := storage.restoreObject(ctx, "incidents", key, incident.GUID); err != nil {
+		t.Fatalf("restoreObject failed: %v", err)
+	}
+	waitForIndexSync(t, storage)
+	if err := storage.putObject(ctx, storage.getIndexKey("incidents"), &s3Index{
+		Format:   s3IndexFormat,
+		Complete: true,
+		Events:   map[string]*s3IndexEntry{incident.GUID: tombstone},
+	}); err != nil {
+		t.Fatalf("Failed to write a stale index: %v", err)
+	}
+
+	purged, err := storage.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+	if err != nil || purged != 0 {
+		t.Errorf("Expected the restored incident not to be purged, got %d, %v", purged, err)
+	}
+	if keys := fake.keys("incidents/"); len(keys) != 1 {
+		t.Errorf("Expected the incident object to be kept, got %v", keys)
+	}
+}
@@ -0,0 +1,70 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+)
+
+// ScheduleReport counts the actions applied by a scheduler run
+type ScheduleReport struct {
+	Started   int `json:"started"`
+	Completed int `json:"completed"`
+	Overruns  int `json:"overruns"` // Maintenances newly flagged as overrun
+}
+
+// ApplySchedule applies the scheduled actions due at now to the planned maintenances of the storage,
+// attributed to AuthorFromContext(ctx). A maintenance modified concurrently is left to the next run.
+func ApplySchedule(ctx context.Context, storage EventStorage, now time.Time) (*ScheduleReport, error) {
+	log := logger.GetDefault().WithComponent("Scheduler")
+	report := &ScheduleReport{}
+
+	// Completed maintenances have nothing left to schedule, indexed drivers skip them
+	var maintenances []*event.PlannedMaintenance
+	var err error
+	if active, ok := storage.(ActiveEventStorage); ok {
+		maintenances, err = active.GetActivePlannedMaintenances(ctx)
+	} else {
+		maintenances, err = storage.GetAllPlannedMaintenances(ctx)
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to read planned maintenances: %w", err)
+	}
+
+	author := AuthorFromContext(ctx)
+	for _, stored := range maintenances {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		maintenance := *stored
+		actions := maintenance.ApplySchedule(author, now)
+		if len(actions) == 0 {
+			continue
+		}
+
+		err := storage.UpdatePlannedMaintenance(ctx, &maintenance)
+		if err == ErrVersionConflict || err == ErrNotFound {
+			log.WithField("maintenance_id", maintenance.GUID).Debug("Maintenance changed during scheduling, retrying on the next run")
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to update planned maintenance %s: %w", maintenance.GUID, err)
+		}
+
+		for _, action := range actions {
+			switch action {
+			case event.ScheduleStart:
+				report.Started++
+			case event.ScheduleComplete:
+				report.Completed++
+			case event.ScheduleOverrun:
+				report.Overruns++
+			}
+		}
+		log.WithField("maintenance_id", maintenance.GUID).WithField("actions", actions).Info("Scheduled maintenance actions applied")
+	}
+	return report, nil
+}
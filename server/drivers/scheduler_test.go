@@ -0,0 +1,60 @@
+package drivers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+func TestApplySchedule(t *testing.T) {
+	ctx := WithAuthor(context.Background(), "scheduler")
+	storage := NewRAMStorage()
+	now := time.Now()
+
+	due := event.NewPlannedMaintenance("Upgrade", "Database upgrade", nil, now.Add(-time.Hour), now.Add(time.Hour))
+	due.AutoStart, due.AutoComplete = true, true
+	manual := event.NewPlannedMaintenance("Upgrade", "Cache upgrade", nil, now.Add(-time.Hour), now.Add(time.Hour))
+	overrunning := event.NewPlannedMaintenance("Upgrade", "Queue upgrade", nil, now.Add(-3*time.Hour), now.Add(-time.Hour))
+	overrunning.StartEffective = &overrunning.StartPlanned
+	for _, pm := range []*event.PlannedMaintenance{due, manual, overrunning} {
+		if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+			t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+		}
+	}
+
+	report, err := ApplySchedule(ctx, storage, now)
+	if err != nil {
+		t.Fatalf("ApplySchedule failed: %v", err)
+	}
+	if report.Started != 1 || report.Completed != 0 || report.Overruns != 1 {
+		t.Errorf("Expected 1 maintenance started and 1 overrun, got %+v", report)
+	}
+
+	stored, err := storage.GetPlannedMaintenance(ctx, due.GUID)
+	if err != nil {
+		t.Fatalf("GetPlannedMaintenance failed: %v", err)
+	}
+	if stored.StartEffective == nil || !stored.StartEffective.Equal(due.StartPlanned) || stored.StartedBy != "scheduler" {
+		t.Errorf("Expected the opted-in maintenance started at its planned start, got %+v", stored)
+	}
+	if stored, _ := storage.GetPlannedMaintenance(ctx, manual.GUID); stored.StartEffective != nil {
+		t.Errorf("Expected the maintenance without opt-in to be left alone")
+	}
+	if stored, _ := storage.GetPlannedMaintenance(ctx, overrunning.GUID); stored.OverrunAt == nil {
+		t.Errorf("Expected the maintenance past its end to be flagged as overrun")
+	}
+
+	// The next run past the planned end completes the opted-in maintenance, overruns are only flagged once
+	report, err = ApplySchedule(ctx, storage, now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("ApplySchedule failed: %v", err)
+	}
+	if report.Started != 0 || report.Completed != 1 || report.Overruns != 0 {
+		t.Errorf("Expected 1 maintenance completed, got %+v", report)
+	}
+	if stored, _ := storage.GetPlannedMaintenance(ctx, due.GUID); stored.EndEffective == nil || !stored.EndEffective.Equal(due.EndPlanned) {
+		t.Errorf("Expected the opted-in maintenance completed at its planned end")
+	}
+}
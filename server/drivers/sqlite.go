@@ -0,0 +1,901 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+	"modernc.org/sqlite" // Pure-Go SQLite driver
+)
+
+const (
+	kindIncident           = "incident"
+	kindPlannedMaintenance = "planned_maintenance"
+)
+
+// SQLiteStorage implements EventStorage interface using an embedded SQLite database
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// SQLiteConfig holds SQLite-specific configuration
+type SQLiteConfig struct {
+	Path string
+}
+
+// SQLite lower() only folds ASCII, the text filter lowercases with unicode_lower to match like strings.ToLower
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch value := args[0].(type) {
+		case string:
+			return strings.ToLower(value), nil
+		case []byte:
+			return strings.ToLower(string(value)), nil
+		}
+		return args[0], nil
+	})
+}
+
+// sqliteMigrations are applied in order on startup, each one exactly once.
+// Never edit a released migration, append a new one instead.
+var sqliteMigrations = []string{
+	// 1: events, their components and query indexes
+	`CREATE TABLE events (
+		kind            TEXT    NOT NULL,
+		guid            TEXT    NOT NULL,
+		title           TEXT    NOT NULL,
+		type            TEXT    NOT NULL,
+		criticality     INTEGER NOT NULL,
+		perpetual       INTEGER NOT NULL DEFAULT 0,
+		cancelled       INTEGER NOT NULL DEFAULT 0,
+		start_effective INTEGER,
+		end_effective   INTEGER,
+		start_planned   INTEGER,
+		end_planned     INTEGER,
+		document        TEXT    NOT NULL,
+		PRIMARY KEY (kind, guid)
+	);
+	CREATE TABLE event_components (
+		kind           TEXT    NOT NULL,
+		event_guid     TEXT    NOT NULL,
+		position       INTEGER NOT NULL,
+		platform_code  TEXT    NOT NULL DEFAULT '',
+		instance_code  TEXT    NOT NULL DEFAULT '',
+		component_code TEXT    NOT NULL,
+		PRIMARY KEY (kind, event_guid, position),
+		FOREIGN KEY (kind, event_guid) REFERENCES events (kind, guid) ON DELETE CASCADE
+	);
+	CREATE INDEX idx_events_end_effective ON events (kind, end_effective);
+	CREATE INDEX idx_events_start_effective ON events (kind, start_effective);
+	CREATE INDEX idx_events_planned ON events (kind, cancelled, start_planned, end_planned);
+	CREATE INDEX idx_events_criticality ON events (kind, criticality);
+	CREATE INDEX idx_event_components_path ON event_components (platform_code, instance_code, component_code);`,
+	// 2: revision counter for optimistic concurrency
+	`ALTER TABLE events ADD COLUMN version INTEGER NOT NULL DEFAULT 0;`,
+	// 3: immutable revision history, kept after the event is deleted
+	`CREATE TABLE event_revisions (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind       TEXT    NOT NULL,
+		event_guid TEXT    NOT NULL,
+		version    INTEGER NOT NULL,
+		action     TEXT    NOT NULL,
+		author     TEXT    NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		document   TEXT    NOT NULL
+	);
+	CREATE INDEX idx_event_revisions_event ON event_revisions (kind, event_guid, id);`,
+	// 4: trash, deleted events are kept until purged
+	`ALTER TABLE events ADD COLUMN deleted_at INTEGER;
+	CREATE INDEX idx_events_deleted_at ON events (kind, deleted_at);`,
+	// 5: incident lifecycle status, empty outside the lifecycle
+	`ALTER TABLE events ADD COLUMN lifecycle_status TEXT NOT NULL DEFAULT '';`,
+	// 6: incident acknowledgement
+	`ALTER TABLE events ADD COLUMN acknowledged INTEGER NOT NULL DEFAULT 0;`,
+	// 7: recurring planned maintenances, their end_planned holds the end of the series
+	`ALTER TABLE events ADD COLUMN recurring INTEGER NOT NULL DEFAULT 0;`,
+}
+
+// NewSQLiteStorage creates a new SQLite storage driver and migrates its schema
+func NewSQLiteStorage(cfg SQLiteConfig) (*SQLiteStorage, error) {
+	log := logger.GetDefault().WithComponent("SQLiteStorage")
+	log.WithField("path", cfg.Path).Info("Initializing SQLite storage driver")
+
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("sqlite database path is required")
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+
+	db, err := sql.Open("sqlite", "file:"+cfg.Path+"?"+params.Encode())
+	if err != nil {
+		log.WithError(err).Error("Failed to open SQLite database")
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// SQLite allows a single writer, serializing here avoids SQLITE_BUSY churn
+	db.SetMaxOpenConns(1)
+
+	storage := &SQLiteStorage{db: db}
+	if err := storage.migrate(); err != nil {
+		db.Close()
+		log.WithError(err).Error("Failed to migrate SQLite schema")
+		return nil, err
+	}
+
+	log.WithField("path", cfg.Path).Info("SQLite storage driver initialized successfully")
+	return storage, nil
+}
+
+// Close releases the underlying database
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// migrate brings the schema up to date with sqliteMigrations
+func (s *SQLiteStorage) migrate() error {
+	log := logger.GetDefault().WithComponent("SQLiteStorage")
+
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := current; i < len(sqliteMigrations); i++ {
+		version := i + 1
+		log.WithField("version", version).Info("Applying SQLite schema migration")
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().Unix()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// Helper methods for SQL operations
+
+// eventRow holds the indexed columns of an event
+type eventRow struct {
+	kind           string
+	guid           string
+	title          string
+	typ            event.TypeEvent
+	criticality    event.Criticality
+	perpetual      bool
+	cancelled      bool
+	lifecycle      event.Status
+	acknowledged   bool
+	recurring      bool
+	startEffective *time.Time
+	endEffective   *time.Time
+	startPlanned   *time.Time
+	endPlanned     *time.Time
+	components     []*component.Component
+	deletedAt      *time.Time // set when the event is created in the trash, such as an imported deleted event
+	version        *int64     // version field of document
+	document       interface{}
+}
+
+func incidentRow(incident *event.Incident) eventRow {
+	return eventRow{
+		kind:           kindIncident,
+		guid:           incident.GUID,
+		title:          incident.Title,
+		typ:            incident.Type(),
+		criticality:    incident.Criticality(),
+		perpetual:      incident.Perpetual,
+		lifecycle:      incident.LifecycleStatus,
+		acknowledged:   incident.AcknowledgedAt != nil,
+		startEffective: incident.StartEffective,
+		endEffective:   incident.EndEffective,
+		components:     incident.Components,
+		deletedAt:      incident.DeletedAt,
+		version:        &incident.Version,
+		document:       incident,
+	}
+}
+
+func plannedMaintenanceRow(pm *event.PlannedMaintenance) eventRow {
+	startPlanned, endPlanned := pm.StartPlanned, &pm.EndPlanned
+	if pm.IsRecurring() {
+		// NULL for series that never end, listings then treat them as open
+		endPlanned = pm.SeriesEnd()
+	}
+	return eventRow{
+		kind:           kindPlannedMaintenance,
+		guid:           pm.GUID,
+		title:          pm.Title,
+		typ:            pm.Type(),
+		criticality:    pm.Criticality(),
+		cancelled:      pm.Cancelled,
+		recurring:      pm.IsRecurring(),
+		startEffective: pm.StartEffective,
+		endEffective:   pm.EndEffective,
+		startPlanned:   &startPlanned,
+		endPlanned:     endPlanned,
+		components:     pm.Components,
+		deletedAt:      pm.DeletedAt,
+		version:        &pm.Version,
+		document:       pm,
+	}
+}
+
+// sqlTime converts an optional time to its stored representation (UTC unix nanoseconds)
+func sqlTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UnixNano()
+}
+
+// insertEvent inserts the event unless one with the same kind and GUID exists
+func (s *SQLiteStorage) insertEvent(ctx context.Context, row eventRow) error {
+	previous := *row.version
+	*row.version = initialVersion(previous)
+	document, err := json.Marshal(row.document)
+	if err != nil {
+		*row.version = previous
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	revision, err := newRevision(ctx, row.guid, event.RevisionCreated, *row.version, nil, row.document)
+	if err != nil {
+		*row.version = previous
+		return err
+	}
+
+	err = s.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `INSERT INTO events
+			(kind, guid, title, type, criticality, perpetual, cancelled, lifecycle_status, acknowledged, recurring, start_effective, end_effective, start_planned, end_planned, version, document, deleted_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (kind, guid) DO NOTHING`,
+			row.kind, row.guid, row.title, string(row.typ), int(row.criticality), row.perpetual, row.cancelled, string(row.lifecycle), row.acknowledged, row.recurring,
+			sqlTime(row.startEffective), sqlTime(row.endEffective), sqlTime(row.startPlanned), sqlTime(row.endPlanned), *row.version, string(document),
+			sqlTime(row.deletedAt))
+		if err != nil {
+			return fmt.Errorf("failed to insert event %s: %w", row.guid, err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return ErrExists
+		}
+		if err := insertEventComponents(ctx, tx, row); err != nil {
+			return err
+		}
+		return insertRevision(ctx, tx, row.kind, revision)
+	})
+	if err != nil {
+		*row.version = previous
+	}
+	return err
+}
+
+// updateEvent replaces an existing event and its components once its stored version was checked
+func (s *SQLiteStorage) updateEvent(ctx context.Context, row eventRow) error {
+	expected := *row.version
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		stored, storedDocument, err := getVersioned(ctx, tx, row.kind, row.guid, false)
+		if err != nil {
+			return err
+		}
+		next, err := nextVersion(stored, expected)
+		if err != nil {
+			return err
+		}
+
+		*row.version = next
+		document, err := json.Marshal(row.document)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		revision, err := newRevision(ctx, row.guid, event.RevisionUpdated, next, storedDocument, row.document)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE events SET
+			title = ?, type = ?, criticality = ?, perpetual = ?, cancelled = ?, lifecycle_status = ?, acknowledged = ?, recurring = ?,
+			start_effective = ?, end_effective = ?, start_planned = ?, end_planned = ?, version = ?, document = ?
+			WHERE kind = ? AND guid = ?`,
+			row.title, string(row.typ), int(row.criticality), row.perpetual, row.cancelled, string(row.lifecycle), row.acknowledged, row.recurring,
+			sqlTime(row.startEffective), sqlTime(row.endEffective), sqlTime(row.startPlanned), sqlTime(row.endPlanned), next, string(document),
+			row.kind, row.guid); err != nil {
+			return fmt.Errorf("failed to update event %s: %w", row.guid, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM event_components WHERE kind = ? AND event_guid = ?`, row.kind, row.guid); err != nil {
+			return fmt.Errorf("failed to clear components of event %s: %w", row.guid, err)
+		}
+		if err := insertEventComponents(ctx, tx, row); err != nil {
+			return err
+		}
+		return insertRevision(ctx, tx, row.kind, revision)
+	})
+	if err != nil {
+		*row.version = expected
+	}
+	return err
+}
+
+// inTx runs fn in a transaction committed when fn succeeds
+func (s *SQLiteStorage) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertEventComponents(ctx context.Context, tx *sql.Tx, row eventRow) error {
+	for position, comp := range row.components {
+		if comp == nil {
+			continue
+		}
+		var platformCode, instanceCode string
+		if comp.Instance != nil {
+			instanceCode = comp.Instance.Code
+			if comp.Instance.Platform != nil {
+				platformCode = comp.Instance.Platform.Code
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO event_components
+			(kind, event_guid, position, platform_code, instance_code, component_code)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			row.kind, row.guid, position, platformCode, instanceCode, comp.Code); err != nil {
+			return fmt.Errorf("failed to insert components of event %s: %w", row.guid, err)
+		}
+	}
+	return nil
+}
+
+// trashEvent moves an event to the trash and records the deletion
+func (s *SQLiteStorage) trashEvent(ctx context.Context, kind, guid string) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		version, document, err := getVersioned(ctx, tx, kind, guid, false)
+		if err != nil {
+			return err
+		}
+		deleted, err := markDeleted(ctx, document, version+1)
+		if err != nil {
+			return err
+		}
+		state, err := readTrashState(deleted)
+		if err != nil {
+			return err
+		}
+		revision, err := newRevision(ctx, guid, event.RevisionDeleted, version+1, document, deleted)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE events SET version = ?, document = ?, deleted_at = ? WHERE kind = ? AND guid = ?`,
+			version+1, string(deleted), sqlTime(state.DeletedAt), kind, guid); err != nil {
+			return fmt.Errorf("failed to delete event %s: %w", guid, err)
+		}
+		return insertRevision(ctx, tx, kind, revision)
+	})
+}
+
+// restoreEvent takes an event out of the trash, records it and returns the restored document
+func (s *SQLiteStorage) restoreEvent(ctx context.Context, kind, guid string) (json.RawMessage, error) {
+	var restored json.RawMessage
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		version, document, err := getVersioned(ctx, tx, kind, guid, true)
+		if err != nil {
+			return err
+		}
+		restored, err = markRestored(document, version+1)
+		if err != nil {
+			return err
+		}
+		revision, err := newRevision(ctx, guid, event.RevisionRestored, version+1, document, restored)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE events SET version = ?, document = ?, deleted_at = NULL WHERE kind = ? AND guid = ?`,
+			version+1, string(restored), kind, guid); err != nil {
+			return fmt.Errorf("failed to restore event %s: %w", guid, err)
+		}
+		return insertRevision(ctx, tx, kind, revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// getVersioned returns the version and raw document of a stored event, in the trash or not depending on deleted
+func getVersioned(ctx context.Context, tx *sql.Tx, kind, guid string, deleted bool) (int64, json.RawMessage, error) {
+	var version int64
+	var document string
+	err := tx.QueryRowContext(ctx, `SELECT version, document FROM events WHERE kind = ? AND guid = ? AND (deleted_at IS NOT NULL) = ?`,
+		kind, guid, deleted).Scan(&version, &document)
+	if err == sql.ErrNoRows {
+		return 0, nil, ErrNotFound
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read event %s: %w", guid, err)
+	}
+	return version, json.RawMessage(document), nil
+}
+
+func insertRevision(ctx context.Context, tx *sql.Tx, kind string, revision *event.Revision) error {
+	document, err := json.Marshal(revision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO event_revisions
+		(kind, event_guid, version, action, author, created_at, document)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		kind, revision.EventGUID, revision.Version, string(revision.Action), revision.Author, revision.Timestamp.UnixNano(), string(document)); err != nil {
+		return fmt.Errorf("failed to record revision of event %s: %w", revision.EventGUID, err)
+	}
+	return nil
+}
+
+// getRevisions returns the revisions of an event in write order
+func (s *SQLiteStorage) getRevisions(ctx context.Context, kind, guid string) ([]*event.Revision, error) {
+	revisions := []*event.Revision{}
+	err := s.queryDocuments(ctx, func(data []byte) error {
+		var revision event.Revision
+		if err := json.Unmarshal(data, &revision); err != nil {
+			return err
+		}
+		revisions = append(revisions, &revision)
+		return nil
+	}, `SELECT document FROM event_revisions WHERE kind = ? AND event_guid = ? ORDER BY id`, kind, guid)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (s *SQLiteStorage) getDocument(ctx context.Context, kind, guid string, dest interface{}) error {
+	var document string
+	err := s.db.QueryRowContext(ctx, `SELECT document FROM events WHERE kind = ? AND guid = ? AND deleted_at IS NULL`, kind, guid).Scan(&document)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get event %s: %w", guid, err)
+	}
+	if err := json.Unmarshal([]byte(document), dest); err != nil {
+		return fmt.Errorf("failed to decode event %s: %w", guid, err)
+	}
+	return nil
+}
+
+// queryDocuments runs a query selecting a single document column and decodes each row with decode
+func (s *SQLiteStorage) queryDocuments(ctx context.Context, decode func([]byte) error, query string, args ...interface{}) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var document string
+		if err := rows.Scan(&document); err != nil {
+			return fmt.Errorf("failed to scan event: %w", err)
+		}
+		if err := decode([]byte(document)); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+func (s *SQLiteStorage) queryIncidents(ctx context.Context, query string, args ...interface{}) ([]*event.Incident, error) {
+	incidents := []*event.Incident{}
+	err := s.queryDocuments(ctx, func(data []byte) error {
+		var incident event.Incident
+		if err := json.Unmarshal(data, &incident); err != nil {
+			return err
+		}
+		incidents = append(incidents, &incident)
+		return nil
+	}, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+func (s *SQLiteStorage) queryPlannedMaintenances(ctx context.Context, query string, args ...interface{}) ([]*event.PlannedMaintenance, error) {
+	pms := []*event.PlannedMaintenance{}
+	err := s.queryDocuments(ctx, func(data []byte) error {
+		var pm event.PlannedMaintenance
+		if err := json.Unmarshal(data, &pm); err != nil {
+			return err
+		}
+		pms = append(pms, &pm)
+		return nil
+	}, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pms, nil
+}
+
+// sqliteEventColumns are the SQL expressions a filtered listing uses for each kind of event
+type sqliteEventColumns struct {
+	sortKey string
+	status  string
+	start   string
+	end     string
+	// computed selects the rows whose status SQLite cannot evaluate, they are filtered on their document instead
+	computed string
+}
+
+var sqliteListColumns = map[string]sqliteEventColumns{
+	kindIncident: {
+		sortKey: `COALESCE(start_effective, 0)`,
+		status: `CASE
+			WHEN lifecycle_status = 'investigating' AND acknowledged THEN 'acknowledged'
+			WHEN lifecycle_status <> '' THEN lifecycle_status
+			WHEN end_effective IS NOT NULL AND end_effective < :now THEN 'resolved'
+			WHEN start_effective IS NOT NULL AND start_effective < :now AND acknowledged THEN 'acknowledged'
+			WHEN start_effective IS NOT NULL AND start_effective < :now THEN 'ongoing'
+			ELSE 'unknown' END`,
+		start: `start_effective`,
+		end:   `end_effective`,
+	},
+	kindPlannedMaintenance: {
+		sortKey: `start_planned`,
+		status: `CASE
+			WHEN cancelled THEN 'canceled'
+			WHEN end_effective IS NOT NULL AND end_effective < :now THEN 'resolved'
+			WHEN start_effective IS NOT NULL AND start_effective < :now THEN 'ongoing'
+			WHEN start_planned > :now THEN 'planned'
+			ELSE 'unknown' END`,
+		start:    `COALESCE(start_effective, start_planned)`,
+		end:      `COALESCE(end_effective, end_planned)`,
+		computed: `recurring`,
+	},
+}
+
+// buildListQuery translates a filter into a query selecting the matching documents.
+// One extra row past the limit is requested to know whether another page follows.
+// When exact is false, the query also selects rows with a computed status and the
+// caller must apply the filter and pagination to the documents.
+func buildListQuery(kind string, filter EventFilter, now time.Time) (query string, args []interface{}, exact bool, err error) {
+	if err := filter.Validate(); err != nil {
+		return "", nil, false, err
+	}
+	cols := sqliteListColumns[kind]
+
+	conditions := []string{`kind = :kind`, `deleted_at IS NULL`}
+	args = []interface{}{sql.Named("kind", kind), sql.Named("now", now.UnixNano())}
+	exact = true
+
+	in := func(expr, prefix string, values []interface{}) {
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			name := fmt.Sprintf("%s%d", prefix, i)
+			placeholders[i] = ":" + name
+			args = append(args, sql.Named(name, v))
+		}
+		conditions = append(conditions, fmt.Sprintf("%s IN (%s)", expr, strings.Join(placeholders, ", ")))
+	}
+
+	if len(filter.Statuses) > 0 {
+		values := make([]interface{}, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			values[i] = string(status)
+		}
+		in("("+cols.status+")", "status", values)
+		if cols.computed != "" {
+			last := len(conditions) - 1
+			conditions[last] = fmt.Sprintf("(%s OR %s)", cols.computed, conditions[last])
+			exact = false
+		}
+	}
+	if len(filter.Types) > 0 {
+		values := make([]interface{}, len(filter.Types))
+		for i, typ := range filter.Types {
+			values[i] = string(typ)
+		}
+		in("type", "type", values)
+	}
+	if len(filter.Criticalities) > 0 {
+		values := make([]interface{}, len(filter.Criticalities))
+		for i, criticality := range filter.Criticalities {
+			values[i] = int(criticality)
+		}
+		in("criticality", "criticality", values)
+	}
+
+	if filter.PlatformCode != "" {
+		path := `c.platform_code = :platform`
+		args = append(args, sql.Named("platform", filter.PlatformCode))
+		if filter.InstanceCode != "" || filter.ComponentCode != "" {
+			path += ` AND c.instance_code = :instance`
+			args = append(args, sql.Named("instance", filter.InstanceCode))
+		}
+		if filter.ComponentCode != "" {
+			path += ` AND c.component_code = :component`
+			args = append(args, sql.Named("component", filter.ComponentCode))
+		}
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM event_components c
+			WHERE c.kind = events.kind AND c.event_guid = events.guid AND `+path+`)`)
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, fmt.Sprintf(`(%[1]s IS NULL OR %[1]s >= :from)`, cols.end))
+		args = append(args, sql.Named("from", filter.From.UnixNano()))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, fmt.Sprintf(`(%[1]s IS NULL OR %[1]s <= :to)`, cols.start))
+		args = append(args, sql.Named("to", filter.To.UnixNano()))
+	}
+
+	if filter.Text != "" {
+		conditions = append(conditions, `(instr(unicode_lower(title), :text) > 0
+			OR instr(unicode_lower(COALESCE(json_extract(document, '$.content'), '')), :text) > 0)`)
+		args = append(args, sql.Named("text", strings.ToLower(filter.Text)))
+	}
+
+	for i, requirement := range filter.Selector {
+		// Keys are validated by the selector parser and cannot break out of the quoted path member
+		label := fmt.Sprintf("json_extract(document, :label%d)", i)
+		args = append(args, sql.Named(fmt.Sprintf("label%d", i), fmt.Sprintf(`$.labels."%s"`, requirement.Key)))
+		values := make([]interface{}, len(requirement.Values))
+		for j, value := range requirement.Values {
+			values[j] = value
+		}
+		switch requirement.Operator {
+		case event.SelectorEquals, event.SelectorIn:
+			in(label, fmt.Sprintf("label%d_", i), values)
+		case event.SelectorNotEquals, event.SelectorNotIn:
+			in(label, fmt.Sprintf("label%d_", i), values)
+			last := len(conditions) - 1
+			conditions[last] = fmt.Sprintf("(%s IS NULL OR NOT (%s))", label, conditions[last])
+		case event.SelectorExists:
+			conditions = append(conditions, label+" IS NOT NULL")
+		case event.SelectorDoesNotExist:
+			conditions = append(conditions, label+" IS NULL")
+		}
+	}
+
+	order := filter.order()
+	comparison, direction := "<", "DESC"
+	if order == SortAscending {
+		comparison, direction = ">", "ASC"
+	}
+
+	cursor, _ := decodeCursor(filter.Cursor)
+	if cursor != nil {
+		conditions = append(conditions, fmt.Sprintf(`(%[1]s %[2]s :cursor_key OR (%[1]s = :cursor_key AND guid %[2]s :cursor_guid))`, cols.sortKey, comparison))
+		args = append(args, sql.Named("cursor_key", cursor.key), sql.Named("cursor_guid", cursor.guid))
+	}
+
+	query = fmt.Sprintf(`SELECT document FROM events WHERE %s ORDER BY %s %s, guid %s`,
+		strings.Join(conditions, " AND "), cols.sortKey, direction, direction)
+	if filter.Limit > 0 && exact {
+		query += ` LIMIT :limit`
+		args = append(args, sql.Named("limit", filter.Limit+1))
+	}
+	return query, args, exact, nil
+}
+
+// Incidents implementation
+
+func (s *SQLiteStorage) CreateIncident(ctx context.Context, incident *event.Incident) error {
+	log := logger.GetDefault().WithComponent("SQLiteStorage")
+	log.WithField("incident_id", incident.GUID).Info("Creating incident in SQLite")
+
+	if err := s.insertEvent(ctx, incidentRow(incident)); err != nil {
+		if err == ErrExists {
+			log.WithField("incident_id", incident.GUID).Warn("Incident already exists")
+		} else {
+			log.WithError(err).WithField("incident_id", incident.GUID).Error("Failed to create incident")
+		}
+		return err
+	}
+
+	log.WithField("incident_id", incident.GUID).Info("Incident created successfully in SQLite")
+	return nil
+}
+
+func (s *SQLiteStorage) GetIncident(ctx context.Context, id string) (*event.Incident, error) {
+	var incident event.Incident
+	if err := s.getDocument(ctx, kindIncident, id, &incident); err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+func (s *SQLiteStorage) GetAllIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return s.queryIncidents(ctx, `SELECT document FROM events WHERE kind = ? AND deleted_at IS NULL ORDER BY guid`, kindIncident)
+}
+
+// ListIncidents returns the incidents matching the filter, evaluated by SQLite
+func (s *SQLiteStorage) ListIncidents(ctx context.Context, filter EventFilter) (*IncidentPage, error) {
+	query, args, _, err := buildListQuery(kindIncident, filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	incidents, err := s.queryIncidents(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &IncidentPage{Items: incidents}
+	if filter.Limit > 0 && len(incidents) > filter.Limit {
+		page.Items = incidents[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(incidentSortKey(last), last.GUID)
+	}
+	return page, nil
+}
+
+func (s *SQLiteStorage) UpdateIncident(ctx context.Context, incident *event.Incident) error {
+	return s.updateEvent(ctx, incidentRow(incident))
+}
+
+func (s *SQLiteStorage) DeleteIncident(ctx context.Context, id string) error {
+	return s.trashEvent(ctx, kindIncident, id)
+}
+
+// GetDeletedIncidents returns the incidents in the trash, most recently deleted first
+func (s *SQLiteStorage) GetDeletedIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return s.queryIncidents(ctx, `SELECT document FROM events
+		WHERE kind = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, guid`, kindIncident)
+}
+
+// RestoreIncident takes an incident out of the trash
+func (s *SQLiteStorage) RestoreIncident(ctx context.Context, id string) (*event.Incident, error) {
+	document, err := s.restoreEvent(ctx, kindIncident, id)
+	if err != nil {
+		return nil, err
+	}
+	var incident event.Incident
+	if err := json.Unmarshal(document, &incident); err != nil {
+		return nil, fmt.Errorf("failed to decode incident %s: %w", id, err)
+	}
+	return &incident, nil
+}
+
+// GetIncidentRevisions returns the revision history of an incident, oldest first
+func (s *SQLiteStorage) GetIncidentRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return s.getRevisions(ctx, kindIncident, id)
+}
+
+// GetActiveIncidents returns the incidents that have not ended yet, using the end_effective index
+// instead of loading the whole history
+func (s *SQLiteStorage) GetActiveIncidents(ctx context.Context) ([]*event.Incident, error) {
+	return s.queryIncidents(ctx, `SELECT document FROM events
+		WHERE kind = ? AND deleted_at IS NULL AND (end_effective IS NULL OR end_effective >= ?)
+		ORDER BY guid`, kindIncident, time.Now().UnixNano())
+}
+
+// Planned Maintenances implementation
+
+func (s *SQLiteStorage) CreatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	return s.insertEvent(ctx, plannedMaintenanceRow(pm))
+}
+
+func (s *SQLiteStorage) GetPlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	var pm event.PlannedMaintenance
+	if err := s.getDocument(ctx, kindPlannedMaintenance, id, &pm); err != nil {
+		return nil, err
+	}
+	return &pm, nil
+}
+
+func (s *SQLiteStorage) GetAllPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return s.queryPlannedMaintenances(ctx, `SELECT document FROM events WHERE kind = ? AND deleted_at IS NULL ORDER BY guid`, kindPlannedMaintenance)
+}
+
+// ListPlannedMaintenances returns the planned maintenances matching the filter, evaluated by SQLite
+func (s *SQLiteStorage) ListPlannedMaintenances(ctx context.Context, filter EventFilter) (*PlannedMaintenancePage, error) {
+	query, args, exact, err := buildListQuery(kindPlannedMaintenance, filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	pms, err := s.queryPlannedMaintenances(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if !exact {
+		// The status of a series depends on its occurrences, which only the document knows
+		return filterPlannedMaintenances(pms, filter)
+	}
+
+	page := &PlannedMaintenancePage{Items: pms}
+	if filter.Limit > 0 && len(pms) > filter.Limit {
+		page.Items = pms[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(plannedMaintenanceSortKey(last), last.GUID)
+	}
+	return page, nil
+}
+
+func (s *SQLiteStorage) UpdatePlannedMaintenance(ctx context.Context, pm *event.PlannedMaintenance) error {
+	return s.updateEvent(ctx, plannedMaintenanceRow(pm))
+}
+
+func (s *SQLiteStorage) DeletePlannedMaintenance(ctx context.Context, id string) error {
+	return s.trashEvent(ctx, kindPlannedMaintenance, id)
+}
+
+// GetDeletedPlannedMaintenances returns the planned maintenances in the trash, most recently deleted first
+func (s *SQLiteStorage) GetDeletedPlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return s.queryPlannedMaintenances(ctx, `SELECT document FROM events
+		WHERE kind = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, guid`, kindPlannedMaintenance)
+}
+
+// RestorePlannedMaintenance takes a planned maintenance out of the trash
+func (s *SQLiteStorage) RestorePlannedMaintenance(ctx context.Context, id string) (*event.PlannedMaintenance, error) {
+	document, err := s.restoreEvent(ctx, kindPlannedMaintenance, id)
+	if err != nil {
+		return nil, err
+	}
+	var pm event.PlannedMaintenance
+	if err := json.Unmarshal(document, &pm); err != nil {
+		return nil, fmt.Errorf("failed to decode planned maintenance %s: %w", id, err)
+	}
+	return &pm, nil
+}
+
+// GetPlannedMaintenanceRevisions returns the revision history of a planned maintenance, oldest first
+func (s *SQLiteStorage) GetPlannedMaintenanceRevisions(ctx context.Context, id string) ([]*event.Revision, error) {
+	return s.getRevisions(ctx, kindPlannedMaintenance, id)
+}
+
+// GetActivePlannedMaintenances returns the planned maintenances that have not ended yet,
+// using the end_effective index instead of loading the whole history
+func (s *SQLiteStorage) GetActivePlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	return s.queryPlannedMaintenances(ctx, `SELECT document FROM events
+		WHERE kind = ? AND deleted_at IS NULL AND (end_effective IS NULL OR end_effective >= ?)
+		ORDER BY guid`, kindPlannedMaintenance, time.Now().UnixNano())
+}
+
+// PurgeDeleted permanently removes the events deleted before the given time, their components cascade
+func (s *SQLiteStorage) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE deleted_at IS NOT NULL AND deleted_at < ?`, before.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted events: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(purged), nil
+}
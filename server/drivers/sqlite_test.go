@@ -0,0 +1,213 @@
+package drivers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+)
+
+func newTestSQLiteStorage(t *testing.T) (*SQLiteStorage, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "clariti.db")
+	storage, err := NewSQLiteStorage(SQLiteConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to create SQLite storage: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+	return storage, path
+}
+
+func TestNewSQLiteStorage_RequiresPath(t *testing.T) {
+	if _, err := NewSQLiteStorage(SQLiteConfig{}); err == nil {
+		t.Fatal("Expected error for empty path")
+	}
+}
+
+func TestSQLiteStorage_MigrationsAreIdempotent(t *testing.T) {
+	storage, path := newTestSQLiteStorage(t)
+	storage.Close()
+
+	reopened, err := NewSQLiteStorage(SQLiteConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to reopen SQLite storage: %v", err)
+	}
+	defer reopened.Close()
+
+	var version int
+	if err := reopened.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+	if version != len(sqliteMigrations) {
+		t.Errorf("Expected schema version %d, got %d", len(sqliteMigrations), version)
+	}
+}
+
+func TestSQLiteStorage_IncidentCRUD(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestSQLiteStorage(t)
+
+	comp := component.NewComponent("API", "api", component.NewInstance("Main", "main", component.NewPlatform("Prod", "PROD")))
+	incident := event.NewFiringIncident("Outage", "API is down", []*component.Component{comp}, event.CriticalityMajorOutage)
+	incident.ExtraFields["ticket"] = "OPS-1"
+
+	if err := storage.CreateIncident(ctx, incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	if err := storage.CreateIncident(ctx, incident); err != ErrExists {
+		t.Errorf("Expected ErrExists on duplicate create, got %v", err)
+	}
+
+	got, err := storage.GetIncident(ctx, incident.GUID)
+	if err != nil {
+		t.Fatalf("GetIncident failed: %v", err)
+	}
+	if got.Title != "Outage" || got.ExtraFields["ticket"] != "OPS-1" {
+		t.Errorf("Unexpected incident: %+v", got)
+	}
+	if len(got.Components) != 1 || got.Components[0].Instance.Platform.Code != "PROD" {
+		t.Errorf("Expected component hierarchy to round-trip, got %+v", got.Components)
+	}
+
+	var platformCode string
+	if err := storage.db.QueryRow(`SELECT platform_code FROM event_components WHERE event_guid = ?`, incident.GUID).Scan(&platformCode); err != nil {
+		t.Fatalf("Expected component row, got %v", err)
+	}
+	if platformCode != "PROD" {
+		t.Errorf("Expected platform code PROD, got %q", platformCode)
+	}
+
+	incident.Title = "Outage (updated)"
+	incident.Components = nil
+	if err := storage.UpdateIncident(ctx, incident); err != nil {
+		t.Fatalf("UpdateIncident failed: %v", err)
+	}
+	got, _ = storage.GetIncident(ctx, incident.GUID)
+	if got.Title != "Outage (updated)" {
+		t.Errorf("Expected updated title, got %q", got.Title)
+	}
+	var componentRows int
+	storage.db.QueryRow(`SELECT COUNT(*) FROM event_components WHERE event_guid = ?`, incident.GUID).Scan(&componentRows)
+	if componentRows != 0 {
+		t.Errorf("Expected components to be replaced on update, got %d rows", componentRows)
+	}
+
+	if err := storage.DeleteIncident(ctx, incident.GUID); err != nil {
+		t.Fatalf("DeleteIncident failed: %v", err)
+	}
+	if _, err := storage.GetIncident(ctx, incident.GUID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := storage.DeleteIncident(ctx, incident.GUID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound on second delete, got %v", err)
+	}
+	if err := storage.UpdateIncident(ctx, incident); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound on update of deleted incident, got %v", err)
+	}
+}
+
+func TestSQLiteStorage_PlannedMaintenanceCRUD(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestSQLiteStorage(t)
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	pm := event.NewPlannedMaintenance("Upgrade", "DB upgrade", nil, start, start.Add(2*time.Hour))
+
+	if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+	}
+	if err := storage.CreatePlannedMaintenance(ctx, pm); err != ErrExists {
+		t.Errorf("Expected ErrExists on duplicate create, got %v", err)
+	}
+
+	// Incidents and maintenances live in separate namespaces
+	if _, err := storage.GetIncident(ctx, pm.GUID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for maintenance GUID looked up as incident, got %v", err)
+	}
+
+	pm.Cancelled = true
+	if err := storage.UpdatePlannedMaintenance(ctx, pm); err != nil {
+		t.Fatalf("UpdatePlannedMaintenance failed: %v", err)
+	}
+	all, err := storage.GetAllPlannedMaintenances(ctx)
+	if err != nil || len(all) != 1 || !all[0].Cancelled {
+		t.Fatalf("Expected 1 cancelled maintenance, got %+v (err=%v)", all, err)
+	}
+	if !all[0].StartPlanned.Equal(start) {
+		t.Errorf("Expected start %v, got %v", start, all[0].StartPlanned)
+	}
+
+	if err := storage.DeletePlannedMaintenance(ctx, pm.GUID); err != nil {
+		t.Fatalf("DeletePlannedMaintenance failed: %v", err)
+	}
+	if _, err := storage.GetPlannedMaintenance(ctx, pm.GUID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestSQLiteStorage_GetActiveSkipsEndedEvents(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newTestSQLiteStorage(t)
+
+	past := time.Now().Add(-2 * time.Hour)
+	ended := time.Now().Add(-time.Hour)
+
+	ongoing := event.NewFiringIncident("Ongoing", "content", nil, event.CriticalityDegraded)
+	ongoing.StartEffective = &past
+	resolved := event.NewFiringIncident("Resolved", "content", nil, event.CriticalityDegraded)
+	resolved.StartEffective = &past
+	resolved.EndEffective = &ended
+
+	for _, incident := range []*event.Incident{ongoing, resolved} {
+		if err := storage.CreateIncident(ctx, incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+
+	active, err := storage.GetActiveIncidents(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveIncidents failed: %v", err)
+	}
+	if len(active) != 1 || active[0].GUID != ongoing.GUID {
+		t.Errorf("Expected only the ongoing incident, got %+v", active)
+	}
+
+	completed := event.NewPlannedMaintenance("Done", "content", nil, past, ended)
+	completed.StartEffective = &past
+	completed.EndEffective = &ended
+	upcoming := event.NewPlannedMaintenance("Upcoming", "content", nil, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	for _, pm := range []*event.PlannedMaintenance{completed, upcoming} {
+		if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+			t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+		}
+	}
+
+	activePMs, err := storage.GetActivePlannedMaintenances(ctx)
+	if err != nil {
+		t.Fatalf("GetActivePlannedMaintenances failed: %v", err)
+	}
+	if len(activePMs) != 1 || activePMs[0].GUID != upcoming.GUID {
+		t.Errorf("Expected only the upcoming maintenance, got %+v", activePMs)
+	}
+}
+
+func TestSQLiteStorage_CancelledContext(t *testing.T) {
+	storage, _ := newTestSQLiteStorage(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	incident := event.NewFiringIncident("Cancelled", "content", nil, event.CriticalityDegraded)
+	if err := storage.CreateIncident(ctx, incident); err == nil {
+		t.Error("Expected error when creating with a cancelled context")
+	}
+	if _, err := storage.GetAllIncidents(ctx); err == nil {
+		t.Error("Expected error when listing with a cancelled context")
+	}
+	if _, err := storage.GetIncident(context.Background(), incident.GUID); err != ErrNotFound {
+		t.Errorf("Expected cancelled create to store nothing, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package drivers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+// trashState holds the fields of a stored event document the trash relies on
+type trashState struct {
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+func readTrashState(document json.RawMessage) (trashState, error) {
+	var state trashState
+	if err := json.Unmarshal(document, &state); err != nil {
+		return state, fmt.Errorf("failed to decode event: %w", err)
+	}
+	return state, nil
+}
+
+// markDeleted returns a copy of document moved to the trash by the author of ctx, stored at version
+func markDeleted(ctx context.Context, document json.RawMessage, version int64) (json.RawMessage, error) {
+	var author interface{}
+	if a := AuthorFromContext(ctx); a != "" {
+		author = a
+	}
+	return patchDocument(document, map[string]interface{}{
+		"version":    version,
+		"deleted_at": time.Now().UTC(),
+		"deleted_by": author,
+	})
+}
+
+// markRestored returns a copy of document taken out of the trash, stored at version
+func markRestored(document json.RawMessage, version int64) (json.RawMessage, error) {
+	return patchDocument(document, map[string]interface{}{
+		"version":    version,
+		"deleted_at": nil,
+		"deleted_by": nil,
+	})
+}
+
+// setDeleted moves an event to the trash, or takes it out of it, at the next version.
+// It is the typed counterpart of markDeleted and markRestored for drivers holding decoded events.
+func setDeleted(ctx context.Context, base *event.BaseEvent, deleted bool) {
+	base.Version++
+	if !deleted {
+		base.DeletedAt = nil
+		base.DeletedBy = ""
+		return
+	}
+	now := time.Now().UTC()
+	base.DeletedAt = &now
+	base.DeletedBy = AuthorFromContext(ctx)
+}
+
+// patchDocument sets top-level fields of a JSON document, nil values remove the field
+func patchDocument(document json.RawMessage, fields map[string]interface{}) (json.RawMessage, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	for name, value := range fields {
+		if value == nil {
+			delete(doc, name)
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field %s: %w", name, err)
+		}
+		doc[name] = data
+	}
+	patched, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return patched, nil
+}
+
+// purgeable returns true if an event deleted at deletedAt must be removed by a purge of the events deleted before before
+func purgeable(deletedAt *time.Time, before time.Time) bool {
+	return deletedAt != nil && deletedAt.Before(before)
+}
+
+// sortTrash orders a trash listing most recently deleted first
+func sortTrash[T any](items []T, deletedAt func(T) *time.Time) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := deletedAt(items[i]), deletedAt(items[j])
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.After(*b)
+	})
+}
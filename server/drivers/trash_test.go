@@ -0,0 +1,127 @@
+package drivers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+func TestIncidentTrash(t *testing.T) {
+	for _, driver := range versionTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			ctx := WithAuthor(context.Background(), "alice")
+
+			incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+			if err := storage.CreateIncident(ctx, incident); err != nil {
+				t.Fatalf("CreateIncident failed: %v", err)
+			}
+			if err := storage.DeleteIncident(ctx, incident.GUID); err != nil {
+				t.Fatalf("DeleteIncident failed: %v", err)
+			}
+
+			// Deleted incidents are hidden from every other read
+			if _, err := storage.GetIncident(ctx, incident.GUID); err != ErrNotFound {
+				t.Errorf("Expected ErrNotFound for a deleted incident, got %v", err)
+			}
+			if all, err := storage.GetAllIncidents(ctx); err != nil || len(all) != 0 {
+				t.Errorf("Expected no listed incidents, got %d, %v", len(all), err)
+			}
+			if page, err := storage.ListIncidents(ctx, EventFilter{}); err != nil || len(page.Items) != 0 {
+				t.Errorf("Expected no filtered incidents, got %v", err)
+			}
+			if err := storage.DeleteIncident(ctx, incident.GUID); err != ErrNotFound {
+				t.Errorf("Expected ErrNotFound when deleting twice, got %v", err)
+			}
+			if err := storage.UpdateIncident(ctx, incident); err != ErrNotFound {
+				t.Errorf("Expected ErrNotFound when updating a deleted incident, got %v", err)
+			}
+			if err := storage.CreateIncident(ctx, incident); err != ErrExists {
+				t.Errorf("Expected ErrExists when reusing a deleted GUID, got %v", err)
+			}
+
+			trash, err := storage.GetDeletedIncidents(ctx)
+			if err != nil {
+				t.Fatalf("GetDeletedIncidents failed: %v", err)
+			}
+			if len(trash) != 1 || trash[0].GUID != incident.GUID {
+				t.Fatalf("Expected the incident in the trash, got %d incidents", len(trash))
+			}
+			if !trash[0].IsDeleted() || trash[0].DeletedBy != "alice" || trash[0].Version != 2 {
+				t.Errorf("Expected a tombstone by alice at version 2, got %v by %q at version %d",
+					trash[0].DeletedAt, trash[0].DeletedBy, trash[0].Version)
+			}
+
+			restored, err := storage.RestoreIncident(ctx, incident.GUID)
+			if err != nil {
+				t.Fatalf("RestoreIncident failed: %v", err)
+			}
+			if restored.IsDeleted() || restored.DeletedBy != "" || restored.Version != 3 {
+				t.Errorf("Expected a live incident at version 3, got %v by %q at version %d",
+					restored.DeletedAt, restored.DeletedBy, restored.Version)
+			}
+			if _, err := storage.GetIncident(ctx, incident.GUID); err != nil {
+				t.Errorf("Expected the restored incident to be readable, got %v", err)
+			}
+			if _, err := storage.RestoreIncident(ctx, incident.GUID); err != ErrNotFound {
+				t.Errorf("Expected ErrNotFound when restoring a live incident, got %v", err)
+			}
+
+			revisions, err := storage.GetIncidentRevisions(ctx, incident.GUID)
+			if err != nil || len(revisions) != 3 || revisions[2].Action != event.RevisionRestored {
+				t.Errorf("Expected a restore revision, got %d revisions, %v", len(revisions), err)
+			}
+		})
+	}
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	for _, driver := range versionTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+			ctx := context.Background()
+
+			kept := event.NewFiringIncident("Kept", "content", nil, event.CriticalityDegraded)
+			deleted := event.NewFiringIncident("Deleted", "content", nil, event.CriticalityDegraded)
+			start := time.Now().Add(time.Hour)
+			pm := event.NewPlannedMaintenance("Upgrade", "content", nil, start, start.Add(time.Hour))
+			for _, incident := range []*event.Incident{kept, deleted} {
+				if err := storage.CreateIncident(ctx, incident); err != nil {
+					t.Fatalf("CreateIncident failed: %v", err)
+				}
+			}
+			if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+				t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+			}
+			if err := storage.DeleteIncident(ctx, deleted.GUID); err != nil {
+				t.Fatalf("DeleteIncident failed: %v", err)
+			}
+			if err := storage.DeletePlannedMaintenance(ctx, pm.GUID); err != nil {
+				t.Fatalf("DeletePlannedMaintenance failed: %v", err)
+			}
+
+			// Nothing was deleted before an hour ago
+			if purged, err := storage.PurgeDeleted(ctx, time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+				t.Fatalf("Expected nothing to purge, got %d, %v", purged, err)
+			}
+			if purged, err := storage.PurgeDeleted(ctx, time.Now().Add(time.Second)); err != nil || purged != 2 {
+				t.Fatalf("Expected 2 purged events, got %d, %v", purged, err)
+			}
+
+			if _, err := storage.RestoreIncident(ctx, deleted.GUID); err != ErrNotFound {
+				t.Errorf("Expected a purged incident to be gone, got %v", err)
+			}
+			if trash, err := storage.GetDeletedPlannedMaintenances(ctx); err != nil || len(trash) != 0 {
+				t.Errorf("Expected an empty trash, got %d, %v", len(trash), err)
+			}
+			if _, err := storage.GetIncident(ctx, kept.GUID); err != nil {
+				t.Errorf("Expected the live incident to survive the purge, got %v", err)
+			}
+			if err := storage.CreateIncident(ctx, deleted); err != nil {
+				t.Errorf("Expected a purged GUID to be reusable, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package drivers
+
+// initialVersion returns the version an event is created at.
+// Events that already carry a version, such as imported ones, keep it.
+func initialVersion(version int64) int64 {
+	if version > 0 {
+		return version
+	}
+	return 1
+}
+
+// nextVersion returns the version an update is stored at.
+// An expected version of 0 skips the check, any other value must match the stored version.
+func nextVersion(stored, expected int64) (int64, error) {
+	if expected != 0 && expected != stored {
+		return 0, ErrVersionConflict
+	}
+	return stored + 1, nil
+}
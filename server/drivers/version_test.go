@@ -0,0 +1,117 @@
+package drivers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+// versionTestDrivers are the drivers run through the shared write semantics tests, S3 against a fake server
+var versionTestDrivers = []struct {
+	name    string
+	storage func(t *testing.T) EventStorage
+}{
+	{"ram", func(t *testing.T) EventStorage { return NewRAMStorage() }},
+	{"file", func(t *testing.T) EventStorage { s, _ := newTestFileStorage(t); return s }},
+	{"sqlite", func(t *testing.T) EventStorage { s, _ := newTestSQLiteStorage(t); return s }},
+	{"s3", func(t *testing.T) EventStorage { s, _ := newTestS3Storage(t); return s }},
+	{"cached", func(t *testing.T) EventStorage {
+		return NewCachedStorage(NewRAMStorage(), CacheConfig{TTL: time.Minute})
+	}},
+}
+
+func TestNextVersion(t *testing.T) {
+	if v, err := nextVersion(3, 0); err != nil || v != 4 {
+		t.Errorf("Expected unconditional update to version 4, got %d, %v", v, err)
+	}
+	if v, err := nextVersion(3, 3); err != nil || v != 4 {
+		t.Errorf("Expected matching update to version 4, got %d, %v", v, err)
+	}
+	if _, err := nextVersion(3, 2); err != ErrVersionConflict {
+		t.Errorf("Expected ErrVersionConflict for a stale version, got %v", err)
+	}
+}
+
+func TestUpdateIncident_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	for _, driver := range versionTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+
+			incident := event.NewFiringIncident("Outage", "content", nil, event.CriticalityMajorOutage)
+			if err := storage.CreateIncident(ctx, incident); err != nil {
+				t.Fatalf("CreateIncident failed: %v", err)
+			}
+			if incident.Version != 1 {
+				t.Fatalf("Expected created incident at version 1, got %d", incident.Version)
+			}
+
+			// Two editors start from version 1, only the first one wins
+			first, second := *incident, *incident
+			first.Title = "First edit"
+			second.Title = "Second edit"
+			if err := storage.UpdateIncident(ctx, &first); err != nil {
+				t.Fatalf("First UpdateIncident failed: %v", err)
+			}
+			if first.Version != 2 {
+				t.Errorf("Expected version 2 after update, got %d", first.Version)
+			}
+			if err := storage.UpdateIncident(ctx, &second); err != ErrVersionConflict {
+				t.Fatalf("Expected ErrVersionConflict for stale update, got %v", err)
+			}
+			if second.Version != 1 {
+				t.Errorf("Expected rejected update to keep its version, got %d", second.Version)
+			}
+
+			got, err := storage.GetIncident(ctx, incident.GUID)
+			if err != nil {
+				t.Fatalf("GetIncident failed: %v", err)
+			}
+			if got.Title != "First edit" || got.Version != 2 {
+				t.Errorf("Expected first edit at version 2, got %q at version %d", got.Title, got.Version)
+			}
+
+			// Version 0 is an unconditional write
+			blind := *got
+			blind.Version = 0
+			if err := storage.UpdateIncident(ctx, &blind); err != nil || blind.Version != 3 {
+				t.Errorf("Expected blind update to version 3, got %d, %v", blind.Version, err)
+			}
+		})
+	}
+}
+
+func TestUpdatePlannedMaintenance_CompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	for _, driver := range versionTestDrivers {
+		t.Run(driver.name, func(t *testing.T) {
+			storage := driver.storage(t)
+
+			start := time.Now().Add(time.Hour)
+			pm := event.NewPlannedMaintenance("Upgrade", "content", nil, start, start.Add(time.Hour))
+			if err := storage.CreatePlannedMaintenance(ctx, pm); err != nil {
+				t.Fatalf("CreatePlannedMaintenance failed: %v", err)
+			}
+
+			stale := *pm
+			updated := *pm
+			updated.Cancelled = true
+			if err := storage.UpdatePlannedMaintenance(ctx, &updated); err != nil {
+				t.Fatalf("UpdatePlannedMaintenance failed: %v", err)
+			}
+			if err := storage.UpdatePlannedMaintenance(ctx, &stale); err != ErrVersionConflict {
+				t.Errorf("Expected ErrVersionConflict for stale update, got %v", err)
+			}
+
+			got, err := storage.GetPlannedMaintenance(ctx, pm.GUID)
+			if err != nil {
+				t.Fatalf("GetPlannedMaintenance failed: %v", err)
+			}
+			if !got.Cancelled || got.Version != 2 {
+				t.Errorf("Expected cancelled maintenance at version 2, got cancelled=%v version=%d", got.Cancelled, got.Version)
+			}
+		})
+	}
+}
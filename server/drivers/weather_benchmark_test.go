@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -36,11 +37,11 @@ func BenchmarkWeatherService_GetWeatherSummary(b *testing.B) {
 		IncidentCriticality: event.CriticalityMajorOutage,
 	}
 
-	storage.CreateIncident(incident)
+	storage.CreateIncident(context.Background(), incident)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = service.GetWeatherSummary()
+		_, _ = service.GetWeatherSummary(context.Background())
 	}
 }
 
@@ -71,10 +72,11 @@ func BenchmarkWeatherService_CalculateWeatherForPath(b *testing.B) {
 		IncidentCriticality: event.CriticalityMajorOutage,
 	}
 
-	storage.CreateIncident(incident)
+	storage.CreateIncident(context.Background(), incident)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = service.calculateWeatherForPath("aws-prod", "eks-cluster", "api-gateway")
+		events, _ := service.loadWeatherEvents(context.Background(), nil)
+		_ = service.weatherForPath(events, "aws-prod", "eks-cluster", "api-gateway")
 	}
 }
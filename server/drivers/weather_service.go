@@ -1,11 +1,11 @@
 package drivers
 
 import (
+	"context"
 	"sort"
 	"time"
 
 	"github.com/gmllt/clariti/models"
-	"github.com/gmllt/clariti/models/component"
 	"github.com/gmllt/clariti/models/event"
 	"github.com/gmllt/clariti/server/config"
 )
@@ -25,10 +25,21 @@ func NewWeatherService(cfg *config.Config, storage EventStorage) *WeatherService
 }
 
 // GetWeatherSummary returns the current weather for all components
-func (ws *WeatherService) GetWeatherSummary() (*models.WeatherSummary, error) {
-	platformWeather := ws.calculatePlatformWeather()
-	instanceWeather := ws.calculateInstanceWeather()
-	componentWeather := ws.calculateComponentWeather()
+func (ws *WeatherService) GetWeatherSummary(ctx context.Context) (*models.WeatherSummary, error) {
+	return ws.GetSelectedWeatherSummary(ctx, nil)
+}
+
+// GetSelectedWeatherSummary returns the current weather for all components, only counting the events
+// whose labels match the selector
+func (ws *WeatherService) GetSelectedWeatherSummary(ctx context.Context, selector event.Selector) (*models.WeatherSummary, error) {
+	// Every path is evaluated against the same events, loaded once
+	events, err := ws.loadWeatherEvents(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	platformWeather := ws.calculatePlatformWeather(events)
+	instanceWeather := ws.calculateInstanceWeather(events)
+	componentWeather := ws.calculateComponentWeather(events)
 
 	// Calculate overall status (worst status across all)
 	overall := ws.calculateOverallWeather(platformWeather, instanceWeather, componentWeather)
@@ -41,12 +52,46 @@ func (ws *WeatherService) GetWeatherSummary() (*models.WeatherSummary, error) {
 	}, nil
 }
 
+// weatherEvents are the events a weather computation evaluates
+type weatherEvents struct {
+	incidents    []*event.Incident
+	maintenances []*event.PlannedMaintenance
+}
+
+// loadWeatherEvents loads the incidents and planned maintenances that may be active and match the selector
+func (ws *WeatherService) loadWeatherEvents(ctx context.Context, selector event.Selector) (*weatherEvents, error) {
+	incidents, err := ws.getCandidateIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	maintenances, err := ws.getCandidatePlannedMaintenances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(selector) == 0 {
+		return &weatherEvents{incidents: incidents, maintenances: maintenances}, nil
+	}
+
+	events := &weatherEvents{}
+	for _, incident := range incidents {
+		if selector.Matches(incident.Labels) {
+			events.incidents = append(events.incidents, incident)
+		}
+	}
+	for _, maintenance := range maintenances {
+		if selector.Matches(maintenance.Labels) {
+			events.maintenances = append(events.maintenances, maintenance)
+		}
+	}
+	return events, nil
+}
+
 // calculatePlatformWeather calculates weather for all platforms
-func (ws *WeatherService) calculatePlatformWeather() []models.ServiceWeather {
+func (ws *WeatherService) calculatePlatformWeather(events *weatherEvents) []models.ServiceWeather {
 	var weather []models.ServiceWeather
 
 	for _, platform := range ws.config.Components.Platforms {
-		platformWeather := ws.calculateWeatherForPath(platform.Code, "", "")
+		platformWeather := ws.weatherForPath(events, platform.Code, "", "")
 		platformWeather.Platform = platform.Name
 		platformWeather.PlatformCode = platform.Code
 		weather = append(weather, platformWeather)
@@ -56,12 +101,12 @@ func (ws *WeatherService) calculatePlatformWeather() []models.ServiceWeather {
 }
 
 // calculateInstanceWeather calculates weather for all instances
-func (ws *WeatherService) calculateInstanceWeather() []models.ServiceWeather {
+func (ws *WeatherService) calculateInstanceWeather(events *weatherEvents) []models.ServiceWeather {
 	var weather []models.ServiceWeather
 
 	for _, platform := range ws.config.Components.Platforms {
 		for _, instance := range platform.Instances {
-			instanceWeather := ws.calculateWeatherForPath(platform.Code, instance.Code, "")
+			instanceWeather := ws.weatherForPath(events, platform.Code, instance.Code, "")
 			instanceWeather.Platform = platform.Name
 			instanceWeather.PlatformCode = platform.Code
 			instanceWeather.Instance = instance.Name
@@ -74,13 +119,13 @@ func (ws *WeatherService) calculateInstanceWeather() []models.ServiceWeather {
 }
 
 // calculateComponentWeather calculates weather for all components
-func (ws *WeatherService) calculateComponentWeather() []models.ServiceWeather {
+func (ws *WeatherService) calculateComponentWeather(events *weatherEvents) []models.ServiceWeather {
 	var weather []models.ServiceWeather
 
 	for _, platform := range ws.config.Components.Platforms {
 		for _, instance := range platform.Instances {
 			for _, comp := range instance.Components {
-				componentWeather := ws.calculateWeatherForPath(platform.Code, instance.Code, comp.Code)
+				componentWeather := ws.weatherForPath(events, platform.Code, instance.Code, comp.Code)
 				componentWeather.Platform = platform.Name
 				componentWeather.PlatformCode = platform.Code
 				componentWeather.Instance = instance.Name
@@ -95,9 +140,9 @@ func (ws *WeatherService) calculateComponentWeather() []models.ServiceWeather {
 	return weather
 }
 
-// calculateWeatherForPath calculates weather for a specific component path
-func (ws *WeatherService) calculateWeatherForPath(platformCode, instanceCode, componentCode string) models.ServiceWeather {
-	activeEvents := ws.getActiveEventsForPath(platformCode, instanceCode, componentCode)
+// weatherForPath calculates weather for a specific component path from already loaded events
+func (ws *WeatherService) weatherForPath(events *weatherEvents, platformCode, instanceCode, componentCode string) models.ServiceWeather {
+	activeEvents := ws.getActiveEventsForPath(events, platformCode, instanceCode, componentCode)
 
 	// Find the highest criticality among active events
 	maxCriticality := event.CriticalityOperational
@@ -116,27 +161,28 @@ func (ws *WeatherService) calculateWeatherForPath(platformCode, instanceCode, co
 }
 
 // getActiveEventsForPath gets all active events affecting a specific component path
-func (ws *WeatherService) getActiveEventsForPath(platformCode, instanceCode, componentCode string) []models.ActiveEvent {
+func (ws *WeatherService) getActiveEventsForPath(events *weatherEvents, platformCode, instanceCode, componentCode string) []models.ActiveEvent {
 	var activeEvents []models.ActiveEvent
 
-	// Check incidents
-	incidents, _ := ws.storage.GetAllIncidents()
-	for _, incident := range incidents {
-		if ws.isEventActive(incident) && ws.eventAffectsPath(incident.Components, platformCode, instanceCode, componentCode) {
+	// Check incidents, at their impact on the components of the path
+	for _, incident := range events.incidents {
+		if !ws.isEventActive(incident) {
+			continue
+		}
+		if criticality, affected := ws.incidentCriticalityForPath(incident, platformCode, instanceCode, componentCode); affected {
 			activeEvents = append(activeEvents, models.ActiveEvent{
 				GUID:        incident.GUID,
 				Type:        incident.Type(),
 				Title:       incident.Title,
 				Status:      incident.Status(),
-				Criticality: incident.Criticality(),
+				Criticality: criticality,
 			})
 		}
 	}
 
 	// Check planned maintenances
-	maintenances, _ := ws.storage.GetAllPlannedMaintenances()
-	for _, maintenance := range maintenances {
-		if ws.isEventActive(maintenance) && ws.eventAffectsPath(maintenance.Components, platformCode, instanceCode, componentCode) {
+	for _, maintenance := range events.maintenances {
+		if ws.isEventActive(maintenance) && componentsMatchPath(maintenance.Components, platformCode, instanceCode, componentCode) {
 			activeEvents = append(activeEvents, models.ActiveEvent{
 				GUID:        maintenance.GUID,
 				Type:        maintenance.Type(),
@@ -155,11 +201,31 @@ func (ws *WeatherService) getActiveEventsForPath(platformCode, instanceCode, com
 	return activeEvents
 }
 
+// getCandidateIncidents returns the incidents that may be active, letting indexed drivers skip ended ones
+func (ws *WeatherService) getCandidateIncidents(ctx context.Context) ([]*event.Incident, error) {
+	if active, ok := ws.storage.(ActiveEventStorage); ok {
+		return active.GetActiveIncidents(ctx)
+	}
+	return ws.storage.GetAllIncidents(ctx)
+}
+
+// getCandidatePlannedMaintenances returns the planned maintenances that may be active, letting indexed drivers skip ended ones
+func (ws *WeatherService) getCandidatePlannedMaintenances(ctx context.Context) ([]*event.PlannedMaintenance, error) {
+	if active, ok := ws.storage.(ActiveEventStorage); ok {
+		return active.GetActivePlannedMaintenances(ctx)
+	}
+	return ws.storage.GetAllPlannedMaintenances(ctx)
+}
+
 // isEventActive checks if an event is currently active
 func (ws *WeatherService) isEventActive(evt event.Event) bool {
 	// For the interface, we need to access the timing through a type assertion
 	switch e := evt.(type) {
 	case *event.Incident:
+		// Incidents in the lifecycle are active until resolved
+		if e.LifecycleStatus != "" {
+			return e.IsOpen()
+		}
 		status := e.Status()
 		// An incident is active if it's ongoing or acknowledged
 		// Also consider incidents without start_effective as active (immediate incidents)
@@ -172,6 +238,10 @@ func (ws *WeatherService) isEventActive(evt event.Event) bool {
 		}
 		return false
 	case *event.PlannedMaintenance:
+		// A series only weighs on the weather during its occurrences, cancelled ones excluded
+		if e.IsRecurring() {
+			return e.Status() == event.StatusOnGoing
+		}
 		status := e.Status()
 		// A maintenance is active if it's ongoing or planned
 		if status == event.StatusOnGoing || status == event.StatusPlanned {
@@ -186,41 +256,20 @@ func (ws *WeatherService) isEventActive(evt event.Event) bool {
 	return false
 }
 
-// eventAffectsPath checks if an event affects a specific component path
-func (ws *WeatherService) eventAffectsPath(components []*component.Component, platformCode, instanceCode, componentCode string) bool {
-	for _, comp := range components {
-		if ws.componentMatchesPath(comp, platformCode, instanceCode, componentCode) {
-			return true
+// incidentCriticalityForPath returns the highest impact of an incident on its components matching a path,
+// and whether any of them does
+func (ws *WeatherService) incidentCriticalityForPath(incident *event.Incident, platformCode, instanceCode, componentCode string) (event.Criticality, bool) {
+	highest, affected := event.CriticalityUnknown, false
+	for _, comp := range incident.Components {
+		if comp == nil || !componentMatchesPath(comp, platformCode, instanceCode, componentCode) {
+			continue
 		}
-	}
-	return false
-}
-
-// componentMatchesPath checks if a component matches the given path
-func (ws *WeatherService) componentMatchesPath(comp *component.Component, platformCode, instanceCode, componentCode string) bool {
-	// Get platform code through the hierarchy
-	var compPlatformCode, compInstanceCode, compComponentCode string
-
-	if comp.Instance != nil {
-		compInstanceCode = comp.Instance.Code
-		if comp.Instance.Platform != nil {
-			compPlatformCode = comp.Instance.Platform.Code
+		affected = true
+		if criticality := incident.CriticalityFor(comp); criticality > highest {
+			highest = criticality
 		}
 	}
-	compComponentCode = comp.Code
-
-	// If component code is specified, it must match exactly
-	if componentCode != "" {
-		return compPlatformCode == platformCode && compInstanceCode == instanceCode && compComponentCode == componentCode
-	}
-
-	// If only instance is specified, component must be in that instance
-	if instanceCode != "" {
-		return compPlatformCode == platformCode && compInstanceCode == instanceCode
-	}
-
-	// If only platform is specified, component must be in that platform
-	return compPlatformCode == platformCode
+	return highest, affected
 }
 
 // calculateOverallWeather calculates the overall system weather
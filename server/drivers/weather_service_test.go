@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -52,7 +53,7 @@ func TestWeatherService_GetWeatherSummary(t *testing.T) {
 	service := NewWeatherService(cfg, storage)
 
 	// Test with no incidents
-	summary, err := service.GetWeatherSummary()
+	summary, err := service.GetWeatherSummary(context.Background())
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -111,13 +112,13 @@ func TestWeatherService_WithActiveIncidents(t *testing.T) {
 		event.CriticalityMajorOutage,
 	)
 
-	err := storage.CreateIncident(incident)
+	err := storage.CreateIncident(context.Background(), incident)
 	if err != nil {
 		t.Fatalf("Failed to create incident: %v", err)
 	}
 
 	// Test weather with active incident
-	summary, err := service.GetWeatherSummary()
+	summary, err := service.GetWeatherSummary(context.Background())
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -133,6 +134,64 @@ func TestWeatherService_WithActiveIncidents(t *testing.T) {
 	}
 }
 
+func TestWeatherService_GetSelectedWeatherSummary(t *testing.T) {
+	cfg := &config.Config{
+		Components: config.ComponentsConfig{
+			Platforms: []config.PlatformConfig{
+				{
+					Name: "TestPlatform",
+					Code: "TST",
+					Instances: []config.InstanceConfig{
+						{
+							Name:       "TestInstance",
+							Code:       "test",
+							Components: []config.ComponentConfig{{Name: "TestComponent", Code: "comp"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	storage := NewRAMStorage()
+	service := NewWeatherService(cfg, storage)
+	comp := component.NewComponent("TestComponent", "comp", component.NewInstance("TestInstance", "test", component.NewPlatform("TestPlatform", "TST")))
+
+	payments := event.NewFiringIncident("Payments outage", "Card payments fail", []*component.Component{comp}, event.CriticalityMajorOutage)
+	payments.Labels = map[string]string{"team": "payments"}
+	search := event.NewFiringIncident("Slow search", "Search is slow", []*component.Component{comp}, event.CriticalityDegraded)
+	search.Labels = map[string]string{"team": "search"}
+	for _, incident := range []*event.Incident{payments, search} {
+		if err := storage.CreateIncident(context.Background(), incident); err != nil {
+			t.Fatalf("Failed to create incident: %v", err)
+		}
+	}
+
+	tests := []struct {
+		selector string
+		expected event.Criticality
+		events   int
+	}{
+		{selector: "", expected: event.CriticalityMajorOutage, events: 2},
+		{selector: "team=search", expected: event.CriticalityDegraded, events: 1},
+		{selector: "team notin (payments,search)", expected: event.CriticalityOperational, events: 0},
+	}
+	for _, tt := range tests {
+		selector, err := event.ParseSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseSelector failed: %v", err)
+		}
+		summary, err := service.GetSelectedWeatherSummary(context.Background(), selector)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if summary.Overall.Status != tt.expected || len(summary.Overall.ActiveEvents) != tt.events {
+			t.Errorf("%q: expected %s with %d events, got %s with %d", tt.selector, tt.expected, tt.events,
+				summary.Overall.Status, len(summary.Overall.ActiveEvents))
+		}
+	}
+}
+
 func TestWeatherService_CriticalityPriority(t *testing.T) {
 	// Setup
 	cfg := &config.Config{
@@ -180,11 +239,11 @@ func TestWeatherService_CriticalityPriority(t *testing.T) {
 		event.CriticalityMajorOutage,
 	)
 
-	storage.CreateIncident(incident1)
-	storage.CreateIncident(incident2)
+	storage.CreateIncident(context.Background(), incident1)
+	storage.CreateIncident(context.Background(), incident2)
 
 	// Test weather - should show highest criticality
-	summary, err := service.GetWeatherSummary()
+	summary, err := service.GetWeatherSummary(context.Background())
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -200,10 +259,61 @@ func TestWeatherService_CriticalityPriority(t *testing.T) {
 	}
 }
 
-func TestWeatherService_ComponentMatchesPath(t *testing.T) {
+func TestWeatherService_ComponentCriticalities(t *testing.T) {
+	cfg := &config.Config{
+		Components: config.ComponentsConfig{
+			Platforms: []config.PlatformConfig{
+				{
+					Name: "TestPlatform",
+					Code: "TST",
+					Instances: []config.InstanceConfig{
+						{
+							Name: "TestInstance",
+							Code: "test",
+							Components: []config.ComponentConfig{
+								{Name: "API", Code: "api"},
+								{Name: "Cache", Code: "cache"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
 	storage := NewRAMStorage()
-	service := NewWeatherService(&config.Config{}, storage)
+	service := NewWeatherService(cfg, storage)
+
+	platform := component.NewPlatform("TestPlatform", "TST")
+	instance := component.NewInstance("TestInstance", "test", platform)
+	api := component.NewComponent("API", "api", instance)
+	cache := component.NewComponent("Cache", "cache", instance)
+
+	// One outage, major for the API and only degraded for the cache
+	incident := event.NewFiringIncident("Outage", "Requests fail", []*component.Component{api, cache}, event.CriticalityDegraded)
+	incident.ComponentCriticalities = map[string]event.Criticality{"api": event.CriticalityMajorOutage}
+	if err := storage.CreateIncident(context.Background(), incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	events, err := service.loadWeatherEvents(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("loadWeatherEvents failed: %v", err)
+	}
+	expected := map[string]event.Criticality{"api": event.CriticalityMajorOutage, "cache": event.CriticalityDegraded}
+	for code, criticality := range expected {
+		weather := service.weatherForPath(events, "TST", "test", code)
+		if weather.Status != criticality || len(weather.ActiveEvents) != 1 || weather.ActiveEvents[0].Criticality != criticality {
+			t.Errorf("Expected %s to be %s, got %s", code, criticality, weather.Status)
+		}
+	}
+
+	// The instance holds both components and shows the worst impact
+	if weather := service.weatherForPath(events, "TST", "test", ""); weather.Status != event.CriticalityMajorOutage {
+		t.Errorf("Expected the instance to be in major outage, got %s", weather.Status)
+	}
+}
 
+func TestWeatherService_ComponentMatchesPath(t *testing.T) {
 	// Create test component hierarchy
 	platform := component.NewPlatform("TestPlatform", "TST")
 	instance := component.NewInstance("TestInstance", "test", platform)
@@ -226,7 +336,7 @@ func TestWeatherService_ComponentMatchesPath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			match := service.componentMatchesPath(comp, tt.platformCode, tt.instanceCode, tt.componentCode)
+			match := componentMatchesPath(comp, tt.platformCode, tt.instanceCode, tt.componentCode)
 			if match != tt.expectedMatch {
 				t.Errorf("Expected %v, got %v for path %s/%s/%s", tt.expectedMatch, match, tt.platformCode, tt.instanceCode, tt.componentCode)
 			}
@@ -242,6 +352,17 @@ func TestWeatherService_IsEventActive(t *testing.T) {
 	instance := component.NewInstance("Test", "test", platform)
 	comp := component.NewComponent("Test", "comp", instance)
 
+	// Daily series, one in the middle of today's occurrence and one with today's occurrence cancelled
+	recurring := func(cancelToday bool) *event.PlannedMaintenance {
+		start := time.Now().AddDate(0, 0, -7).Add(-time.Hour)
+		pm := event.NewPlannedMaintenance("Test", "Test", []*component.Component{comp}, start, start.Add(2*time.Hour))
+		pm.Recurrence = "FREQ=DAILY"
+		if cancelToday {
+			pm.Overrides = []event.OccurrenceOverride{{RecurrenceID: start.AddDate(0, 0, 7), Cancelled: true}}
+		}
+		return pm
+	}
+
 	tests := []struct {
 		name     string
 		event    event.Event
@@ -262,6 +383,16 @@ func TestWeatherService_IsEventActive(t *testing.T) {
 			event:    event.NewPlannedMaintenance("Test", "Test", []*component.Component{comp}, time.Now(), time.Now().Add(time.Hour)),
 			expected: true,
 		},
+		{
+			name:     "Recurring maintenance during an occurrence",
+			event:    recurring(false),
+			expected: true,
+		},
+		{
+			name:     "Recurring maintenance with the occurrence cancelled",
+			event:    recurring(true),
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -304,3 +435,21 @@ func TestWeatherService_EventWithTiming(t *testing.T) {
 		t.Error("Expected incident with future start time to not be active based on status")
 	}
 }
+
+func TestWeatherService_GetWeatherSummary_CancelledContext(t *testing.T) {
+	cfg := &config.Config{
+		Components: config.ComponentsConfig{
+			Platforms: []config.PlatformConfig{
+				{Name: "TestPlatform", Code: "TST"},
+			},
+		},
+	}
+	service := NewWeatherService(cfg, NewRAMStorage())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := service.GetWeatherSummary(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ActionRequest represents the optional JSON body of an action endpoint, such as resolving an incident
+type ActionRequest struct {
+	Message string `json:"message,omitempty"`
+}
+
+// decodeActionRequest decodes the body of an action endpoint, an empty body is an empty request
+func decodeActionRequest(r *http.Request) (ActionRequest, error) {
+	var req ActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return req, err
+	}
+	return req, nil
+}
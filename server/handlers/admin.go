@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/server/drivers"
+	"github.com/prometheus/common/version"
+)
+
+// maxImportSize bounds the size of an imported archive
+const maxImportSize = 256 << 20
+
+// archiveContentType is the media type of the NDJSON archives
+const archiveContentType = "application/x-ndjson"
+
+// AdminHandler handles the backup and restore of the events
+type AdminHandler struct {
+	storage drivers.EventStorage
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(storage drivers.EventStorage) *AdminHandler {
+	return &AdminHandler{storage: storage}
+}
+
+// writeJSON writes a JSON response
+func (h *AdminHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// writeError writes an error response
+func (h *AdminHandler) writeError(w http.ResponseWriter, status int, message string) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
+
+// HandleExport handles /admin/export endpoint, returning an NDJSON archive of every event, deleted ones included
+func (h *AdminHandler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("AdminHandler")
+
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).Warn("Method not allowed for export endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	generator := "clariti"
+	if version.Version != "" {
+		generator += " " + version.Version
+	}
+	archive, err := drivers.ExportArchive(r.Context(), h.storage, generator)
+	if err != nil {
+		log.WithError(err).Error("Failed to export events")
+		h.writeError(w, http.StatusInternalServerError, "Failed to export events")
+		return
+	}
+
+	filename := fmt.Sprintf("clariti-%s.ndjson", archive.Header.ExportedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", archiveContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := archive.WriteTo(w); err != nil {
+		log.WithError(err).Error("Failed to write export archive")
+		return
+	}
+
+	log.WithField("incidents", len(archive.Incidents)).WithField("planned_maintenances", len(archive.PlannedMaintenances)).Info("Events exported successfully")
+}
+
+// HandleImport handles /admin/import endpoint.
+// The mode query parameter selects merge (default) or replace, the archive is checked before anything is written.
+func (h *AdminHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("AdminHandler")
+
+	if r.Method != http.MethodPost {
+		log.WithField("method", r.Method).Warn("Method not allowed for import endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	mode := drivers.ImportMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = drivers.ImportMerge
+	}
+	if mode != drivers.ImportMerge && mode != drivers.ImportReplace {
+		log.WithField("mode", mode).Warn("Invalid import mode")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Invalid query parameters",
+			"validation_errors": []string{"parameter 'mode' must be one of merge, replace"},
+		})
+		return
+	}
+
+	archive, err := drivers.ReadArchive(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		log.WithError(err).Warn("Invalid import archive")
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.writeError(w, http.StatusRequestEntityTooLarge, "Archive too large")
+			return
+		}
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := drivers.ImportArchive(r.Context(), h.storage, archive, mode)
+	if err != nil {
+		log.WithError(err).Error("Failed to import events")
+		h.writeError(w, http.StatusInternalServerError, "Failed to import events, the import stopped partway")
+		return
+	}
+
+	log.WithField("mode", mode).
+		WithField("incident_conflicts", len(report.Incidents.Conflicts)).
+		WithField("planned_maintenance_conflicts", len(report.PlannedMaintenances.Conflicts)).
+		Info("Events imported successfully")
+	h.writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+func TestAdminHandler_ExportImport(t *testing.T) {
+	source := drivers.NewRAMStorage()
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := source.CreateIncident(context.Background(), incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	NewAdminHandler(source).HandleExport(rr, httptest.NewRequest(http.MethodGet, "/api/v1/admin/export", nil))
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != archiveContentType {
+		t.Fatalf("Expected 200 with an NDJSON archive, got %d with %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rr.Header().Get("Content-Disposition"), "clariti-") {
+		t.Errorf("Expected an attachment filename, got %q", rr.Header().Get("Content-Disposition"))
+	}
+	archive := rr.Body.Bytes()
+
+	destination := drivers.NewRAMStorage()
+	admin := NewAdminHandler(destination)
+	importArchive := func(query string, body []byte) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		admin.HandleImport(rr, httptest.NewRequest(http.MethodPost, "/api/v1/admin/import"+query, bytes.NewReader(body)))
+		return rr
+	}
+
+	rr = importArchive("", archive)
+	var report drivers.ImportReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with a report, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if report.Mode != drivers.ImportMerge || report.Incidents.Created != 1 {
+		t.Errorf("Expected 1 incident created by a merge, got %+v", report)
+	}
+	if _, err := destination.GetIncident(context.Background(), incident.GUID); err != nil {
+		t.Errorf("Expected the imported incident, got %v", err)
+	}
+
+	if rr := importArchive("?mode=replace", archive); rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"unchanged":1`) {
+		t.Errorf("Expected the incident to be unchanged by a replace, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := importArchive("?mode=overwrite", archive); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid mode, got %d", rr.Code)
+	}
+	if rr := importArchive("", archive[:len(archive)/2]); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a truncated archive, got %d", rr.Code)
+	}
+}
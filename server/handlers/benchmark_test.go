@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -54,7 +55,7 @@ func BenchmarkWeatherHandler_HandleWeather(b *testing.B) {
 		IncidentCriticality: event.CriticalityMajorOutage,
 	}
 
-	storage.CreateIncident(incident)
+	storage.CreateIncident(context.Background(), incident)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
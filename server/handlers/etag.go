@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// formatETag returns the entity tag of an event version
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch returns the versions accepted by the If-Match header, which may list several ETags.
+// No version means any is accepted, either because the header is absent or because it is "*".
+func parseIfMatch(r *http.Request) ([]int64, error) {
+	value := strings.TrimSpace(strings.Join(r.Header.Values("If-Match"), ","))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	var versions []int64
+	for _, tag := range strings.Split(value, ",") {
+		// Lists may hold empty elements, versions are compared exactly and a weak tag carries the same number
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "" {
+			continue
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			return nil, fmt.Errorf("invalid If-Match header '%s'. Expected quoted ETags such as \"3\"", value)
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("invalid If-Match header '%s'. Expected ETags returned by this API", value)
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("invalid If-Match header '%s'. Expected quoted ETags such as \"3\"", value)
+	}
+	return versions, nil
+}
+
+// matchesVersion reports whether a stored version satisfies the versions accepted by an If-Match header
+func matchesVersion(expected []int64, version int64) bool {
+	return len(expected) == 0 || slices.Contains(expected, version)
+}
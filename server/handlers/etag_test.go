@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected []int64
+		valid    bool
+	}{
+		{"", nil, true},
+		{"*", nil, true},
+		{`"3"`, []int64{3}, true},
+		{`W/"3"`, []int64{3}, true},
+		{`"3", "4"`, []int64{3, 4}, true},
+		{`"3",W/"4",`, []int64{3, 4}, true},
+		{"3", nil, false},
+		{`"abc"`, nil, false},
+		{`"0"`, nil, false},
+		{`"3", 4`, nil, false},
+		{`"3", *`, nil, false},
+		{",", nil, false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/incidents/x", nil)
+		if tt.header != "" {
+			req.Header.Set("If-Match", tt.header)
+		}
+		versions, err := parseIfMatch(req)
+		if (err == nil) != tt.valid || !slices.Equal(versions, tt.expected) {
+			t.Errorf("If-Match %q: expected %v (valid=%v), got %v, %v", tt.header, tt.expected, tt.valid, versions, err)
+		}
+	}
+
+	// Lists may also be split over several header lines
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/incidents/x", nil)
+	req.Header.Add("If-Match", `"3"`)
+	req.Header.Add("If-Match", `"4"`)
+	if versions, err := parseIfMatch(req); err != nil || !slices.Equal(versions, []int64{3, 4}) {
+		t.Errorf("Expected both header lines to be read, got %v, %v", versions, err)
+	}
+}
+
+func TestIncidentHandler_IfMatch(t *testing.T) {
+	handler := NewIncidentHandler(drivers.NewRAMStorage())
+	body := `{"title":"Outage","content":"API down","components":["api"]}`
+
+	rr := httptest.NewRecorder()
+	handler.HandleIncidents(rr, httptest.NewRequest(http.MethodPost, "/api/v1/incidents", strings.NewReader(body)))
+	if rr.Code != http.StatusCreated || rr.Header().Get("ETag") != `"1"` {
+		t.Fatalf("Expected 201 with ETag \"1\", got %d with %q", rr.Code, rr.Header().Get("ETag"))
+	}
+	id := decodeGUID(t, rr)
+
+	put := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/incidents/"+id, strings.NewReader(body))
+		req.SetPathValue("id", id)
+		req.Header.Set("If-Match", ifMatch)
+		rr := httptest.NewRecorder()
+		handler.HandleIncidentByID(rr, req)
+		return rr
+	}
+
+	if rr := put(`"1"`); rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"2"` {
+		t.Errorf("Expected 200 with ETag \"2\", got %d with %q", rr.Code, rr.Header().Get("ETag"))
+	}
+	if rr := put(`"1"`); rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a stale If-Match, got %d", rr.Code)
+	}
+	// Any listed ETag may match the stored version
+	if rr := put(`"1", "2"`); rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"3"` {
+		t.Errorf("Expected 200 with ETag \"3\" for a list holding the current ETag, got %d with %q", rr.Code, rr.Header().Get("ETag"))
+	}
+	if rr := put(`"1", "2"`); rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for a list of stale ETags, got %d", rr.Code)
+	}
+	if rr := put("garbage"); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed If-Match, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+id, nil)
+	req.SetPathValue("id", id)
+	rr = httptest.NewRecorder()
+	handler.HandleIncidentByID(rr, req)
+	if rr.Header().Get("ETag") != `"3"` {
+		t.Errorf("Expected GET to return ETag \"3\", got %q", rr.Header().Get("ETag"))
+	}
+}
+
+func decodeGUID(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	var created struct {
+		GUID string `json:"guid"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil || created.GUID == "" {
+		t.Fatalf("Failed to decode created event: %v", err)
+	}
+	return created.GUID
+}
+
+func TestIncidentHandler_Revisions(t *testing.T) {
+	handler := NewIncidentHandler(drivers.NewRAMStorage())
+
+	rr := httptest.NewRecorder()
+	handler.HandleIncidents(rr, httptest.NewRequest(http.MethodPost, "/api/v1/incidents",
+		strings.NewReader(`{"title":"Outage","content":"API down","components":["api"]}`)))
+	id := decodeGUID(t, rr)
+
+	history := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/incidents/"+id+"/revisions", nil)
+		req.SetPathValue("id", id)
+		rr := httptest.NewRecorder()
+		handler.HandleIncidentRevisions(rr, req)
+		return rr
+	}
+
+	rr = history(id)
+	var revisions []map[string]interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &revisions); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with a JSON list, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(revisions) != 1 || revisions[0]["action"] != "created" {
+		t.Errorf("Expected a single creation revision, got %v", revisions)
+	}
+
+	if rr := history("missing"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown incident, got %d", rr.Code)
+	}
+}
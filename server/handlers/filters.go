@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// maxListLimit caps the page size a client can request
+const maxListLimit = 1000
+
+// nextCursorHeader carries the cursor of the next page of a listing, absent on the last page
+const nextCursorHeader = "X-Next-Cursor"
+
+// listParams lists the values a listing endpoint accepts for its enumerated query parameters
+type listParams struct {
+	statuses []event.Status
+	types    []event.TypeEvent
+}
+
+var incidentListParams = listParams{
+	statuses: []event.Status{event.StatusInvestigating, event.StatusAcknowledged, event.StatusIdentified, event.StatusMonitoring, event.StatusOnGoing, event.StatusResolved, event.StatusUnknown},
+	types:    []event.TypeEvent{event.TypeFiringIncident, event.TypeKnownIssue},
+}
+
+var plannedMaintenanceListParams = listParams{
+	statuses: []event.Status{event.StatusPlanned, event.StatusOnGoing, event.StatusResolved, event.StatusCanceled, event.StatusUnknown},
+	types:    []event.TypeEvent{event.TypePlannedMaintenance},
+}
+
+// splitQueryValues returns the comma separated values of a repeatable query parameter
+func splitQueryValues(query url.Values, name string) []string {
+	var values []string
+	for _, raw := range query[name] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseEventFilter builds a storage filter from the listing query parameters:
+// status, type, criticality, component (platform[/instance[/component]]), from, to (RFC3339),
+// q (free text), selector (labels), sort (asc or desc), cursor and limit
+func parseEventFilter(r *http.Request, params listParams) (drivers.EventFilter, []string) {
+	query := r.URL.Query()
+	var filter drivers.EventFilter
+	var errors []string
+
+	for _, value := range splitQueryValues(query, "status") {
+		status := event.Status(strings.ToLower(value))
+		if !containsStatus(params.statuses, status) {
+			errors = append(errors, fmt.Sprintf("invalid status '%s'. Valid values: %s", value, joinValues(params.statuses)))
+			continue
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	for _, value := range splitQueryValues(query, "type") {
+		typ := event.TypeEvent(strings.ToLower(value))
+		if !containsType(params.types, typ) {
+			errors = append(errors, fmt.Sprintf("invalid type '%s'. Valid values: %s", value, joinValues(params.types)))
+			continue
+		}
+		filter.Types = append(filter.Types, typ)
+	}
+
+	for _, value := range splitQueryValues(query, "criticality") {
+		criticality, err := parseCriticality(value)
+		if err != nil {
+			errors = append(errors, err.Error())
+			continue
+		}
+		filter.Criticalities = append(filter.Criticalities, criticality)
+	}
+
+	if path := strings.Trim(query.Get("component"), "/"); path != "" {
+		parts := strings.Split(path, "/")
+		if len(parts) > 3 || containsEmpty(parts) {
+			errors = append(errors, fmt.Sprintf("invalid component path '%s'. Expected platform[/instance[/component]]", path))
+		} else {
+			filter.PlatformCode = parts[0]
+			if len(parts) > 1 {
+				filter.InstanceCode = parts[1]
+			}
+			if len(parts) > 2 {
+				filter.ComponentCode = parts[2]
+			}
+		}
+	}
+
+	for name, dest := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("invalid %s '%s'. Expected RFC3339 format", name, value))
+				continue
+			}
+			*dest = &t
+		}
+	}
+
+	filter.Text = strings.TrimSpace(query.Get("q"))
+
+	selector, err := event.ParseSelector(query.Get("selector"))
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	filter.Selector = selector
+
+	switch sort := drivers.SortOrder(strings.ToLower(query.Get("sort"))); sort {
+	case "", drivers.SortAscending, drivers.SortDescending:
+		filter.Sort = sort
+	default:
+		errors = append(errors, fmt.Sprintf("invalid sort '%s'. Valid values: asc, desc", query.Get("sort")))
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			errors = append(errors, fmt.Sprintf("invalid limit '%s'. Expected a number between 1 and %d", value, maxListLimit))
+		} else {
+			filter.Limit = limit
+		}
+	}
+
+	filter.Cursor = query.Get("cursor")
+
+	if len(errors) == 0 {
+		if err := filter.Validate(); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	return filter, errors
+}
+
+// validateLabels returns the validation errors of the labels of an event request, in a stable order
+func validateLabels(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errors []string
+	for _, key := range keys {
+		if err := event.ValidateLabelKey(key); err != nil {
+			errors = append(errors, fmt.Sprintf("labels: %v", err))
+		} else if err := event.ValidateLabelValue(labels[key]); err != nil {
+			errors = append(errors, fmt.Sprintf("labels: %v", err))
+		}
+	}
+	return errors
+}
+
+func containsStatus(values []event.Status, value event.Status) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(values []event.TypeEvent, value event.TypeEvent) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEmpty(values []string) bool {
+	for _, v := range values {
+		if v == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func joinValues[T ~string](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gmllt/clariti/models/component"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+func TestParseEventFilter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/incidents?status=ongoing,resolved&type=known_issue&criticality=degraded&component=PROD/main&from=2025-01-01T00:00:00Z&q=api&selector=team%3Dpayments,!draft&sort=asc&limit=10", nil)
+
+	filter, errors := parseEventFilter(req, incidentListParams)
+	if len(errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+	if len(filter.Statuses) != 2 || len(filter.Types) != 1 || len(filter.Criticalities) != 1 {
+		t.Errorf("Unexpected enumerated values: %+v", filter)
+	}
+	if filter.PlatformCode != "PROD" || filter.InstanceCode != "main" || filter.ComponentCode != "" {
+		t.Errorf("Unexpected component path: %+v", filter)
+	}
+	if filter.From == nil || filter.To != nil || filter.Text != "api" || filter.Sort != drivers.SortAscending || filter.Limit != 10 {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+	if filter.Selector.String() != "team=payments,!draft" {
+		t.Errorf("Unexpected selector: %s", filter.Selector)
+	}
+}
+
+func TestParseEventFilter_InvalidValues(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/incidents?status=planned&type=firing&component=a/b/c/d&from=yesterday&selector=team+in+payments&sort=up&limit=0", nil)
+
+	_, errors := parseEventFilter(req, incidentListParams)
+	// planned is not an incident status, every other parameter is malformed except type
+	if len(errors) != 6 {
+		t.Errorf("Expected 6 errors, got %d: %v", len(errors), errors)
+	}
+}
+
+func TestIncidentHandler_ListWithFilters(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	handler := NewIncidentHandler(storage)
+
+	comp := component.NewComponent("API", "api", component.NewInstance("Main", "main", component.NewPlatform("Prod", "PROD")))
+	for _, incident := range []*event.Incident{
+		event.NewFiringIncident("Outage", "API down", []*component.Component{comp}, event.CriticalityMajorOutage),
+		event.NewKnownIssue("Known issue", "Slow API", []*component.Component{comp}, event.CriticalityDegraded),
+		event.NewKnownIssue("Another known issue", "Slow API", []*component.Component{comp}, event.CriticalityDegraded),
+	} {
+		if err := storage.CreateIncident(context.Background(), incident); err != nil {
+			t.Fatalf("CreateIncident failed: %v", err)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	handler.HandleIncidents(rr, httptest.NewRequest(http.MethodGet, "/api/v1/incidents?type=known_issue&limit=1", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+	var incidents []*event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incidents); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(incidents) != 1 || !incidents[0].Perpetual {
+		t.Errorf("Expected a single known issue, got %d incidents", len(incidents))
+	}
+	cursor := rr.Header().Get(nextCursorHeader)
+	if cursor == "" {
+		t.Fatal("Expected a next cursor header")
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleIncidents(rr, httptest.NewRequest(http.MethodGet, "/api/v1/incidents?type=known_issue&limit=1&cursor="+cursor, nil))
+	if err := json.Unmarshal(rr.Body.Bytes(), &incidents); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(incidents) != 1 || rr.Header().Get(nextCursorHeader) != "" {
+		t.Errorf("Expected the last known issue without a next cursor, got %d incidents", len(incidents))
+	}
+
+	rr = httptest.NewRecorder()
+	handler.HandleIncidents(rr, httptest.NewRequest(http.MethodGet, "/api/v1/incidents?sort=sideways", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid sort, got %d", rr.Code)
+	}
+}
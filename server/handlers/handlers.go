@@ -8,17 +8,24 @@ import (
 // Handlers holds all the application handlers
 type Handlers struct {
 	API                *APIHandler
+	Admin              *AdminHandler
+	History            *HistoryHandler
 	Incident           *IncidentHandler
 	PlannedMaintenance *PlannedMaintenanceHandler
+	Trash              *TrashHandler
 	Weather            *WeatherHandler
 }
 
-// New creates a new handlers instance with all sub-handlers
-func New(storage drivers.EventStorage, config *config.Config) *Handlers {
+// New creates a new handlers instance with all sub-handlers.
+// The archive storage holds the events removed by the retention policy, it may be nil.
+func New(storage, archive drivers.EventStorage, config *config.Config) *Handlers {
 	return &Handlers{
 		API:                NewAPIHandler(storage, config),
+		Admin:              NewAdminHandler(storage),
+		History:            NewHistoryHandler(archive),
 		Incident:           NewIncidentHandler(storage),
 		PlannedMaintenance: NewPlannedMaintenanceHandler(storage),
+		Trash:              NewTrashHandler(storage, config.Storage.Trash.PurgeAfter),
 		Weather:            NewWeatherHandler(config, storage),
 	}
 }
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// HistoryHandler serves the events archived by the retention policy, read-only
+type HistoryHandler struct {
+	archive drivers.EventStorage
+}
+
+// NewHistoryHandler creates a new history handler, an empty history is served when archive is nil
+func NewHistoryHandler(archive drivers.EventStorage) *HistoryHandler {
+	return &HistoryHandler{archive: archive}
+}
+
+// writeJSON writes a JSON response
+func (h *HistoryHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// writeError writes an error response
+func (h *HistoryHandler) writeError(w http.ResponseWriter, status int, message string) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
+
+// HandleIncidents handles /history/incidents endpoint, accepting the query parameters of the incident listing
+func (h *HistoryHandler) HandleIncidents(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("HistoryHandler")
+
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).Warn("Method not allowed for incident history endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	filter, filterErrors := parseEventFilter(r, incidentListParams)
+	if len(filterErrors) > 0 {
+		log.WithField("validation_errors", filterErrors).Warn("Invalid incident history parameters")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Invalid query parameters",
+			"validation_errors": filterErrors,
+		})
+		return
+	}
+
+	if h.archive == nil {
+		h.writeJSON(w, http.StatusOK, []*event.Incident{})
+		return
+	}
+	page, err := h.archive.ListIncidents(r.Context(), filter)
+	if err != nil {
+		log.WithError(err).Error("Failed to retrieve archived incidents")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve archived incidents")
+		return
+	}
+
+	if page.NextCursor != "" {
+		w.Header().Set(nextCursorHeader, page.NextCursor)
+	}
+	log.WithField("count", len(page.Items)).Info("Retrieved archived incidents successfully")
+	h.writeJSON(w, http.StatusOK, visibleIncidents(r, page.Items))
+}
+
+// HandleIncidentByID handles /history/incidents/{id} endpoint
+func (h *HistoryHandler) HandleIncidentByID(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("HistoryHandler")
+
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).Warn("Method not allowed for archived incident endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.PathValue("id")
+	if h.archive == nil {
+		h.writeError(w, http.StatusNotFound, "Archived incident not found")
+		return
+	}
+	incident, err := h.archive.GetIncident(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("incident_id", id).Warn("Archived incident not found")
+			h.writeError(w, http.StatusNotFound, "Archived incident not found")
+			return
+		}
+		log.WithError(err).WithField("incident_id", id).Error("Failed to retrieve archived incident")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve archived incident")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, visibleIncident(r, incident))
+}
+
+// HandlePlannedMaintenances handles /history/planned-maintenances endpoint,
+// accepting the query parameters of the planned maintenance listing
+func (h *HistoryHandler) HandlePlannedMaintenances(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("HistoryHandler")
+
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).Warn("Method not allowed for planned maintenance history endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	filter, filterErrors := parseEventFilter(r, plannedMaintenanceListParams)
+	if len(filterErrors) > 0 {
+		log.WithField("validation_errors", filterErrors).Warn("Invalid planned maintenance history parameters")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Invalid query parameters",
+			"validation_errors": filterErrors,
+		})
+		return
+	}
+
+	if h.archive == nil {
+		h.writeJSON(w, http.StatusOK, []*event.PlannedMaintenance{})
+		return
+	}
+	page, err := h.archive.ListPlannedMaintenances(r.Context(), filter)
+	if err != nil {
+		log.WithError(err).Error("Failed to retrieve archived planned maintenances")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve archived planned maintenances")
+		return
+	}
+
+	if page.NextCursor != "" {
+		w.Header().Set(nextCursorHeader, page.NextCursor)
+	}
+	log.WithField("count", len(page.Items)).Info("Retrieved archived planned maintenances successfully")
+	h.writeJSON(w, http.StatusOK, page.Items)
+}
+
+// HandlePlannedMaintenanceByID handles /history/planned-maintenances/{id} endpoint
+func (h *HistoryHandler) HandlePlannedMaintenanceByID(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("HistoryHandler")
+
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).Warn("Method not allowed for archived planned maintenance endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.PathValue("id")
+	if h.archive == nil {
+		h.writeError(w, http.StatusNotFound, "Archived planned maintenance not found")
+		return
+	}
+	pm, err := h.archive.GetPlannedMaintenance(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("maintenance_id", id).Warn("Archived planned maintenance not found")
+			h.writeError(w, http.StatusNotFound, "Archived planned maintenance not found")
+			return
+		}
+		log.WithError(err).WithField("maintenance_id", id).Error("Failed to retrieve archived planned maintenance")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve archived planned maintenance")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, pm)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+func TestHistoryHandler(t *testing.T) {
+	archive := drivers.NewRAMStorage()
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := archive.CreateIncident(context.Background(), incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+
+	history := NewHistoryHandler(archive)
+	rr := httptest.NewRecorder()
+	history.HandleIncidents(rr, httptest.NewRequest(http.MethodGet, "/api/v1/history/incidents?type=firing", nil))
+	var incidents []*event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incidents); err != nil || rr.Code != http.StatusOK || len(incidents) != 1 {
+		t.Fatalf("Expected 200 with the archived incident, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	history.HandleIncidents(rr, httptest.NewRequest(http.MethodGet, "/api/v1/history/incidents?status=sleeping", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid status, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/history/incidents/"+incident.GUID, nil)
+	request.SetPathValue("id", incident.GUID)
+	history.HandleIncidentByID(rr, request)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected 200 for an archived incident, got %d", rr.Code)
+	}
+
+	// Without an archive the history is empty
+	rr = httptest.NewRecorder()
+	NewHistoryHandler(nil).HandlePlannedMaintenances(rr, httptest.NewRequest(http.MethodGet, "/api/v1/history/planned-maintenances", nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != "[]\n" {
+		t.Errorf("Expected an empty history, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
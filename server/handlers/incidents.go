@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/gmllt/clariti/logger"
 	"github.com/gmllt/clariti/models/component"
@@ -25,8 +29,28 @@ type IncidentRequest struct {
 	Components  []string `json:"components"`            // Component codes as strings
 	Criticality string   `json:"criticality,omitempty"` // Criticality as string
 	Perpetual   bool     `json:"perpetual,omitempty"`
+
+	// Labels such as team=payments, selectable by the listings and the weather
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Criticality as string by component code, for the components impacted differently than the incident
+	ComponentCriticalities map[string]string `json:"component_criticalities,omitempty"`
+
+	// Lifecycle status and public message a new incident opens with, investigating and its content by default.
+	// Ignored by updates, the lifecycle only changes through transitions.
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// TransitionRequest represents the JSON structure moving an incident to a lifecycle status
+type TransitionRequest struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
 }
 
+// maxModifyAttempts bounds the retries of a read-modify-write racing with other writes
+const maxModifyAttempts = 3
+
 // parseCriticality converts string criticality to event.Criticality
 func parseCriticality(criticalityStr string) (event.Criticality, error) {
 	criticality := event.ParseCriticality(criticalityStr)
@@ -36,6 +60,11 @@ func parseCriticality(criticalityStr string) (event.Criticality, error) {
 	return criticality, nil
 }
 
+// parseLifecycleStatus normalizes a lifecycle status given by a client
+func parseLifecycleStatus(status string) event.Status {
+	return event.Status(strings.ToLower(strings.TrimSpace(status)))
+}
+
 // ToIncident converts IncidentRequest to event.Incident by resolving component codes
 func (req *IncidentRequest) ToIncident() (*event.Incident, error) {
 	// Convert component codes to Component objects
@@ -57,16 +86,29 @@ func (req *IncidentRequest) ToIncident() (*event.Incident, error) {
 	if err != nil {
 		return nil, err
 	}
+	var componentCriticalities map[string]event.Criticality
+	for code, value := range req.ComponentCriticalities {
+		componentCriticality, err := parseCriticality(value)
+		if err != nil {
+			return nil, err
+		}
+		if componentCriticalities == nil {
+			componentCriticalities = make(map[string]event.Criticality, len(req.ComponentCriticalities))
+		}
+		componentCriticalities[code] = componentCriticality
+	}
 
 	return &event.Incident{
 		BaseEvent: event.BaseEvent{
 			GUID:       req.GUID,
 			Title:      req.Title,
 			Content:    req.Content,
+			Labels:     req.Labels,
 			Components: components,
 		},
-		IncidentCriticality: criticality,
-		Perpetual:           req.Perpetual,
+		IncidentCriticality:    criticality,
+		ComponentCriticalities: componentCriticalities,
+		Perpetual:              req.Perpetual,
 	}, nil
 }
 
@@ -120,6 +162,26 @@ func (h *IncidentHandler) validateIncidentRequest(req *IncidentRequest) []string
 		}
 	}
 
+	// Validate per-component criticalities, only for components of the incident, in a stable order
+	codes := make([]string, 0, len(req.ComponentCriticalities))
+	for code := range req.ComponentCriticalities {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		value := req.ComponentCriticalities[code]
+		if !containsString(req.Components, code) {
+			errors = append(errors, fmt.Sprintf("component_criticalities: '%s' is not one of the incident components", code))
+		}
+		if strings.TrimSpace(value) == "" {
+			errors = append(errors, fmt.Sprintf("component_criticalities: criticality of '%s' cannot be empty", code))
+		} else if _, err := parseCriticality(value); err != nil {
+			errors = append(errors, fmt.Sprintf("component_criticalities: %s", err.Error()))
+		}
+	}
+
+	errors = append(errors, validateLabels(req.Labels)...)
+
 	return errors
 }
 
@@ -202,20 +264,33 @@ func (h *IncidentHandler) HandleIncidentByID(w http.ResponseWriter, r *http.Requ
 	}
 }
 
-// getAllIncidents returns all incidents
+// getAllIncidents returns the incidents matching the listing query parameters
 func (h *IncidentHandler) getAllIncidents(w http.ResponseWriter, r *http.Request) {
 	log := logger.GetDefault().WithComponent("IncidentHandler")
 	log.Info("Getting all incidents")
 
-	incidents, err := h.storage.GetAllIncidents()
+	filter, filterErrors := parseEventFilter(r, incidentListParams)
+	if len(filterErrors) > 0 {
+		log.WithField("validation_errors", filterErrors).Warn("Invalid incident listing parameters")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Invalid query parameters",
+			"validation_errors": filterErrors,
+		})
+		return
+	}
+
+	page, err := h.storage.ListIncidents(r.Context(), filter)
 	if err != nil {
 		log.WithError(err).Error("Failed to retrieve incidents from storage")
 		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
 		return
 	}
 
-	log.WithField("count", len(incidents)).Info("Retrieved incidents successfully")
-	h.writeJSON(w, http.StatusOK, incidents)
+	if page.NextCursor != "" {
+		w.Header().Set(nextCursorHeader, page.NextCursor)
+	}
+	log.WithField("count", len(page.Items)).Info("Retrieved incidents successfully")
+	h.writeJSON(w, http.StatusOK, visibleIncidents(r, page.Items))
 }
 
 // getIncident returns a specific incident
@@ -223,7 +298,7 @@ func (h *IncidentHandler) getIncident(w http.ResponseWriter, r *http.Request, id
 	log := logger.GetDefault().WithComponent("IncidentHandler")
 	log.WithField("incident_id", id).Debug("Getting incident")
 
-	incident, err := h.storage.GetIncident(id)
+	incident, err := h.storage.GetIncident(r.Context(), id)
 	if err != nil {
 		if err == drivers.ErrNotFound {
 			log.WithField("incident_id", id).Warn("Incident not found")
@@ -236,7 +311,8 @@ func (h *IncidentHandler) getIncident(w http.ResponseWriter, r *http.Request, id
 	}
 
 	log.WithField("incident_id", id).Info("Incident retrieved successfully")
-	h.writeJSON(w, http.StatusOK, incident)
+	w.Header().Set("ETag", formatETag(incident.Version))
+	h.writeJSON(w, http.StatusOK, visibleIncident(r, incident))
 }
 
 // createIncident creates a new incident
@@ -254,8 +330,12 @@ func (h *IncidentHandler) createIncident(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Validate incident request data
-	if validationErrors := h.validateIncidentRequest(&req); len(validationErrors) > 0 {
+	// Validate incident request data, an incident opens in any lifecycle status but resolved
+	validationErrors := h.validateIncidentRequest(&req)
+	if status := parseLifecycleStatus(req.Status); status != "" && (!event.CanTransition("", status) || status == event.StatusResolved) {
+		validationErrors = append(validationErrors, fmt.Sprintf("invalid status '%s'. Valid values: investigating, identified, monitoring", req.Status))
+	}
+	if len(validationErrors) > 0 {
 		log.WithField("validation_errors", validationErrors).Warn("Incident validation failed")
 		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
 			"error":             "Validation failed",
@@ -279,12 +359,28 @@ func (h *IncidentHandler) createIncident(w http.ResponseWriter, r *http.Request)
 	if incident.GUID == "" {
 		// Create new incident with GUID
 		newIncident := event.NewIncident(incident.Title, incident.Content, incident.Components, incident.IncidentCriticality, incident.Perpetual)
+		newIncident.ComponentCriticalities = incident.ComponentCriticalities
+		newIncident.Labels = incident.Labels
 		incident = newIncident
 		log.WithField("guid", incident.GUID).Debug("Generated new incident GUID")
 	}
 
+	// Open the lifecycle of the incident
+	status, message := parseLifecycleStatus(req.Status), req.Message
+	if status == "" {
+		status = event.StatusInvestigating
+	}
+	if strings.TrimSpace(message) == "" {
+		message = incident.Content
+	}
+	if err := incident.Transition(status, message, drivers.AuthorFromContext(r.Context()), time.Now()); err != nil {
+		log.WithError(err).Warn("Failed to open the incident lifecycle")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	log.WithField("incident_id", incident.GUID).Info("Storing incident")
-	if err := h.storage.CreateIncident(incident); err != nil {
+	if err := h.storage.CreateIncident(r.Context(), incident); err != nil {
 		if err == drivers.ErrExists {
 			log.WithField("incident_id", incident.GUID).Warn("Incident already exists")
 			h.writeError(w, http.StatusConflict, "Incident already exists")
@@ -299,6 +395,7 @@ func (h *IncidentHandler) createIncident(w http.ResponseWriter, r *http.Request)
 	}
 
 	log.WithField("incident_id", incident.GUID).Info("Incident created successfully")
+	w.Header().Set("ETag", formatETag(incident.Version))
 	h.writeJSON(w, http.StatusCreated, incident)
 }
 
@@ -342,12 +439,37 @@ func (h *IncidentHandler) updateIncident(w http.ResponseWriter, r *http.Request,
 	// Ensure the ID in the URL matches the incident GUID
 	incident.GUID = id
 
-	if err := h.storage.UpdateIncident(incident); err != nil {
+	// Only overwrite the version the client has seen when it sent If-Match
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("incident_id", id).Warn("Invalid If-Match header in incident update")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The lifecycle, the effective times it manages, the acknowledgement, the updates and the postmortem are kept from the stored incident
+	incident, err = h.modifyIncident(r.Context(), id, expected, func(stored *event.Incident) error {
+		incident.LifecycleStatus, incident.Transitions = stored.LifecycleStatus, stored.Transitions
+		incident.AcknowledgedAt, incident.AcknowledgedBy = stored.AcknowledgedAt, stored.AcknowledgedBy
+		incident.Updates, incident.Postmortem = stored.Updates, stored.Postmortem
+		if stored.LifecycleStatus != "" {
+			incident.StartEffective, incident.EndEffective = stored.StartEffective, stored.EndEffective
+		}
+		incident.Version = stored.Version
+		*stored = *incident
+		return nil
+	})
+	if err != nil {
 		if err == drivers.ErrNotFound {
 			log.WithField("incident_id", id).Warn("Incident not found for update")
 			h.writeError(w, http.StatusNotFound, "Incident not found")
 			return
 		}
+		if err == drivers.ErrVersionConflict {
+			log.WithField("incident_id", id).WithField("if_match", expected).Warn("Incident was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Incident was modified since it was retrieved")
+			return
+		}
 		log.WithError(err).WithField("incident_id", id).Error("Failed to update incident in storage")
 		h.writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
 			"error":   "Failed to update incident",
@@ -357,6 +479,7 @@ func (h *IncidentHandler) updateIncident(w http.ResponseWriter, r *http.Request,
 	}
 
 	log.WithField("incident_id", id).Info("Incident updated successfully")
+	w.Header().Set("ETag", formatETag(incident.Version))
 	h.writeJSON(w, http.StatusOK, incident)
 }
 
@@ -365,7 +488,7 @@ func (h *IncidentHandler) deleteIncident(w http.ResponseWriter, r *http.Request,
 	log := logger.GetDefault().WithComponent("IncidentHandler")
 	log.WithField("incident_id", id).Info("Deleting incident")
 
-	if err := h.storage.DeleteIncident(id); err != nil {
+	if err := h.storage.DeleteIncident(r.Context(), id); err != nil {
 		if err == drivers.ErrNotFound {
 			log.WithField("incident_id", id).Warn("Incident not found for deletion")
 			h.writeError(w, http.StatusNotFound, "Incident not found")
@@ -379,3 +502,405 @@ func (h *IncidentHandler) deleteIncident(w http.ResponseWriter, r *http.Request,
 	log.WithField("incident_id", id).Info("Incident deleted successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// HandleIncidentRevisions handles /incidents/{id}/revisions endpoint
+func (h *IncidentHandler) HandleIncidentRevisions(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing incident ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing incident ID")
+		return
+	}
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).WithField("incident_id", id).Warn("Method not allowed for incident revisions endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	revisions, err := h.storage.GetIncidentRevisions(r.Context(), id)
+	if err != nil {
+		log.WithError(err).WithField("incident_id", id).Error("Failed to retrieve incident revisions from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incident revisions")
+		return
+	}
+
+	// Events created before revisions were recorded have no history yet, only unknown IDs are not found
+	if len(revisions) == 0 {
+		if _, err := h.storage.GetIncident(r.Context(), id); err != nil {
+			if err == drivers.ErrNotFound {
+				log.WithField("incident_id", id).Warn("Incident not found")
+				h.writeError(w, http.StatusNotFound, "Incident not found")
+				return
+			}
+			log.WithError(err).WithField("incident_id", id).Error("Failed to retrieve incident from storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incident")
+			return
+		}
+	}
+
+	log.WithField("incident_id", id).WithField("count", len(revisions)).Info("Retrieved incident revisions successfully")
+	h.writeJSON(w, http.StatusOK, visibleRevisions(r, revisions))
+}
+
+// HandleIncidentRestore handles /incidents/{id}/restore endpoint
+func (h *IncidentHandler) HandleIncidentRestore(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing incident ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing incident ID")
+		return
+	}
+	if r.Method != http.MethodPost {
+		log.WithField("method", r.Method).WithField("incident_id", id).Warn("Method not allowed for incident restore endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	incident, err := h.storage.RestoreIncident(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("incident_id", id).Warn("Incident not found in trash")
+			h.writeError(w, http.StatusNotFound, "Incident not found in trash")
+			return
+		}
+		log.WithError(err).WithField("incident_id", id).Error("Failed to restore incident in storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to restore incident")
+		return
+	}
+
+	log.WithField("incident_id", id).Info("Incident restored successfully")
+	w.Header().Set("ETag", formatETag(incident.Version))
+	h.writeJSON(w, http.StatusOK, incident)
+}
+
+// modifyIncident applies change to a copy of the stored incident and writes it back.
+// A write racing with another one is retried, unless the client sent If-Match: the stored version must then
+// be one of the expected ones, otherwise ErrVersionConflict is returned.
+func (h *IncidentHandler) modifyIncident(ctx context.Context, id string, expected []int64, change func(*event.Incident) error) (*event.Incident, error) {
+	for attempt := 1; ; attempt++ {
+		stored, err := h.storage.GetIncident(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesVersion(expected, stored.Version) {
+			return nil, drivers.ErrVersionConflict
+		}
+
+		incident := *stored
+		if err := change(&incident); err != nil {
+			return nil, err
+		}
+		incident.Version = stored.Version
+		err = h.storage.UpdateIncident(ctx, &incident)
+		if err == drivers.ErrVersionConflict && len(expected) == 0 && attempt < maxModifyAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &incident, nil
+	}
+}
+
+// HandleIncidentTransitions handles /incidents/{id}/transitions endpoint: GET lists the lifecycle timeline,
+// POST moves the incident to another lifecycle status with a public message
+func (h *IncidentHandler) HandleIncidentTransitions(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing incident ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing incident ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getIncidentTransitions(w, r, id)
+	case http.MethodPost:
+		h.transitionIncident(w, r, id)
+	default:
+		log.WithField("method", r.Method).WithField("incident_id", id).Warn("Method not allowed for incident transitions endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getIncidentTransitions returns the lifecycle timeline of an incident, oldest first
+func (h *IncidentHandler) getIncidentTransitions(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	incident, err := h.storage.GetIncident(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("incident_id", id).Warn("Incident not found")
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+			return
+		}
+		log.WithError(err).WithField("incident_id", id).Error("Failed to retrieve incident from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incident")
+		return
+	}
+
+	transitions := incident.Transitions
+	if transitions == nil {
+		transitions = []event.StatusTransition{}
+	}
+	h.writeJSON(w, http.StatusOK, transitions)
+}
+
+// transitionIncident moves an incident to another lifecycle status
+func (h *IncidentHandler) transitionIncident(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	var req TransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.WithError(err).Warn("Failed to decode incident transition JSON request")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var validationErrors []string
+	status := parseLifecycleStatus(req.Status)
+	if !containsStatus(event.LifecycleStatuses(), status) {
+		validationErrors = append(validationErrors, fmt.Sprintf("invalid status '%s'. Valid values: %s", req.Status, joinValues(event.LifecycleStatuses())))
+	}
+	if strings.TrimSpace(req.Message) == "" {
+		validationErrors = append(validationErrors, "field 'message' is required and cannot be empty")
+	}
+	if len(validationErrors) > 0 {
+		log.WithField("validation_errors", validationErrors).WithField("incident_id", id).Warn("Incident transition validation failed")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": validationErrors,
+		})
+		return
+	}
+
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("incident_id", id).Warn("Invalid If-Match header in incident transition")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	author := drivers.AuthorFromContext(r.Context())
+	incident, err := h.modifyIncident(r.Context(), id, expected, func(incident *event.Incident) error {
+		return incident.Transition(status, req.Message, author, time.Now())
+	})
+	if err != nil {
+		switch {
+		case err == drivers.ErrNotFound:
+			log.WithField("incident_id", id).Warn("Incident not found for transition")
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+		case err == drivers.ErrVersionConflict:
+			log.WithField("incident_id", id).WithField("if_match", expected).Warn("Incident was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Incident was modified since it was retrieved")
+		case errors.Is(err, event.ErrInvalidTransition):
+			log.WithError(err).WithField("incident_id", id).Warn("Invalid incident transition")
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.WithError(err).WithField("incident_id", id).Error("Failed to update incident in storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to update incident")
+		}
+		return
+	}
+
+	log.WithField("incident_id", id).WithField("status", status).Info("Incident transitioned successfully")
+	w.Header().Set("ETag", formatETag(incident.Version))
+	h.writeJSON(w, http.StatusOK, incident)
+}
+
+// HandleIncidentUpdates handles /incidents/{id}/updates endpoint: GET lists the updates posted on the incident,
+// POST adds one
+func (h *IncidentHandler) HandleIncidentUpdates(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing incident ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing incident ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getIncidentUpdates(w, r, id)
+	case http.MethodPost:
+		h.postIncidentUpdate(w, r, id)
+	default:
+		log.WithField("method", r.Method).WithField("incident_id", id).Warn("Method not allowed for incident updates endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getIncidentUpdates returns the updates of an incident, oldest first
+func (h *IncidentHandler) getIncidentUpdates(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	incident, err := h.storage.GetIncident(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("incident_id", id).Warn("Incident not found")
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+			return
+		}
+		log.WithError(err).WithField("incident_id", id).Error("Failed to retrieve incident from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incident")
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(incident.Version))
+	h.writeJSON(w, http.StatusOK, eventUpdates(&incident.BaseEvent))
+}
+
+// postIncidentUpdate adds an update to an incident
+func (h *IncidentHandler) postIncidentUpdate(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	var req EventUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.WithError(err).Warn("Failed to decode incident update JSON request")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if validationErrors := req.validate(); len(validationErrors) > 0 {
+		log.WithField("validation_errors", validationErrors).WithField("incident_id", id).Warn("Incident update post validation failed")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": validationErrors,
+		})
+		return
+	}
+
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("incident_id", id).Warn("Invalid If-Match header in incident update post")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var update event.Update
+	author := drivers.AuthorFromContext(r.Context())
+	incident, err := h.modifyIncident(r.Context(), id, expected, func(incident *event.Incident) error {
+		update = incident.PostUpdate(req.Message, author, time.Now())
+		return nil
+	})
+	if err != nil {
+		switch err {
+		case drivers.ErrNotFound:
+			log.WithField("incident_id", id).Warn("Incident not found for update post")
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+		case drivers.ErrVersionConflict:
+			log.WithField("incident_id", id).WithField("if_match", expected).Warn("Incident was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Incident was modified since it was retrieved")
+		default:
+			log.WithError(err).WithField("incident_id", id).Error("Failed to update incident in storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to post incident update")
+		}
+		return
+	}
+
+	log.WithField("incident_id", id).WithField("update_id", update.GUID).Info("Incident update posted successfully")
+	w.Header().Set("ETag", formatETag(incident.Version))
+	h.writeJSON(w, http.StatusCreated, update)
+}
+
+// HandleIncidentAcknowledge handles /incidents/{id}/acknowledge endpoint, recording who took charge of an open incident
+func (h *IncidentHandler) HandleIncidentAcknowledge(w http.ResponseWriter, r *http.Request) {
+	h.applyIncidentAction(w, r, "acknowledge", func(incident *event.Incident, message, author string, at time.Time) error {
+		return incident.Acknowledge(author, at)
+	})
+}
+
+// HandleIncidentResolve handles /incidents/{id}/resolve endpoint, moving an open incident to resolved
+func (h *IncidentHandler) HandleIncidentResolve(w http.ResponseWriter, r *http.Request) {
+	h.applyIncidentAction(w, r, "resolve", func(incident *event.Incident, message, author string, at time.Time) error {
+		if message == "" {
+			message = "This incident has been resolved"
+		}
+		return incident.Transition(event.StatusResolved, message, author, at)
+	})
+}
+
+// HandleIncidentReopen handles /incidents/{id}/reopen endpoint, moving a resolved incident back to investigating
+func (h *IncidentHandler) HandleIncidentReopen(w http.ResponseWriter, r *http.Request) {
+	h.applyIncidentAction(w, r, "reopen", func(incident *event.Incident, message, author string, at time.Time) error {
+		if message == "" {
+			message = "This incident has been reopened"
+		}
+		return incident.Reopen(message, author, at)
+	})
+}
+
+// applyIncidentAction applies an action to an incident on behalf of the request author.
+// The optional message of the request is passed to apply, actions which cannot apply are rejected with 409 Conflict.
+func (h *IncidentHandler) applyIncidentAction(w http.ResponseWriter, r *http.Request, action string,
+	apply func(incident *event.Incident, message, author string, at time.Time) error) {
+	log := logger.GetDefault().WithComponent("IncidentHandler").WithField("action", action)
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing incident ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing incident ID")
+		return
+	}
+	if r.Method != http.MethodPost {
+		log.WithField("method", r.Method).WithField("incident_id", id).Warn("Method not allowed for incident action endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req, err := decodeActionRequest(r)
+	if err != nil {
+		log.WithError(err).Warn("Failed to decode incident action JSON request")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("incident_id", id).Warn("Invalid If-Match header in incident action")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	author := drivers.AuthorFromContext(r.Context())
+	message := strings.TrimSpace(req.Message)
+	incident, err := h.modifyIncident(r.Context(), id, expected, func(incident *event.Incident) error {
+		return apply(incident, message, author, time.Now())
+	})
+	if err != nil {
+		switch {
+		case err == drivers.ErrNotFound:
+			log.WithField("incident_id", id).Warn("Incident not found for action")
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+		case err == drivers.ErrVersionConflict:
+			log.WithField("incident_id", id).WithField("if_match", expected).Warn("Incident was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Incident was modified since it was retrieved")
+		case errors.Is(err, event.ErrInvalidTransition):
+			log.WithError(err).WithField("incident_id", id).Warn("Incident action not allowed")
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.WithError(err).WithField("incident_id", id).Error("Failed to update incident in storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to update incident")
+		}
+		return
+	}
+
+	log.WithField("incident_id", id).WithField("author", author).Info("Incident action applied successfully")
+	w.Header().Set("ETag", formatETag(incident.Version))
+	h.writeJSON(w, http.StatusOK, incident)
+}
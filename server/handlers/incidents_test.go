@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// serveIncident sends a request as alice to an event handler, setting the id path value when not empty
+func serveIncident(handler http.HandlerFunc, method, target, id, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request = request.WithContext(drivers.WithAuthor(request.Context(), "alice"))
+	if id != "" {
+		request.SetPathValue("id", id)
+	}
+	rr := httptest.NewRecorder()
+	handler(rr, request)
+	return rr
+}
+
+func TestIncidentHandler_Lifecycle(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewIncidentHandler(storage)
+
+	rr := serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"API outage","content":"Requests time out","components":["api"],"criticality":"major outage"}`)
+	var incident event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if incident.LifecycleStatus != event.StatusInvestigating || len(incident.Transitions) != 1 ||
+		incident.Transitions[0].Message != "Requests time out" || incident.Transitions[0].Author != "alice" {
+		t.Fatalf("Expected a new incident investigating with its content as message, got %+v", incident.Transitions)
+	}
+	if incident.StartEffective == nil {
+		t.Errorf("Expected a new incident to start when its lifecycle opens")
+	}
+
+	transitions := "/api/v1/incidents/" + incident.GUID + "/transitions"
+	rr = serveIncident(h.HandleIncidentTransitions, http.MethodPost, transitions, incident.GUID,
+		`{"status":"identified","message":"A bad deploy, rolling back"}`)
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"2"` {
+		t.Fatalf("Expected 200 at version 2, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidentTransitions, http.MethodPost, transitions, incident.GUID, `{"status":"identified","message":"Again"}`)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a transition to the current status, got %d", rr.Code)
+	}
+	rr = serveIncident(h.HandleIncidentTransitions, http.MethodPost, transitions, incident.GUID, `{"status":"sleeping"}`)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "message") {
+		t.Errorf("Expected 400 for an unknown status without message, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Updates keep the lifecycle
+	rr = serveIncident(h.HandleIncidentByID, http.MethodPut, "/api/v1/incidents/"+incident.GUID, incident.GUID,
+		`{"title":"API outage","content":"Requests to the EU API time out","components":["api"],"criticality":"major outage","status":"resolved"}`)
+	var updated event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &updated); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if updated.LifecycleStatus != event.StatusIdentified || len(updated.Transitions) != 2 || updated.StartEffective == nil {
+		t.Errorf("Expected the update to keep the lifecycle, got %s with %d transitions", updated.LifecycleStatus, len(updated.Transitions))
+	}
+
+	rr = serveIncident(h.HandleIncidentTransitions, http.MethodPost, transitions, incident.GUID,
+		`{"status":"resolved","message":"Rolled back"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidentTransitions, http.MethodGet, transitions, incident.GUID, "")
+	var timeline []event.StatusTransition
+	if err := json.Unmarshal(rr.Body.Bytes(), &timeline); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(timeline) != 3 || timeline[2].From != event.StatusIdentified || timeline[2].To != event.StatusResolved {
+		t.Errorf("Expected the full timeline, got %+v", timeline)
+	}
+
+	// The status filter sees the lifecycle
+	rr = serveIncident(h.HandleIncidents, http.MethodGet, "/api/v1/incidents?status=resolved", "", "")
+	var resolved []*event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &resolved); err != nil || len(resolved) != 1 {
+		t.Errorf("Expected the resolved incident listed, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidentTransitions, http.MethodPost, "/api/v1/incidents/unknown/transitions", "unknown",
+		`{"status":"resolved","message":"Fixed"}`)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown incident, got %d", rr.Code)
+	}
+}
+
+func TestIncidentHandler_CreateWithStatus(t *testing.T) {
+	h := NewIncidentHandler(drivers.NewRAMStorage())
+
+	rr := serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"Cache degraded","content":"Evictions spike","components":["cache"],"status":"Identified","message":"A node is down"}`)
+	var incident event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if incident.LifecycleStatus != event.StatusIdentified || incident.Transitions[0].Message != "A node is down" {
+		t.Errorf("Expected the requested initial status and message, got %+v", incident.Transitions)
+	}
+
+	rr = serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"Cache degraded","content":"Evictions spike","components":["cache"],"status":"resolved"}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an incident created resolved, got %d", rr.Code)
+	}
+}
+
+func TestIncidentHandler_Updates(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewIncidentHandler(storage)
+
+	rr := serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"API outage","content":"Requests time out","components":["api"]}`)
+	var incident event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	updates := "/api/v1/incidents/" + incident.GUID + "/updates"
+	rr = serveIncident(h.HandleIncidentUpdates, http.MethodGet, updates, incident.GUID, "")
+	if rr.Code != http.StatusOK || strings.TrimSpace(rr.Body.String()) != "[]" {
+		t.Errorf("Expected an empty list before any update, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	for _, message := range []string{"Cause identified", "Fix deployed"} {
+		rr = serveIncident(h.HandleIncidentUpdates, http.MethodPost, updates, incident.GUID, `{"message":"`+message+`"}`)
+		var update event.Update
+		if err := json.Unmarshal(rr.Body.Bytes(), &update); err != nil || rr.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		if update.GUID == "" || update.Message != message || update.Author != "alice" || update.At.IsZero() {
+			t.Errorf("Unexpected update %+v", update)
+		}
+	}
+	rr = serveIncident(h.HandleIncidentUpdates, http.MethodPost, updates, incident.GUID, `{"message":" "}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an empty message, got %d", rr.Code)
+	}
+	rr = serveIncident(h.HandleIncidentUpdates, http.MethodPost, "/api/v1/incidents/unknown/updates", "unknown", `{"message":"Hello"}`)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown incident, got %d", rr.Code)
+	}
+
+	// Editing the incident keeps its updates
+	rr = serveIncident(h.HandleIncidentByID, http.MethodPut, "/api/v1/incidents/"+incident.GUID, incident.GUID,
+		`{"title":"API outage","content":"Requests to the EU API time out","components":["api"]}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidentUpdates, http.MethodGet, updates, incident.GUID, "")
+	var listed []event.Update
+	if err := json.Unmarshal(rr.Body.Bytes(), &listed); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(listed) != 2 || listed[0].Message != "Cause identified" || listed[1].Message != "Fix deployed" {
+		t.Errorf("Expected both updates oldest first, got %+v", listed)
+	}
+	if rr.Header().Get("ETag") != `"4"` {
+		t.Errorf("Expected the listing to carry the incident version 4, got %s", rr.Header().Get("ETag"))
+	}
+}
+
+func TestIncidentHandler_Actions(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewIncidentHandler(storage)
+
+	rr := serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"API outage","content":"Requests time out","components":["api"]}`)
+	var incident event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	path := "/api/v1/incidents/" + incident.GUID
+
+	rr = serveIncident(h.HandleIncidentAcknowledge, http.MethodPost, path+"/acknowledge", incident.GUID, "")
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if incident.Status() != event.StatusAcknowledged || incident.AcknowledgedBy != "alice" || incident.AcknowledgedAt == nil {
+		t.Errorf("Expected the incident acknowledged by alice, got %s by %q", incident.Status(), incident.AcknowledgedBy)
+	}
+	rr = serveIncident(h.HandleIncidentAcknowledge, http.MethodPost, path+"/acknowledge", incident.GUID, "")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a second acknowledgement, got %d", rr.Code)
+	}
+	rr = serveIncident(h.HandleIncidentReopen, http.MethodPost, path+"/reopen", incident.GUID, "")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 when reopening an open incident, got %d", rr.Code)
+	}
+
+	rr = serveIncident(h.HandleIncidentResolve, http.MethodPost, path+"/resolve", incident.GUID, `{"message":"Rolled back"}`)
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	last := incident.Transitions[len(incident.Transitions)-1]
+	if incident.Status() != event.StatusResolved || incident.EndEffective == nil || last.Message != "Rolled back" || last.Author != "alice" {
+		t.Errorf("Expected the incident resolved by alice with its message, got %s and %+v", incident.Status(), last)
+	}
+
+	rr = serveIncident(h.HandleIncidentReopen, http.MethodPost, path+"/reopen", incident.GUID, "")
+	var reopened event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &reopened); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if reopened.Status() != event.StatusInvestigating || reopened.AcknowledgedAt != nil || reopened.Transitions[len(reopened.Transitions)-1].Message == "" {
+		t.Errorf("Expected the incident reopened without acknowledgement, got %s", reopened.Status())
+	}
+
+	rr = serveIncident(h.HandleIncidentResolve, http.MethodGet, path+"/resolve", incident.GUID, "")
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rr.Code)
+	}
+	rr = serveIncident(h.HandleIncidentResolve, http.MethodPost, "/api/v1/incidents/unknown/resolve", "unknown", "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rr.Code)
+	}
+}
+
+func TestIncidentHandler_ComponentCriticalities(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewIncidentHandler(storage)
+
+	rr := serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"Outage","content":"Requests fail","components":["api","cache"],"criticality":"degraded",
+		"component_criticalities":{"api":"major outage"}}`)
+	var incident event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if incident.ComponentCriticalities["api"] != event.CriticalityMajorOutage || incident.Criticality() != event.CriticalityMajorOutage {
+		t.Errorf("Expected the API in major outage, got %v", incident.ComponentCriticalities)
+	}
+
+	rr = serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"Outage","content":"Requests fail","components":["api"],"component_criticalities":{"db":"degraded","api":"on fire"}}`)
+	body := rr.Body.String()
+	if rr.Code != http.StatusBadRequest || !strings.Contains(body, "'db' is not one of the incident components") || !strings.Contains(body, "on fire") {
+		t.Errorf("Expected 400 for an unknown component and criticality, got %d: %s", rr.Code, body)
+	}
+}
+
+func TestIncidentHandler_Postmortem(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewIncidentHandler(storage)
+
+	rr := serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"API outage","content":"Requests time out","components":["api"],"criticality":"major outage"}`)
+	var incident event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	path := "/api/v1/incidents/" + incident.GUID + "/postmortem"
+	body := `{"summary":"The API was down","root_cause":"Expired certificate","contributing_factors":["No expiry alert"],` +
+		`"action_items":[{"description":"Alert on certificate expiry","owner":"bob","due_date":"2026-01-31T00:00:00Z"}]}`
+
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodPut, path, incident.GUID, body)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for the postmortem of an open incident, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodGet, path, incident.GUID, "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 without postmortem, got %d", rr.Code)
+	}
+
+	rr = serveIncident(h.HandleIncidentResolve, http.MethodPost, "/api/v1/incidents/"+incident.GUID+"/resolve", incident.GUID, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	missing := func(query string) []event.Incident {
+		rr := serveIncident(h.HandleMissingPostmortems, http.MethodGet, "/api/v1/incidents/missing-postmortems"+query, "", "")
+		var incidents []event.Incident
+		if err := json.Unmarshal(rr.Body.Bytes(), &incidents); err != nil || rr.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		return incidents
+	}
+	if incidents := missing(""); len(incidents) != 1 || incidents[0].GUID != incident.GUID {
+		t.Errorf("Expected the resolved outage to miss a postmortem, got %d incidents", len(incidents))
+	}
+
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodPut, path, incident.GUID, `{"summary":"Draft","action_items":[{"description":"Fix it"}]}`)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "root_cause") || !strings.Contains(rr.Body.String(), "owner") {
+		t.Errorf("Expected 400 without root cause and owner, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodPut, path, incident.GUID, body)
+	var postmortem event.Postmortem
+	if err := json.Unmarshal(rr.Body.Bytes(), &postmortem); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if postmortem.Author != "alice" || len(postmortem.ActionItems) != 1 || postmortem.ActionItems[0].DueDate == nil || postmortem.Published {
+		t.Errorf("Expected an unpublished draft by alice with its action item, got %+v", postmortem)
+	}
+	if incidents := missing(""); len(incidents) != 1 {
+		t.Errorf("Expected a draft not to satisfy the postmortem requirement, got %d incidents", len(incidents))
+	}
+
+	// Anonymous readers do not see drafts
+	anonymous := func(handler http.HandlerFunc, target, id string) string {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		if id != "" {
+			request.SetPathValue("id", id)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, request)
+		return rr.Body.String()
+	}
+	for name, body := range map[string]string{
+		"postmortem": anonymous(h.HandleIncidentPostmortem, path, incident.GUID),
+		"incident":   anonymous(h.HandleIncidentByID, "/api/v1/incidents/"+incident.GUID, incident.GUID),
+		"listing":    anonymous(h.HandleIncidents, "/api/v1/incidents", ""),
+		"missing":    anonymous(h.HandleMissingPostmortems, "/api/v1/incidents/missing-postmortems", ""),
+		"revisions":  anonymous(h.HandleIncidentRevisions, "/api/v1/incidents/"+incident.GUID+"/revisions", incident.GUID),
+	} {
+		if strings.Contains(body, "Expired certificate") {
+			t.Errorf("Expected the %s to hide the draft from anonymous readers, got %s", name, body)
+		}
+	}
+	if body := anonymous(h.HandleIncidentPostmortem, path, incident.GUID); !strings.Contains(body, "Incident has no postmortem") {
+		t.Errorf("Expected anonymous readers not to find the draft, got %s", body)
+	}
+
+	published := strings.Replace(body, `{"summary"`, `{"published":true,"summary"`, 1)
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodPut, path, incident.GUID, published)
+	postmortem = event.Postmortem{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &postmortem); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !postmortem.Published || postmortem.PublishedAt == nil {
+		t.Errorf("Expected a published postmortem, got %+v", postmortem)
+	}
+	if incidents := missing("?criticality=degraded"); len(incidents) != 0 {
+		t.Errorf("Expected no incident missing a postmortem, got %d", len(incidents))
+	}
+	rr = serveIncident(h.HandleMissingPostmortems, http.MethodGet, "/api/v1/incidents/missing-postmortems?criticality=huge", "", "")
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown criticality, got %d", rr.Code)
+	}
+
+	// Updating the incident keeps its postmortem
+	rr = serveIncident(h.HandleIncidentByID, http.MethodPut, "/api/v1/incidents/"+incident.GUID, incident.GUID,
+		`{"title":"API outage","content":"Expired certificate","components":["api"],"criticality":"major outage"}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodGet, path, incident.GUID, "")
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "Expired certificate") {
+		t.Errorf("Expected the postmortem kept by the update, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodDelete, path, incident.GUID, "")
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected 204, got %d", rr.Code)
+	}
+	rr = serveIncident(h.HandleIncidentPostmortem, http.MethodDelete, path, incident.GUID, "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a second deletion, got %d", rr.Code)
+	}
+}
+
+func TestIncidentHandler_Labels(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewIncidentHandler(storage)
+
+	rr := serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"Card payments fail","content":"Timeouts","components":["api"],"labels":{"team":"payments","source":"alertmanager"}}`)
+	var incident event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incident); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if incident.Labels["team"] != "payments" || incident.Labels["source"] != "alertmanager" {
+		t.Errorf("Expected the labels of the request, got %v", incident.Labels)
+	}
+	rr = serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"Slow search","content":"Latency","components":["search"],"labels":{"team":"search"}}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidents, http.MethodPost, "/api/v1/incidents", "",
+		`{"title":"Outage","content":"Down","components":["api"],"labels":{"team":"on call"}}`)
+	if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), "labels") {
+		t.Errorf("Expected 400 for an invalid label value, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = serveIncident(h.HandleIncidents, http.MethodGet, "/api/v1/incidents?selector=team%3Dpayments,source", "", "")
+	var incidents []event.Incident
+	if err := json.Unmarshal(rr.Body.Bytes(), &incidents); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(incidents) != 1 || incidents[0].GUID != incident.GUID {
+		t.Errorf("Expected the payments incident only, got %d incidents", len(incidents))
+	}
+
+	// Updates replace the labels
+	rr = serveIncident(h.HandleIncidentByID, http.MethodPut, "/api/v1/incidents/"+incident.GUID, incident.GUID,
+		`{"title":"Card payments fail","content":"Timeouts","components":["api"],"labels":{"team":"checkout"}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = serveIncident(h.HandleIncidents, http.MethodGet, "/api/v1/incidents?selector=team+in+(checkout)", "", "")
+	incidents = nil
+	if err := json.Unmarshal(rr.Body.Bytes(), &incidents); err != nil || len(incidents) != 1 || incidents[0].Labels["source"] != "" {
+		t.Errorf("Expected the relabelled incident only, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
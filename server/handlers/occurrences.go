@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+const (
+	// defaultOccurrenceWindow is listed when the occurrence listing has no to parameter
+	defaultOccurrenceWindow = 90 * 24 * time.Hour
+	// maxOccurrenceWindow bounds the occurrence listing, a daily series yields a few hundred occurrences
+	maxOccurrenceWindow = 366 * 24 * time.Hour
+)
+
+// OccurrenceOverrideRequest represents the JSON structure cancelling or moving an occurrence of a recurring maintenance
+type OccurrenceOverrideRequest struct {
+	Cancelled    bool       `json:"cancelled,omitempty"`
+	StartPlanned *time.Time `json:"start_planned,omitempty"` // The occurrence keeps its duration when only its start moves
+	EndPlanned   *time.Time `json:"end_planned,omitempty"`
+}
+
+// validate returns the validation errors of the request
+func (req *OccurrenceOverrideRequest) validate() []string {
+	var errors []string
+	if !req.Cancelled && req.StartPlanned == nil && req.EndPlanned == nil {
+		errors = append(errors, "the override must set 'cancelled', 'start_planned' or 'end_planned'")
+	}
+	if req.StartPlanned != nil && req.EndPlanned != nil && !req.EndPlanned.After(*req.StartPlanned) {
+		errors = append(errors, "field 'end_planned' must be after 'start_planned'")
+	}
+	return errors
+}
+
+// parseOccurrenceWindow reads the from and to query parameters of the occurrence listing
+func parseOccurrenceWindow(r *http.Request) (time.Time, time.Time, []string) {
+	var errors []string
+	from, to := time.Now(), time.Time{}
+	query := r.URL.Query()
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			errors = append(errors, "parameter 'from' must be an RFC3339 timestamp")
+		}
+		from = parsed
+	}
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			errors = append(errors, "parameter 'to' must be an RFC3339 timestamp")
+		}
+		to = parsed
+	} else {
+		to = from.Add(defaultOccurrenceWindow)
+	}
+	if len(errors) == 0 {
+		if !to.After(from) {
+			errors = append(errors, "parameter 'to' must be after 'from'")
+		} else if to.Sub(from) > maxOccurrenceWindow {
+			errors = append(errors, "the window between 'from' and 'to' cannot exceed 366 days")
+		}
+	}
+	return from, to, errors
+}
+
+// HandlePlannedMaintenanceOccurrences handles /planned-maintenances/{id}/occurrences endpoint
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceOccurrences(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing maintenance ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing maintenance ID")
+		return
+	}
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).WithField("maintenance_id", id).Warn("Method not allowed for maintenance occurrences endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	from, to, windowErrors := parseOccurrenceWindow(r)
+	if len(windowErrors) > 0 {
+		log.WithField("validation_errors", windowErrors).Warn("Invalid maintenance occurrences parameters")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Invalid query parameters",
+			"validation_errors": windowErrors,
+		})
+		return
+	}
+
+	maintenance, err := h.storage.GetPlannedMaintenance(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("maintenance_id", id).Warn("Maintenance not found")
+			h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
+			return
+		}
+		log.WithError(err).WithField("maintenance_id", id).Error("Failed to retrieve maintenance from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve planned maintenance")
+		return
+	}
+
+	occurrences, err := maintenance.Occurrences(from, to)
+	if err != nil {
+		log.WithError(err).WithField("maintenance_id", id).Error("Failed to expand maintenance occurrences")
+		h.writeError(w, http.StatusInternalServerError, "Failed to expand planned maintenance occurrences")
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(maintenance.Version))
+	h.writeJSON(w, http.StatusOK, occurrences)
+}
+
+// HandlePlannedMaintenanceOccurrence handles /planned-maintenances/{id}/occurrences/{recurrence_id} endpoint:
+// PUT overrides the occurrence, DELETE restores it as generated by the recurrence rule
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceOccurrence(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing maintenance ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing maintenance ID")
+		return
+	}
+	recurrenceID, err := time.Parse(time.RFC3339, r.PathValue("recurrence_id"))
+	if err != nil {
+		log.WithField("maintenance_id", id).Warn("Invalid recurrence ID in request path")
+		h.writeError(w, http.StatusBadRequest, "The recurrence ID must be an RFC3339 timestamp")
+		return
+	}
+
+	var change func(*event.PlannedMaintenance) error
+	switch r.Method {
+	case http.MethodPut:
+		var req OccurrenceOverrideRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.WithError(err).Warn("Failed to decode occurrence override JSON request")
+			h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":   "Invalid JSON format",
+				"details": err.Error(),
+			})
+			return
+		}
+		if validationErrors := req.validate(); len(validationErrors) > 0 {
+			log.WithField("validation_errors", validationErrors).WithField("maintenance_id", id).Warn("Occurrence override validation failed")
+			h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":             "Validation failed",
+				"validation_errors": validationErrors,
+			})
+			return
+		}
+		change = func(maintenance *event.PlannedMaintenance) error {
+			return maintenance.SetOverride(event.OccurrenceOverride{
+				RecurrenceID: recurrenceID,
+				Cancelled:    req.Cancelled,
+				StartPlanned: req.StartPlanned,
+				EndPlanned:   req.EndPlanned,
+			})
+		}
+	case http.MethodDelete:
+		change = func(maintenance *event.PlannedMaintenance) error {
+			if !maintenance.RemoveOverride(recurrenceID) {
+				return fmt.Errorf("%w: %s is not overridden", event.ErrOccurrenceNotFound, recurrenceID.Format(time.RFC3339))
+			}
+			return nil
+		}
+	default:
+		log.WithField("method", r.Method).WithField("maintenance_id", id).Warn("Method not allowed for maintenance occurrence endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("maintenance_id", id).Warn("Invalid If-Match header in occurrence override")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	maintenance, err := h.modifyPlannedMaintenance(r.Context(), id, expected, change)
+	if err != nil {
+		switch {
+		case err == drivers.ErrNotFound:
+			log.WithField("maintenance_id", id).Warn("Maintenance not found for occurrence override")
+			h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
+		case err == drivers.ErrVersionConflict:
+			log.WithField("maintenance_id", id).WithField("if_match", expected).Warn("Maintenance was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Planned maintenance was modified since it was retrieved")
+		case errors.Is(err, event.ErrOccurrenceNotFound):
+			log.WithError(err).WithField("maintenance_id", id).Warn("Occurrence not found")
+			h.writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, event.ErrInvalidTransition):
+			log.WithError(err).WithField("maintenance_id", id).Warn("Occurrence override not allowed")
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.WithError(err).WithField("maintenance_id", id).Error("Failed to update maintenance in storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to update planned maintenance")
+		}
+		return
+	}
+
+	log.WithField("maintenance_id", id).WithField("recurrence_id", recurrenceID).Info("Maintenance occurrence updated successfully")
+	w.Header().Set("ETag", formatETag(maintenance.Version))
+	h.writeJSON(w, http.StatusOK, maintenance)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -20,13 +22,17 @@ type PlannedMaintenanceHandler struct {
 
 // PlannedMaintenanceRequest represents the JSON structure for creating/updating planned maintenances
 type PlannedMaintenanceRequest struct {
-	GUID         string    `json:"guid,omitempty"`
-	Title        string    `json:"title"`
-	Content      string    `json:"content"`
-	Components   []string  `json:"components"` // Component codes as strings
-	StartPlanned time.Time `json:"start_planned"`
-	EndPlanned   time.Time `json:"end_planned"`
-	Cancelled    bool      `json:"cancelled,omitempty"`
+	GUID         string            `json:"guid,omitempty"`
+	Title        string            `json:"title"`
+	Content      string            `json:"content"`
+	Components   []string          `json:"components"` // Component codes as strings
+	Labels       map[string]string `json:"labels,omitempty"`
+	StartPlanned time.Time         `json:"start_planned"`
+	EndPlanned   time.Time         `json:"end_planned"`
+	Cancelled    bool              `json:"cancelled,omitempty"`
+	Recurrence   string            `json:"recurrence,omitempty"` // RRULE repeating the start to end window, such as FREQ=MONTHLY;BYDAY=2TU
+	AutoStart    bool              `json:"auto_start,omitempty"`
+	AutoComplete bool              `json:"auto_complete,omitempty"`
 }
 
 // ToPlannedMaintenance converts PlannedMaintenanceRequest to event.PlannedMaintenance by resolving component codes
@@ -45,16 +51,26 @@ func (req *PlannedMaintenanceRequest) ToPlannedMaintenance() *event.PlannedMaint
 		components = append(components, comp)
 	}
 
+	// Store the rule in its normalized form, it was validated beforehand
+	recurrence := req.Recurrence
+	if rule, err := event.ParseRecurrence(recurrence); err == nil {
+		recurrence = rule.String()
+	}
+
 	return &event.PlannedMaintenance{
 		BaseEvent: event.BaseEvent{
 			GUID:       req.GUID,
 			Title:      req.Title,
 			Content:    req.Content,
+			Labels:     req.Labels,
 			Components: components,
 		},
 		StartPlanned: req.StartPlanned,
 		EndPlanned:   req.EndPlanned,
 		Cancelled:    req.Cancelled,
+		Recurrence:   recurrence,
+		AutoStart:    req.AutoStart,
+		AutoComplete: req.AutoComplete,
 	}
 }
 
@@ -120,6 +136,14 @@ func (h *PlannedMaintenanceHandler) validatePlannedMaintenanceRequest(req *Plann
 		}
 	}
 
+	if req.Recurrence != "" {
+		if _, err := event.ParseRecurrence(req.Recurrence); err != nil {
+			errors = append(errors, fmt.Sprintf("field 'recurrence' is invalid: %v", err))
+		}
+	}
+
+	errors = append(errors, validateLabels(req.Labels)...)
+
 	return errors
 }
 
@@ -216,20 +240,33 @@ func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceByID(w http.Response
 	}
 }
 
-// getAllPlannedMaintenances returns all planned maintenances
+// getAllPlannedMaintenances returns the planned maintenances matching the listing query parameters
 func (h *PlannedMaintenanceHandler) getAllPlannedMaintenances(w http.ResponseWriter, r *http.Request) {
 	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
 	log.Info("Getting all planned maintenances")
 
-	maintenances, err := h.storage.GetAllPlannedMaintenances()
+	filter, filterErrors := parseEventFilter(r, plannedMaintenanceListParams)
+	if len(filterErrors) > 0 {
+		log.WithField("validation_errors", filterErrors).Warn("Invalid planned maintenance listing parameters")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Invalid query parameters",
+			"validation_errors": filterErrors,
+		})
+		return
+	}
+
+	page, err := h.storage.ListPlannedMaintenances(r.Context(), filter)
 	if err != nil {
 		log.WithError(err).Error("Failed to retrieve planned maintenances from storage")
 		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve planned maintenances")
 		return
 	}
 
-	log.WithField("count", len(maintenances)).Info("Retrieved planned maintenances successfully")
-	h.writeJSON(w, http.StatusOK, maintenances)
+	if page.NextCursor != "" {
+		w.Header().Set(nextCursorHeader, page.NextCursor)
+	}
+	log.WithField("count", len(page.Items)).Info("Retrieved planned maintenances successfully")
+	h.writeJSON(w, http.StatusOK, page.Items)
 }
 
 // getPlannedMaintenance returns a specific planned maintenance
@@ -237,7 +274,7 @@ func (h *PlannedMaintenanceHandler) getPlannedMaintenance(w http.ResponseWriter,
 	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
 	log.WithField("maintenance_id", id).Debug("Getting planned maintenance")
 
-	maintenance, err := h.storage.GetPlannedMaintenance(id)
+	maintenance, err := h.storage.GetPlannedMaintenance(r.Context(), id)
 	if err != nil {
 		if err == drivers.ErrNotFound {
 			log.WithField("maintenance_id", id).Warn("Maintenance not found")
@@ -250,6 +287,7 @@ func (h *PlannedMaintenanceHandler) getPlannedMaintenance(w http.ResponseWriter,
 	}
 
 	log.WithField("maintenance_id", id).Info("Maintenance retrieved successfully")
+	w.Header().Set("ETag", formatETag(maintenance.Version))
 	h.writeJSON(w, http.StatusOK, maintenance)
 }
 
@@ -287,11 +325,13 @@ func (h *PlannedMaintenanceHandler) createPlannedMaintenance(w http.ResponseWrit
 		log.Debug("Generating GUID for new maintenance")
 		// Create new planned maintenance with GUID - use provided times or current time
 		newMaintenance := event.NewPlannedMaintenance(maintenance.Title, maintenance.Content, maintenance.Components, maintenance.StartPlanned, maintenance.EndPlanned)
+		newMaintenance.Recurrence, newMaintenance.Labels = maintenance.Recurrence, maintenance.Labels
+		newMaintenance.AutoStart, newMaintenance.AutoComplete = maintenance.AutoStart, maintenance.AutoComplete
 		maintenance = newMaintenance
 		log.WithField("maintenance_id", maintenance.GUID).Debug("Generated new maintenance GUID")
 	}
 
-	if err := h.storage.CreatePlannedMaintenance(maintenance); err != nil {
+	if err := h.storage.CreatePlannedMaintenance(r.Context(), maintenance); err != nil {
 		if err == drivers.ErrExists {
 			log.WithField("maintenance_id", maintenance.GUID).Warn("Maintenance already exists")
 			h.writeError(w, http.StatusConflict, "Planned maintenance already exists")
@@ -307,6 +347,7 @@ func (h *PlannedMaintenanceHandler) createPlannedMaintenance(w http.ResponseWrit
 
 	log.WithField("maintenance_id", maintenance.GUID).Info("Maintenance created successfully")
 
+	w.Header().Set("ETag", formatETag(maintenance.Version))
 	h.writeJSON(w, http.StatusCreated, maintenance)
 }
 
@@ -342,12 +383,43 @@ func (h *PlannedMaintenanceHandler) updatePlannedMaintenance(w http.ResponseWrit
 	// Ensure the ID in the URL matches the maintenance GUID
 	maintenance.GUID = id
 
-	if err := h.storage.UpdatePlannedMaintenance(maintenance); err != nil {
+	// Only overwrite the version the client has seen when it sent If-Match
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("maintenance_id", id).Warn("Invalid If-Match header in maintenance update")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The effective times and actors recorded by the actions, and the updates, are kept from the stored maintenance,
+	// as are the occurrence overrides still matching the schedule
+	maintenance, err = h.modifyPlannedMaintenance(r.Context(), id, expected, func(stored *event.PlannedMaintenance) error {
+		maintenance.StartEffective, maintenance.StartedBy = stored.StartEffective, stored.StartedBy
+		maintenance.EndEffective, maintenance.CompletedBy = stored.EndEffective, stored.CompletedBy
+		if maintenance.Cancelled && stored.Cancelled {
+			maintenance.CancelledAt, maintenance.CancelledBy = stored.CancelledAt, stored.CancelledBy
+		}
+		// Extending a running maintenance past now lifts its overrun
+		if stored.OverrunAt != nil && (maintenance.EndEffective != nil || !maintenance.EndPlanned.After(time.Now())) {
+			maintenance.OverrunAt = stored.OverrunAt
+		}
+		maintenance.Updates = stored.Updates
+		maintenance.Overrides = stored.Overrides
+		maintenance.PruneOverrides()
+		*stored = *maintenance
+		return nil
+	})
+	if err != nil {
 		if err == drivers.ErrNotFound {
 			log.WithField("maintenance_id", id).Warn("Maintenance not found for update")
 			h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
 			return
 		}
+		if err == drivers.ErrVersionConflict {
+			log.WithField("maintenance_id", id).WithField("if_match", expected).Warn("Maintenance was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Planned maintenance was modified since it was retrieved")
+			return
+		}
 		log.WithError(err).WithField("maintenance_id", id).Error("Failed to update maintenance in storage")
 		h.writeJSON(w, http.StatusInternalServerError, map[string]interface{}{
 			"error":   "Failed to update planned maintenance",
@@ -357,6 +429,7 @@ func (h *PlannedMaintenanceHandler) updatePlannedMaintenance(w http.ResponseWrit
 	}
 
 	log.WithField("maintenance_id", id).Info("Maintenance updated successfully")
+	w.Header().Set("ETag", formatETag(maintenance.Version))
 	h.writeJSON(w, http.StatusOK, maintenance)
 }
 
@@ -365,7 +438,7 @@ func (h *PlannedMaintenanceHandler) deletePlannedMaintenance(w http.ResponseWrit
 	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
 	log.WithField("maintenance_id", id).Info("Deleting planned maintenance")
 
-	if err := h.storage.DeletePlannedMaintenance(id); err != nil {
+	if err := h.storage.DeletePlannedMaintenance(r.Context(), id); err != nil {
 		if err == drivers.ErrNotFound {
 			log.WithField("maintenance_id", id).Warn("Maintenance not found for deletion")
 			h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
@@ -379,3 +452,289 @@ func (h *PlannedMaintenanceHandler) deletePlannedMaintenance(w http.ResponseWrit
 	log.WithField("maintenance_id", id).Info("Maintenance deleted successfully")
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// HandlePlannedMaintenanceRevisions handles /planned-maintenances/{id}/revisions endpoint
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceRevisions(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing planned maintenance ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing planned maintenance ID")
+		return
+	}
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).WithField("maintenance_id", id).Warn("Method not allowed for planned maintenance revisions endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	revisions, err := h.storage.GetPlannedMaintenanceRevisions(r.Context(), id)
+	if err != nil {
+		log.WithError(err).WithField("maintenance_id", id).Error("Failed to retrieve planned maintenance revisions from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve planned maintenance revisions")
+		return
+	}
+
+	// Events created before revisions were recorded have no history yet, only unknown IDs are not found
+	if len(revisions) == 0 {
+		if _, err := h.storage.GetPlannedMaintenance(r.Context(), id); err != nil {
+			if err == drivers.ErrNotFound {
+				log.WithField("maintenance_id", id).Warn("Planned maintenance not found")
+				h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
+				return
+			}
+			log.WithError(err).WithField("maintenance_id", id).Error("Failed to retrieve planned maintenance from storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to retrieve planned maintenance")
+			return
+		}
+	}
+
+	log.WithField("maintenance_id", id).WithField("count", len(revisions)).Info("Retrieved planned maintenance revisions successfully")
+	h.writeJSON(w, http.StatusOK, revisions)
+}
+
+// HandlePlannedMaintenanceRestore handles /planned-maintenances/{id}/restore endpoint
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceRestore(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing planned maintenance ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing planned maintenance ID")
+		return
+	}
+	if r.Method != http.MethodPost {
+		log.WithField("method", r.Method).WithField("maintenance_id", id).Warn("Method not allowed for planned maintenance restore endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	maintenance, err := h.storage.RestorePlannedMaintenance(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("maintenance_id", id).Warn("Planned maintenance not found in trash")
+			h.writeError(w, http.StatusNotFound, "Planned maintenance not found in trash")
+			return
+		}
+		log.WithError(err).WithField("maintenance_id", id).Error("Failed to restore planned maintenance in storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to restore planned maintenance")
+		return
+	}
+
+	log.WithField("maintenance_id", id).Info("Planned maintenance restored successfully")
+	w.Header().Set("ETag", formatETag(maintenance.Version))
+	h.writeJSON(w, http.StatusOK, maintenance)
+}
+
+// modifyPlannedMaintenance applies change to a copy of the stored maintenance and writes it back.
+// A write racing with another one is retried, unless the client sent If-Match: the stored version must then
+// be one of the expected ones, otherwise ErrVersionConflict is returned.
+func (h *PlannedMaintenanceHandler) modifyPlannedMaintenance(ctx context.Context, id string, expected []int64, change func(*event.PlannedMaintenance) error) (*event.PlannedMaintenance, error) {
+	for attempt := 1; ; attempt++ {
+		stored, err := h.storage.GetPlannedMaintenance(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesVersion(expected, stored.Version) {
+			return nil, drivers.ErrVersionConflict
+		}
+
+		maintenance := *stored
+		if err := change(&maintenance); err != nil {
+			return nil, err
+		}
+		maintenance.Version = stored.Version
+		err = h.storage.UpdatePlannedMaintenance(ctx, &maintenance)
+		if err == drivers.ErrVersionConflict && len(expected) == 0 && attempt < maxModifyAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &maintenance, nil
+	}
+}
+
+// HandlePlannedMaintenanceUpdates handles /planned-maintenances/{id}/updates endpoint: GET lists the updates
+// posted on the maintenance, POST adds one
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceUpdates(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing maintenance ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing maintenance ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getPlannedMaintenanceUpdates(w, r, id)
+	case http.MethodPost:
+		h.postPlannedMaintenanceUpdate(w, r, id)
+	default:
+		log.WithField("method", r.Method).WithField("maintenance_id", id).Warn("Method not allowed for maintenance updates endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getPlannedMaintenanceUpdates returns the updates of a maintenance, oldest first
+func (h *PlannedMaintenanceHandler) getPlannedMaintenanceUpdates(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
+
+	maintenance, err := h.storage.GetPlannedMaintenance(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("maintenance_id", id).Warn("Maintenance not found")
+			h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
+			return
+		}
+		log.WithError(err).WithField("maintenance_id", id).Error("Failed to retrieve maintenance from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve planned maintenance")
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(maintenance.Version))
+	h.writeJSON(w, http.StatusOK, eventUpdates(&maintenance.BaseEvent))
+}
+
+// postPlannedMaintenanceUpdate adds an update to a maintenance
+func (h *PlannedMaintenanceHandler) postPlannedMaintenanceUpdate(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler")
+
+	var req EventUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.WithError(err).Warn("Failed to decode maintenance update JSON request")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+	if validationErrors := req.validate(); len(validationErrors) > 0 {
+		log.WithField("validation_errors", validationErrors).WithField("maintenance_id", id).Warn("Maintenance update post validation failed")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":             "Validation failed",
+			"validation_errors": validationErrors,
+		})
+		return
+	}
+
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("maintenance_id", id).Warn("Invalid If-Match header in maintenance update post")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var update event.Update
+	author := drivers.AuthorFromContext(r.Context())
+	maintenance, err := h.modifyPlannedMaintenance(r.Context(), id, expected, func(maintenance *event.PlannedMaintenance) error {
+		update = maintenance.PostUpdate(req.Message, author, time.Now())
+		return nil
+	})
+	if err != nil {
+		switch err {
+		case drivers.ErrNotFound:
+			log.WithField("maintenance_id", id).Warn("Maintenance not found for update post")
+			h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
+		case drivers.ErrVersionConflict:
+			log.WithField("maintenance_id", id).WithField("if_match", expected).Warn("Maintenance was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Planned maintenance was modified since it was retrieved")
+		default:
+			log.WithError(err).WithField("maintenance_id", id).Error("Failed to update maintenance in storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to post maintenance update")
+		}
+		return
+	}
+
+	log.WithField("maintenance_id", id).WithField("update_id", update.GUID).Info("Maintenance update posted successfully")
+	w.Header().Set("ETag", formatETag(maintenance.Version))
+	h.writeJSON(w, http.StatusCreated, update)
+}
+
+// HandlePlannedMaintenanceStart handles /planned-maintenances/{id}/start endpoint
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceStart(w http.ResponseWriter, r *http.Request) {
+	h.applyPlannedMaintenanceAction(w, r, "start", (*event.PlannedMaintenance).Start)
+}
+
+// HandlePlannedMaintenanceComplete handles /planned-maintenances/{id}/complete endpoint
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceComplete(w http.ResponseWriter, r *http.Request) {
+	h.applyPlannedMaintenanceAction(w, r, "complete", (*event.PlannedMaintenance).Complete)
+}
+
+// HandlePlannedMaintenanceCancel handles /planned-maintenances/{id}/cancel endpoint
+func (h *PlannedMaintenanceHandler) HandlePlannedMaintenanceCancel(w http.ResponseWriter, r *http.Request) {
+	h.applyPlannedMaintenanceAction(w, r, "cancel", (*event.PlannedMaintenance).Cancel)
+}
+
+// applyPlannedMaintenanceAction applies an action to a maintenance on behalf of the request author.
+// The optional message of the request is posted as an update, actions which cannot apply are rejected with 409 Conflict.
+func (h *PlannedMaintenanceHandler) applyPlannedMaintenanceAction(w http.ResponseWriter, r *http.Request, action string,
+	apply func(maintenance *event.PlannedMaintenance, author string, at time.Time) error) {
+	log := logger.GetDefault().WithComponent("PlannedMaintenanceHandler").WithField("action", action)
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing maintenance ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing maintenance ID")
+		return
+	}
+	if r.Method != http.MethodPost {
+		log.WithField("method", r.Method).WithField("maintenance_id", id).Warn("Method not allowed for maintenance action endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req, err := decodeActionRequest(r)
+	if err != nil {
+		log.WithError(err).Warn("Failed to decode maintenance action JSON request")
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("maintenance_id", id).Warn("Invalid If-Match header in maintenance action")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	author := drivers.AuthorFromContext(r.Context())
+	message := strings.TrimSpace(req.Message)
+	maintenance, err := h.modifyPlannedMaintenance(r.Context(), id, expected, func(maintenance *event.PlannedMaintenance) error {
+		now := time.Now()
+		if err := apply(maintenance, author, now); err != nil {
+			return err
+		}
+		if message != "" {
+			maintenance.PostUpdate(message, author, now)
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case err == drivers.ErrNotFound:
+			log.WithField("maintenance_id", id).Warn("Maintenance not found for action")
+			h.writeError(w, http.StatusNotFound, "Planned maintenance not found")
+		case err == drivers.ErrVersionConflict:
+			log.WithField("maintenance_id", id).WithField("if_match", expected).Warn("Maintenance was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Planned maintenance was modified since it was retrieved")
+		case errors.Is(err, event.ErrInvalidTransition):
+			log.WithError(err).WithField("maintenance_id", id).Warn("Maintenance action not allowed")
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.WithError(err).WithField("maintenance_id", id).Error("Failed to update maintenance in storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to update planned maintenance")
+		}
+		return
+	}
+
+	log.WithField("maintenance_id", id).WithField("author", author).Info("Maintenance action applied successfully")
+	w.Header().Set("ETag", formatETag(maintenance.Version))
+	h.writeJSON(w, http.StatusOK, maintenance)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+func TestPlannedMaintenanceHandler_Updates(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewPlannedMaintenanceHandler(storage)
+
+	maintenanceJSON := `{"title":"Database upgrade","content":"PostgreSQL 16","components":["db"],` +
+		`"start_planned":"2030-01-01T10:00:00Z","end_planned":"2030-01-01T12:00:00Z"}`
+	rr := serveIncident(h.HandlePlannedMaintenances, http.MethodPost, "/api/v1/planned-maintenances", "", maintenanceJSON)
+	var maintenance event.PlannedMaintenance
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	updates := "/api/v1/planned-maintenances/" + maintenance.GUID + "/updates"
+	rr = serveIncident(h.HandlePlannedMaintenanceUpdates, http.MethodPost, updates, maintenance.GUID, `{"message":"Backups are done"}`)
+	if rr.Code != http.StatusCreated || rr.Header().Get("ETag") != `"2"` {
+		t.Fatalf("Expected 201 at version 2, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Rescheduling the maintenance keeps its updates
+	rr = serveIncident(h.HandlePlannedMaintenanceByID, http.MethodPut, "/api/v1/planned-maintenances/"+maintenance.GUID, maintenance.GUID, maintenanceJSON)
+	var updated event.PlannedMaintenance
+	if err := json.Unmarshal(rr.Body.Bytes(), &updated); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(updated.Updates) != 1 || updated.Updates[0].Message != "Backups are done" || updated.Updates[0].Author != "alice" {
+		t.Errorf("Expected the update to be kept, got %+v", updated.Updates)
+	}
+
+	rr = serveIncident(h.HandlePlannedMaintenanceUpdates, http.MethodDelete, updates, maintenance.GUID, "")
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rr.Code)
+	}
+}
+
+func TestPlannedMaintenanceHandler_Actions(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewPlannedMaintenanceHandler(storage)
+
+	maintenanceJSON := `{"title":"Database upgrade","content":"PostgreSQL 16","components":["db"],` +
+		`"start_planned":"2030-01-01T10:00:00Z","end_planned":"2030-01-01T12:00:00Z"}`
+	rr := serveIncident(h.HandlePlannedMaintenances, http.MethodPost, "/api/v1/planned-maintenances", "", maintenanceJSON)
+	var maintenance event.PlannedMaintenance
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	path := "/api/v1/planned-maintenances/" + maintenance.GUID
+
+	rr = serveIncident(h.HandlePlannedMaintenanceComplete, http.MethodPost, path+"/complete", maintenance.GUID, "")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 when completing a maintenance which did not start, got %d", rr.Code)
+	}
+
+	rr = serveIncident(h.HandlePlannedMaintenanceStart, http.MethodPost, path+"/start", maintenance.GUID, `{"message":"Starting early"}`)
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if maintenance.StartEffective == nil || maintenance.StartedBy != "alice" || len(maintenance.Updates) != 1 {
+		t.Errorf("Expected the maintenance started by alice with an update, got %+v", maintenance)
+	}
+
+	// Rescheduling a started maintenance keeps its effective start
+	rr = serveIncident(h.HandlePlannedMaintenanceByID, http.MethodPut, path, maintenance.GUID, maintenanceJSON)
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if maintenance.StartEffective == nil || maintenance.StartedBy != "alice" {
+		t.Errorf("Expected the update to keep the effective start")
+	}
+
+	rr = serveIncident(h.HandlePlannedMaintenanceCancel, http.MethodPost, path+"/cancel", maintenance.GUID, "")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 when cancelling a started maintenance, got %d", rr.Code)
+	}
+	rr = serveIncident(h.HandlePlannedMaintenanceComplete, http.MethodPost, path+"/complete", maintenance.GUID, "")
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if maintenance.EndEffective == nil || maintenance.CompletedBy != "alice" || len(maintenance.Updates) != 1 {
+		t.Errorf("Expected the maintenance completed by alice without a new update, got %+v", maintenance)
+	}
+}
+
+func TestPlannedMaintenanceHandler_Occurrences(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewPlannedMaintenanceHandler(storage)
+
+	rr := serveIncident(h.HandlePlannedMaintenances, http.MethodPost, "/api/v1/planned-maintenances", "",
+		`{"title":"Patch Tuesday","content":"Rolling reboot","components":["api"],"start_planned":"2030-01-08T22:00:00Z",`+
+			`"end_planned":"2030-01-09T00:00:00Z","recurrence":"rrule:freq=monthly;byday=2tu"}`)
+	var maintenance event.PlannedMaintenance
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if maintenance.Recurrence != "FREQ=MONTHLY;BYDAY=2TU" {
+		t.Errorf("Expected the normalized rule, got %q", maintenance.Recurrence)
+	}
+	path := "/api/v1/planned-maintenances/" + maintenance.GUID
+
+	rr = serveIncident(h.HandlePlannedMaintenanceOccurrences, http.MethodGet, path+"/occurrences?from=2030-01-01T00:00:00Z&to=2030-04-01T00:00:00Z", maintenance.GUID, "")
+	var occurrences []event.Occurrence
+	if err := json.Unmarshal(rr.Body.Bytes(), &occurrences); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(occurrences) != 3 || occurrences[1].StartPlanned.Format(time.RFC3339) != "2030-02-12T22:00:00Z" {
+		t.Fatalf("Expected the second Tuesdays of January to March, got %+v", occurrences)
+	}
+
+	override := func(method, recurrenceID, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path+"/occurrences/"+recurrenceID, strings.NewReader(body))
+		request.SetPathValue("id", maintenance.GUID)
+		request.SetPathValue("recurrence_id", recurrenceID)
+		rr := httptest.NewRecorder()
+		h.HandlePlannedMaintenanceOccurrence(rr, request)
+		return rr
+	}
+
+	rr = override(http.MethodPut, "2030-02-12T22:00:00Z", `{"cancelled":true}`)
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(maintenance.Overrides) != 1 || !maintenance.Overrides[0].Cancelled {
+		t.Errorf("Expected the February occurrence to be cancelled, got %+v", maintenance.Overrides)
+	}
+
+	if rr = override(http.MethodPut, "2030-02-13T22:00:00Z", `{"cancelled":true}`); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a date which is not an occurrence, got %d", rr.Code)
+	}
+	if rr = override(http.MethodPut, "tuesday", `{"cancelled":true}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid recurrence ID, got %d", rr.Code)
+	}
+	if rr = override(http.MethodPut, "2030-03-12T22:00:00Z", `{}`); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an empty override, got %d", rr.Code)
+	}
+	if rr = override(http.MethodPut, "2030-03-12T22:00:00Z", `{"end_planned":"2030-03-12T21:00:00Z"}`); rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for an end before the occurrence start, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Rescheduling the series keeps the overrides matching the new schedule
+	rr = serveIncident(h.HandlePlannedMaintenanceByID, http.MethodPut, path, maintenance.GUID,
+		`{"title":"Patch Tuesday","content":"Rolling reboot","components":["api"],"start_planned":"2030-01-08T22:00:00Z",`+
+			`"end_planned":"2030-01-09T01:00:00Z","recurrence":"FREQ=MONTHLY;BYDAY=2TU;COUNT=6"}`)
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(maintenance.Overrides) != 1 {
+		t.Errorf("Expected the override to be kept, got %+v", maintenance.Overrides)
+	}
+
+	if rr = override(http.MethodDelete, "2030-02-12T22:00:00Z", ""); rr.Code != http.StatusOK {
+		t.Errorf("Expected 200 when removing the override, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr = override(http.MethodDelete, "2030-02-12T22:00:00Z", ""); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when the occurrence is not overridden, got %d", rr.Code)
+	}
+
+	rr = serveIncident(h.HandlePlannedMaintenanceStart, http.MethodPost, path+"/start", maintenance.GUID, "")
+	if rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 when starting a series, got %d", rr.Code)
+	}
+
+	rr = serveIncident(h.HandlePlannedMaintenances, http.MethodPost, "/api/v1/planned-maintenances", "",
+		`{"title":"Patch","content":"Reboot","components":["api"],"start_planned":"2030-01-08T22:00:00Z",`+
+			`"end_planned":"2030-01-09T00:00:00Z","recurrence":"FREQ=HOURLY"}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unsupported rule, got %d", rr.Code)
+	}
+}
+
+func TestPlannedMaintenanceHandler_AutoSchedule(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	h := NewPlannedMaintenanceHandler(storage)
+
+	start := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	body := func(end time.Time) string {
+		return `{"title":"Database upgrade","content":"PostgreSQL 16","components":["db"],"auto_start":true,` +
+			`"start_planned":"` + start + `","end_planned":"` + end.UTC().Format(time.RFC3339) + `"}`
+	}
+	rr := serveIncident(h.HandlePlannedMaintenances, http.MethodPost, "/api/v1/planned-maintenances", "", body(time.Now().Add(-time.Minute)))
+	var maintenance event.PlannedMaintenance
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !maintenance.AutoStart || maintenance.AutoComplete {
+		t.Fatalf("Expected the maintenance to opt in to the automatic start only, got %+v", maintenance)
+	}
+
+	// The scheduler starts the maintenance and flags it as running past its end
+	ctx := drivers.WithAuthor(context.Background(), "scheduler")
+	if report, err := drivers.ApplySchedule(ctx, storage, time.Now()); err != nil || report.Started != 1 || report.Overruns != 1 {
+		t.Fatalf("Expected the maintenance started and overrun, got %+v (%v)", report, err)
+	}
+
+	path := "/api/v1/planned-maintenances/" + maintenance.GUID
+	rr = serveIncident(h.HandlePlannedMaintenanceByID, http.MethodPut, path, maintenance.GUID, body(time.Now().Add(-time.Minute)))
+	if err := json.Unmarshal(rr.Body.Bytes(), &maintenance); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if maintenance.OverrunAt == nil || maintenance.StartedBy != "scheduler" {
+		t.Errorf("Expected the update to keep the overrun, got %+v", maintenance)
+	}
+
+	// Extending the maintenance lifts the overrun
+	rr = serveIncident(h.HandlePlannedMaintenanceByID, http.MethodPut, path, maintenance.GUID, body(time.Now().Add(time.Hour)))
+	var extended event.PlannedMaintenance
+	if err := json.Unmarshal(rr.Body.Bytes(), &extended); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if extended.OverrunAt != nil {
+		t.Errorf("Expected the overrun to be lifted, got %v", extended.OverrunAt)
+	}
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// maxPostmortemLength bounds the summary and the root cause of a postmortem
+const maxPostmortemLength = 50000
+
+// errNoPostmortem is returned by the postmortem removal of an incident which has none
+var errNoPostmortem = errors.New("incident has no postmortem")
+
+// PostmortemRequest represents the JSON structure writing the postmortem of an incident
+type PostmortemRequest struct {
+	Summary             string              `json:"summary"`
+	RootCause           string              `json:"root_cause"`
+	ContributingFactors []string            `json:"contributing_factors,omitempty"`
+	ActionItems         []ActionItemRequest `json:"action_items,omitempty"`
+	Published           bool                `json:"published,omitempty"`
+}
+
+// ActionItemRequest represents the JSON structure of a postmortem action item
+type ActionItemRequest struct {
+	Description string     `json:"description"`
+	Owner       string     `json:"owner"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Done        bool       `json:"done,omitempty"`
+}
+
+// validate returns the validation errors of the request
+func (req *PostmortemRequest) validate() []string {
+	var errors []string
+	if strings.TrimSpace(req.Summary) == "" {
+		errors = append(errors, "field 'summary' is required and cannot be empty")
+	} else if len(req.Summary) > maxPostmortemLength {
+		errors = append(errors, "field 'summary' cannot be longer than 50000 characters")
+	}
+	if strings.TrimSpace(req.RootCause) == "" {
+		errors = append(errors, "field 'root_cause' is required and cannot be empty")
+	} else if len(req.RootCause) > maxPostmortemLength {
+		errors = append(errors, "field 'root_cause' cannot be longer than 50000 characters")
+	}
+	for i, factor := range req.ContributingFactors {
+		if strings.TrimSpace(factor) == "" {
+			errors = append(errors, fmt.Sprintf("contributing factor %d cannot be empty", i))
+		}
+	}
+	for i, item := range req.ActionItems {
+		if strings.TrimSpace(item.Description) == "" {
+			errors = append(errors, fmt.Sprintf("action item %d: field 'description' is required and cannot be empty", i))
+		}
+		if strings.TrimSpace(item.Owner) == "" {
+			errors = append(errors, fmt.Sprintf("action item %d: field 'owner' is required and cannot be empty", i))
+		}
+	}
+	return errors
+}
+
+// ToPostmortem converts PostmortemRequest to event.Postmortem
+func (req *PostmortemRequest) ToPostmortem() event.Postmortem {
+	postmortem := event.Postmortem{
+		Summary:             req.Summary,
+		RootCause:           req.RootCause,
+		ContributingFactors: req.ContributingFactors,
+		Published:           req.Published,
+	}
+	for _, item := range req.ActionItems {
+		postmortem.ActionItems = append(postmortem.ActionItems, event.ActionItem{
+			Description: item.Description,
+			Owner:       item.Owner,
+			DueDate:     item.DueDate,
+			Done:        item.Done,
+		})
+	}
+	return postmortem
+}
+
+// HandleIncidentPostmortem handles /incidents/{id}/postmortem endpoint: GET returns the postmortem,
+// PUT writes it once the incident is resolved and DELETE removes it
+func (h *IncidentHandler) HandleIncidentPostmortem(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	id := r.PathValue("id")
+	if id == "" {
+		log.Warn("Missing incident ID in request path")
+		h.writeError(w, http.StatusBadRequest, "Missing incident ID")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getIncidentPostmortem(w, r, id)
+	case http.MethodPut, http.MethodDelete:
+		h.writeIncidentPostmortem(w, r, id)
+	default:
+		log.WithField("method", r.Method).WithField("incident_id", id).Warn("Method not allowed for incident postmortem endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// getIncidentPostmortem returns the postmortem of an incident
+func (h *IncidentHandler) getIncidentPostmortem(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	incident, err := h.storage.GetIncident(r.Context(), id)
+	if err != nil {
+		if err == drivers.ErrNotFound {
+			log.WithField("incident_id", id).Warn("Incident not found")
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+			return
+		}
+		log.WithError(err).WithField("incident_id", id).Error("Failed to retrieve incident from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incident")
+		return
+	}
+	incident = visibleIncident(r, incident)
+	if incident.Postmortem == nil {
+		log.WithField("incident_id", id).Debug("Incident has no postmortem")
+		h.writeError(w, http.StatusNotFound, "Incident has no postmortem")
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(incident.Version))
+	h.writeJSON(w, http.StatusOK, incident.Postmortem)
+}
+
+// writeIncidentPostmortem replaces or removes the postmortem of an incident
+func (h *IncidentHandler) writeIncidentPostmortem(w http.ResponseWriter, r *http.Request, id string) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	var change func(*event.Incident) error
+	created := false
+	if r.Method == http.MethodPut {
+		var req PostmortemRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.WithError(err).Warn("Failed to decode postmortem JSON request")
+			h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":   "Invalid JSON format",
+				"details": err.Error(),
+			})
+			return
+		}
+		if validationErrors := req.validate(); len(validationErrors) > 0 {
+			log.WithField("validation_errors", validationErrors).WithField("incident_id", id).Warn("Postmortem validation failed")
+			h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":             "Validation failed",
+				"validation_errors": validationErrors,
+			})
+			return
+		}
+		author := drivers.AuthorFromContext(r.Context())
+		change = func(incident *event.Incident) error {
+			created = incident.Postmortem == nil
+			return incident.SetPostmortem(req.ToPostmortem(), author, time.Now())
+		}
+	} else {
+		change = func(incident *event.Incident) error {
+			if incident.Postmortem == nil {
+				return errNoPostmortem
+			}
+			incident.Postmortem = nil
+			return nil
+		}
+	}
+
+	expected, err := parseIfMatch(r)
+	if err != nil {
+		log.WithError(err).WithField("incident_id", id).Warn("Invalid If-Match header in postmortem request")
+		h.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	incident, err := h.modifyIncident(r.Context(), id, expected, change)
+	if err != nil {
+		switch {
+		case err == drivers.ErrNotFound:
+			log.WithField("incident_id", id).Warn("Incident not found for postmortem")
+			h.writeError(w, http.StatusNotFound, "Incident not found")
+		case err == drivers.ErrVersionConflict:
+			log.WithField("incident_id", id).WithField("if_match", expected).Warn("Incident was modified concurrently")
+			h.writeError(w, http.StatusPreconditionFailed, "Incident was modified since it was retrieved")
+		case err == errNoPostmortem:
+			log.WithField("incident_id", id).Warn("Incident has no postmortem to delete")
+			h.writeError(w, http.StatusNotFound, "Incident has no postmortem")
+		case errors.Is(err, event.ErrInvalidTransition):
+			log.WithError(err).WithField("incident_id", id).Warn("Postmortem not allowed")
+			h.writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.WithError(err).WithField("incident_id", id).Error("Failed to update incident in storage")
+			h.writeError(w, http.StatusInternalServerError, "Failed to update incident")
+		}
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(incident.Version))
+	if r.Method == http.MethodDelete {
+		log.WithField("incident_id", id).Info("Incident postmortem deleted successfully")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	log.WithField("incident_id", id).WithField("published", incident.Postmortem.Published).Info("Incident postmortem written successfully")
+	h.writeJSON(w, status, incident.Postmortem)
+}
+
+// HandleMissingPostmortems handles /incidents/missing-postmortems endpoint: it lists the resolved incidents
+// of at least the criticality query parameter, major outage by default, without a published postmortem
+func (h *IncidentHandler) HandleMissingPostmortems(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("IncidentHandler")
+
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).Warn("Method not allowed for missing postmortems endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	minimum := event.CriticalityMajorOutage
+	if value := r.URL.Query().Get("criticality"); value != "" {
+		parsed, err := parseCriticality(value)
+		if err != nil || parsed == event.CriticalityUnknown {
+			log.WithField("criticality", value).Warn("Invalid missing postmortems criticality")
+			h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":             "Invalid query parameters",
+				"validation_errors": []string{"parameter 'criticality' must be operational, degraded, partial outage or major outage"},
+			})
+			return
+		}
+		minimum = parsed
+	}
+
+	page, err := h.storage.ListIncidents(r.Context(), drivers.EventFilter{
+		Statuses: []event.Status{event.StatusResolved},
+		Types:    []event.TypeEvent{event.TypeFiringIncident},
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to retrieve incidents from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve incidents")
+		return
+	}
+
+	missing := []*event.Incident{}
+	for _, incident := range page.Items {
+		if incident.NeedsPostmortem(minimum) {
+			missing = append(missing, incident)
+		}
+	}
+
+	log.WithField("count", len(missing)).WithField("criticality", minimum.String()).Info("Retrieved incidents missing a postmortem")
+	h.writeJSON(w, http.StatusOK, visibleIncidents(r, missing))
+}
+
+// visibleIncident returns the incident as the reader of the request may see it: unpublished postmortems
+// are drafts, only shown to authenticated readers
+func visibleIncident(r *http.Request, incident *event.Incident) *event.Incident {
+	if incident.Postmortem == nil || incident.Postmortem.Published || drivers.AuthorFromContext(r.Context()) != "" {
+		return incident
+	}
+	hidden := *incident
+	hidden.Postmortem = nil
+	return &hidden
+}
+
+// visibleIncidents applies visibleIncident to every incident
+func visibleIncidents(r *http.Request, incidents []*event.Incident) []*event.Incident {
+	visible := make([]*event.Incident, len(incidents))
+	for i, incident := range incidents {
+		visible[i] = visibleIncident(r, incident)
+	}
+	return visible
+}
+
+// visibleRevisions removes the postmortem changes involving a draft from the revisions shown to anonymous readers
+func visibleRevisions(r *http.Request, revisions []*event.Revision) []*event.Revision {
+	if drivers.AuthorFromContext(r.Context()) != "" {
+		return revisions
+	}
+	visible := make([]*event.Revision, len(revisions))
+	for i, revision := range revisions {
+		hidden := *revision
+		hidden.Changes = make([]event.FieldChange, 0, len(revision.Changes))
+		for _, change := range revision.Changes {
+			if change.Field == "postmortem" && (isDraftPostmortem(change.Before) || isDraftPostmortem(change.After)) {
+				continue
+			}
+			hidden.Changes = append(hidden.Changes, change)
+		}
+		visible[i] = &hidden
+	}
+	return visible
+}
+
+// isDraftPostmortem reports whether a revision field value holds an unpublished postmortem
+func isDraftPostmortem(value json.RawMessage) bool {
+	if len(value) == 0 {
+		return false
+	}
+	var postmortem *event.Postmortem
+	if err := json.Unmarshal(value, &postmortem); err != nil {
+		return true // Hide what cannot be checked
+	}
+	return postmortem != nil && !postmortem.Published
+}
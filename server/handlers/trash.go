@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gmllt/clariti/logger"
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/config"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+// TrashHandler handles the deleted events
+type TrashHandler struct {
+	storage    drivers.EventStorage
+	purgeAfter time.Duration
+}
+
+// TrashResponse lists the events in the trash, most recently deleted first
+type TrashResponse struct {
+	Incidents           []*event.Incident           `json:"incidents"`
+	PlannedMaintenances []*event.PlannedMaintenance `json:"planned_maintenances"`
+}
+
+// PurgeResponse reports the outcome of a trash purge
+type PurgeResponse struct {
+	Purged        int       `json:"purged"`
+	DeletedBefore time.Time `json:"deleted_before"`
+}
+
+// NewTrashHandler creates a new trash handler purging events deleted more than purgeAfter ago by default
+func NewTrashHandler(storage drivers.EventStorage, purgeAfter time.Duration) *TrashHandler {
+	if purgeAfter <= 0 {
+		purgeAfter = config.DefaultTrashPurgeAfter
+	}
+	return &TrashHandler{storage: storage, purgeAfter: purgeAfter}
+}
+
+// writeJSON writes a JSON response
+func (h *TrashHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// writeError writes an error response
+func (h *TrashHandler) writeError(w http.ResponseWriter, status int, message string) {
+	h.writeJSON(w, status, map[string]string{"error": message})
+}
+
+// HandleTrash handles /trash endpoint
+func (h *TrashHandler) HandleTrash(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("TrashHandler")
+
+	if r.Method != http.MethodGet {
+		log.WithField("method", r.Method).Warn("Method not allowed for trash endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	incidents, err := h.storage.GetDeletedIncidents(r.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to retrieve deleted incidents from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve deleted incidents")
+		return
+	}
+	pms, err := h.storage.GetDeletedPlannedMaintenances(r.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to retrieve deleted planned maintenances from storage")
+		h.writeError(w, http.StatusInternalServerError, "Failed to retrieve deleted planned maintenances")
+		return
+	}
+
+	log.WithField("incidents", len(incidents)).WithField("planned_maintenances", len(pms)).Info("Retrieved trash successfully")
+	h.writeJSON(w, http.StatusOK, TrashResponse{Incidents: visibleIncidents(r, incidents), PlannedMaintenances: pms})
+}
+
+// HandlePurge handles /trash/purge endpoint.
+// The optional older_than query parameter (Go duration, e.g. 72h) overrides the configured age.
+func (h *TrashHandler) HandlePurge(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetDefault().WithComponent("TrashHandler")
+
+	if r.Method != http.MethodPost {
+		log.WithField("method", r.Method).Warn("Method not allowed for trash purge endpoint")
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	age := h.purgeAfter
+	if raw := r.URL.Query().Get("older_than"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < 0 {
+			log.WithField("older_than", raw).Warn("Invalid trash purge age")
+			h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error":             "Invalid query parameters",
+				"validation_errors": []string{"parameter 'older_than' must be a non-negative duration such as 72h"},
+			})
+			return
+		}
+		age = parsed
+	}
+
+	before := time.Now().Add(-age).UTC()
+	purged, err := h.storage.PurgeDeleted(r.Context(), before)
+	if err != nil {
+		log.WithError(err).Error("Failed to purge deleted events")
+		h.writeError(w, http.StatusInternalServerError, "Failed to purge deleted events")
+		return
+	}
+
+	log.WithField("purged", purged).WithField("deleted_before", before).Info("Trash purged successfully")
+	h.writeJSON(w, http.StatusOK, PurgeResponse{Purged: purged, DeletedBefore: before})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gmllt/clariti/models/event"
+	"github.com/gmllt/clariti/server/drivers"
+)
+
+func TestTrashHandler(t *testing.T) {
+	storage := drivers.NewRAMStorage()
+	incidents := NewIncidentHandler(storage)
+	trash := NewTrashHandler(storage, time.Hour)
+
+	incident := event.NewFiringIncident("Outage", "API down", nil, event.CriticalityMajorOutage)
+	if err := storage.CreateIncident(context.Background(), incident); err != nil {
+		t.Fatalf("CreateIncident failed: %v", err)
+	}
+	if err := storage.DeleteIncident(context.Background(), incident.GUID); err != nil {
+		t.Fatalf("DeleteIncident failed: %v", err)
+	}
+
+	rr := httptest.NewRecorder()
+	trash.HandleTrash(rr, httptest.NewRequest(http.MethodGet, "/api/v1/trash", nil))
+	var listing TrashResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &listing); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with the trash, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(listing.Incidents) != 1 || len(listing.PlannedMaintenances) != 0 {
+		t.Errorf("Expected 1 deleted incident, got %d incidents and %d maintenances", len(listing.Incidents), len(listing.PlannedMaintenances))
+	}
+
+	// The incident was deleted less than the configured hour ago
+	rr = httptest.NewRecorder()
+	trash.HandlePurge(rr, httptest.NewRequest(http.MethodPost, "/api/v1/trash/purge", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"purged":0`) {
+		t.Errorf("Expected nothing purged, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = httptest.NewRecorder()
+	trash.HandlePurge(rr, httptest.NewRequest(http.MethodPost, "/api/v1/trash/purge?older_than=soon", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid older_than, got %d", rr.Code)
+	}
+
+	restore := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/incidents/"+id+"/restore", nil)
+		req.SetPathValue("id", id)
+		rr := httptest.NewRecorder()
+		incidents.HandleIncidentRestore(rr, req)
+		return rr
+	}
+	if rr := restore(incident.GUID); rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"3"` {
+		t.Errorf("Expected 200 with ETag \"3\", got %d with %q", rr.Code, rr.Header().Get("ETag"))
+	}
+	if rr := restore(incident.GUID); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when restoring a live incident, got %d", rr.Code)
+	}
+}
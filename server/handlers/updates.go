@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gmllt/clariti/models/event"
+)
+
+// maxUpdateLength bounds the message of an update
+const maxUpdateLength = 10000
+
+// EventUpdateRequest represents the JSON structure posting an update on an incident or a maintenance
+type EventUpdateRequest struct {
+	Message string `json:"message"`
+}
+
+// validate returns the validation errors of the request
+func (req *EventUpdateRequest) validate() []string {
+	var errors []string
+	if strings.TrimSpace(req.Message) == "" {
+		errors = append(errors, "field 'message' is required and cannot be empty")
+	} else if len(req.Message) > maxUpdateLength {
+		errors = append(errors, "field 'message' cannot be longer than 10000 characters")
+	}
+	return errors
+}
+
+// eventUpdates returns the updates of an event, never nil so that an event without updates is listed as []
+func eventUpdates(base *event.BaseEvent) []event.Update {
+	if base.Updates == nil {
+		return []event.Update{}
+	}
+	return base.Updates
+}
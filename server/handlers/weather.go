@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/gmllt/clariti/models/event"
 	"github.com/gmllt/clariti/server/config"
 	"github.com/gmllt/clariti/server/drivers"
 )
@@ -20,14 +21,21 @@ func NewWeatherHandler(cfg *config.Config, storage drivers.EventStorage) *Weathe
 	}
 }
 
-// HandleWeather returns the current "weather" status of all services
+// HandleWeather returns the current "weather" status of all services, only counting the events
+// matching the selector query parameter when given
 func (wh *WeatherHandler) HandleWeather(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	summary, err := wh.weatherService.GetWeatherSummary()
+	selector, err := event.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := wh.weatherService.GetSelectedWeatherSummary(r.Context(), selector)
 	if err != nil {
 		http.Error(w, "Failed to get weather summary", http.StatusInternalServerError)
 		return
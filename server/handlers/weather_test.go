@@ -112,6 +112,22 @@ func TestWeatherHandler_HandleWeather_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestWeatherHandler_HandleWeather_InvalidSelector(t *testing.T) {
+	handler := NewWeatherHandler(&config.Config{}, drivers.NewRAMStorage())
+
+	req, err := http.NewRequest("GET", "/api/v1/weather?selector=team+in+(payments", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.HandleWeather(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid selector, got %d", http.StatusBadRequest, status)
+	}
+}
+
 func TestWeatherHandler_HandleWeather_WithData(t *testing.T) {
 	// Setup with test data
 	cfg := &config.Config{
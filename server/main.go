@@ -7,6 +7,7 @@ import (
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/gmllt/clariti/server/core"
+	"github.com/gmllt/clariti/server/drivers"
 	"github.com/prometheus/common/version"
 )
 
@@ -15,8 +16,19 @@ var (
 	configPath = app.Flag("config", "Path to configuration file").Short('c').Envar("CONFIG_PATH").Default("config.yaml").String()
 
 	// Commands
-	versionInfo = app.Command("version", "Show detailed version information")
-	serverCmd   = app.Command("serve", "Start the Clariti server").Default()
+	versionInfo  = app.Command("version", "Show detailed version information")
+	serverCmd    = app.Command("serve", "Start the Clariti server").Default()
+	rebuildCmd   = app.Command("rebuild-index", "Rebuild the storage index from the stored events (s3 driver)")
+	migrateCmd   = app.Command("migrate", "Copy all events from one storage to another")
+	reconcileCmd = app.Command("reconcile", "Repair the secondary storage of the mirror driver from the primary one")
+
+	// Migrate flags
+	migrateFrom   = migrateCmd.Flag("from-config", "Configuration file of the source storage").Required().String()
+	migrateTo     = migrateCmd.Flag("to-config", "Configuration file of the destination storage").Required().String()
+	migrateDryRun = migrateCmd.Flag("dry-run", "Report what would be copied without writing anything").Bool()
+
+	// Reconcile flags
+	reconcileDryRun = reconcileCmd.Flag("dry-run", "Report what would be repaired without writing anything").Bool()
 )
 
 func main() {
@@ -33,6 +45,19 @@ func main() {
 	case versionInfo.FullCommand():
 		fmt.Println(version.Print("clariti-server"))
 		return
+	case rebuildCmd.FullCommand():
+		count, err := core.RebuildIndex(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to rebuild index: %v", err)
+		}
+		fmt.Printf("Index rebuilt with %d events\n", count)
+		return
+	case migrateCmd.FullCommand():
+		runMigrate()
+		return
+	case reconcileCmd.FullCommand():
+		runReconcile()
+		return
 	case serverCmd.FullCommand():
 		// Default: start server
 	}
@@ -48,3 +73,63 @@ func main() {
 		log.Fatalf("Server error: %v", err)
 	}
 }
+
+// runMigrate copies the events between the storages of the migrate flags and prints its progress
+func runMigrate() {
+	report, err := core.Migrate(*migrateFrom, *migrateTo, drivers.MigrateOptions{
+		DryRun: *migrateDryRun,
+		Progress: func(kind string, done, total int) {
+			if done%100 == 0 || done == total {
+				fmt.Printf("%s: %d/%d\n", kind, done, total)
+			}
+		},
+	})
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	action := "Copied"
+	if report.DryRun {
+		action = "Would copy"
+	}
+	for _, kind := range []struct {
+		name   string
+		report drivers.MigrationKindReport
+	}{
+		{"incidents", report.Incidents},
+		{"planned maintenances", report.PlannedMaintenances},
+	} {
+		fmt.Printf("%s %d of %d %s (%d already present), checksum %s\n",
+			action, kind.report.Copied, kind.report.Total, kind.name, kind.report.Skipped, kind.report.Checksum)
+	}
+	if !report.DryRun {
+		fmt.Println("Destination verified")
+	}
+}
+
+// runReconcile repairs the secondary storage of the configured mirror and prints what was repaired
+func runReconcile() {
+	report, err := core.Reconcile(*configPath, *reconcileDryRun)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	action := "Repaired"
+	if report.DryRun {
+		action = "Would repair"
+	}
+	for _, kind := range []struct {
+		name   string
+		report drivers.ReconcileKindReport
+	}{
+		{"incidents", report.Incidents},
+		{"planned maintenances", report.PlannedMaintenances},
+	} {
+		fmt.Printf("%s %s: %d created, %d updated, %d deleted out of %d checked",
+			action, kind.name, kind.report.Created, kind.report.Updated, kind.report.Deleted, kind.report.Checked)
+		if kind.report.VersionMismatches > 0 {
+			fmt.Printf(", %d at another version", kind.report.VersionMismatches)
+		}
+		fmt.Println()
+	}
+}
@@ -45,6 +45,21 @@ var (
 		[]string{"status"},
 	)
 
+	plannedMaintenancesOverrunning = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "clariti_planned_maintenances_overrunning",
+			Help: "Current number of started maintenances running past their planned end - delayed landings",
+		},
+	)
+
+	schedulerActionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clariti_scheduler_actions_total",
+			Help: "Total number of actions applied on planned maintenances by the scheduler - autopilot inputs",
+		},
+		[]string{"action"},
+	)
+
 	componentsTotal = promauto.NewGauge(
 		prometheus.GaugeOpts{
 			Name: "clariti_components_total",
@@ -52,6 +67,46 @@ var (
 		},
 	)
 
+	// Storage Metrics - fuel tank gauges
+	storageCacheHitsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clariti_storage_cache_hits_total",
+			Help: "Total number of storage reads served by the cache - fuel saved",
+		},
+		[]string{"kind"},
+	)
+
+	storageCacheMissesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clariti_storage_cache_misses_total",
+			Help: "Total number of storage reads forwarded to the storage driver - fuel burned",
+		},
+		[]string{"kind"},
+	)
+
+	storageCacheEntries = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "clariti_storage_cache_entries",
+			Help: "Current number of entries held by the storage cache - tank level",
+		},
+	)
+
+	storageMirrorDivergencesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clariti_storage_mirror_divergences_total",
+			Help: "Total number of writes that failed on the secondary storage of a mirror - crossfeed imbalance",
+		},
+		[]string{"kind", "operation"},
+	)
+
+	retentionEventsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clariti_retention_events_total",
+			Help: "Total number of events removed by the retention policy - logbooks sent to the archive",
+		},
+		[]string{"kind", "action"},
+	)
+
 	// System Metrics - aircraft status instruments
 	applicationInfo = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -87,11 +142,46 @@ func UpdatePlannedMaintenanceMetrics(status string, count float64) {
 	plannedMaintenancesTotal.WithLabelValues(status).Set(count)
 }
 
+// UpdateOverrunningMaintenances refreshes the overrunning maintenance counter - delayed landings update
+func UpdateOverrunningMaintenances(count float64) {
+	plannedMaintenancesOverrunning.Set(count)
+}
+
+// RecordSchedulerActions counts the actions applied by the scheduler - autopilot inputs
+func RecordSchedulerActions(action string, count int) {
+	schedulerActionsTotal.WithLabelValues(action).Add(float64(count))
+}
+
 // UpdateComponentsCount refreshes component counter - fleet size update
 func UpdateComponentsCount(count float64) {
 	componentsTotal.Set(count)
 }
 
+// RecordStorageCacheHit counts a read served by the storage cache - fuel saved
+func RecordStorageCacheHit(kind string) {
+	storageCacheHitsTotal.WithLabelValues(kind).Inc()
+}
+
+// RecordStorageCacheMiss counts a read forwarded to the storage driver - fuel burned
+func RecordStorageCacheMiss(kind string) {
+	storageCacheMissesTotal.WithLabelValues(kind).Inc()
+}
+
+// UpdateStorageCacheEntries refreshes the storage cache size - tank level update
+func UpdateStorageCacheEntries(count int) {
+	storageCacheEntries.Set(float64(count))
+}
+
+// RecordMirrorDivergence counts a write that failed on the secondary storage of a mirror - crossfeed imbalance
+func RecordMirrorDivergence(kind, operation string) {
+	storageMirrorDivergencesTotal.WithLabelValues(kind, operation).Inc()
+}
+
+// RecordRetainedEvents counts the events removed by the retention policy - logbooks sent to the archive
+func RecordRetainedEvents(kind, action string, count int) {
+	retentionEventsTotal.WithLabelValues(kind, action).Add(float64(count))
+}
+
 // UpdateUptimeMetrics refreshes uptime counter - flight time update
 func UpdateUptimeMetrics() {
 	uptimeSeconds.Set(time.Since(startTime).Seconds())
@@ -3,9 +3,11 @@ package middleware
 import (
 	"crypto/subtle"
 	"net/http"
+	"strings"
 
 	"github.com/gmllt/clariti/logger"
 	"github.com/gmllt/clariti/server/config"
+	"github.com/gmllt/clariti/server/drivers"
 )
 
 // BasicAuth provides HTTP Basic Authentication middleware
@@ -14,9 +16,13 @@ func BasicAuth(config *config.Config) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			log := logger.GetDefault().WithComponent("BasicAuthMiddleware")
 
-			// Skip auth for GET requests (read-only)
-			if r.Method == http.MethodGet {
+			// Skip auth for GET requests (read-only), except on admin endpoints which expose every stored event.
+			// Valid credentials still identify the reader, which then sees the drafts hidden from anonymous ones.
+			if r.Method == http.MethodGet && !strings.HasPrefix(r.URL.Path, "/api/v1/admin/") {
 				log.WithField("method", r.Method).WithField("path", r.URL.Path).Debug("Skipping auth for read-only request")
+				if username, password, ok := r.BasicAuth(); ok && validCredentials(config, username, password) {
+					r = r.WithContext(drivers.WithAuthor(r.Context(), username))
+				}
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -31,11 +37,7 @@ func BasicAuth(config *config.Config) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Use constant-time comparison to prevent timing attacks
-			usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(config.Auth.AdminUsername)) == 1
-			passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(config.Auth.AdminPassword)) == 1
-
-			if !usernameMatch || !passwordMatch {
+			if !validCredentials(config, username, password) {
 				log.WithField("username", username).Warn("Authentication failed - invalid credentials")
 				w.Header().Set("WWW-Authenticate", `Basic realm="Clariti API"`)
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -43,11 +45,20 @@ func BasicAuth(config *config.Config) func(http.Handler) http.Handler {
 			}
 
 			log.WithField("username", username).Info("Authentication successful")
-			next.ServeHTTP(w, r)
+			// Attribute the writes of this request in the revision history
+			next.ServeHTTP(w, r.WithContext(drivers.WithAuthor(r.Context(), username)))
 		})
 	}
 }
 
+// validCredentials reports whether username and password are the admin ones
+func validCredentials(config *config.Config, username, password string) bool {
+	// Use constant-time comparison to prevent timing attacks
+	usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(config.Auth.AdminUsername)) == 1
+	passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(config.Auth.AdminPassword)) == 1
+	return usernameMatch && passwordMatch
+}
+
 // CORS adds basic CORS headers
 func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -57,7 +68,8 @@ func CORS(next http.Handler) http.Handler {
 
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, X-Next-Cursor")
 
 		if r.Method == "OPTIONS" {
 			log.WithField("path", r.URL.Path).Debug("Handling preflight CORS request")
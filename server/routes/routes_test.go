@@ -34,7 +34,7 @@ func TestSetup(t *testing.T) {
 			},
 		},
 	}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	// Setup routes
 	Setup(mux, h, cfg)
@@ -73,7 +73,7 @@ func TestSetupHealthRoutes(t *testing.T) {
 	mux := http.NewServeMux()
 	storage := drivers.NewRAMStorage()
 	cfg := &config.Config{}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	setupHealthRoutes(mux, h)
 
@@ -207,7 +207,7 @@ func TestRoutes_Integration(t *testing.T) {
 			},
 		},
 	}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	Setup(mux, h, cfg)
 
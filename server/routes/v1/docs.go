@@ -72,7 +72,7 @@ func SetupV1DocumentationRoutes(mux *http.ServeMux) {
 					{
 						Path:         "/api/v1/incidents",
 						Methods:      []string{"GET", "POST"},
-						Description:  "List all incidents or create new incident (POST requires auth)",
+						Description:  "List incidents (filters: status, type, criticality, component, from, to, q, selector, sort, limit, cursor) or create new incident (POST requires auth)",
 						AuthRequired: false,
 					},
 					{
@@ -81,12 +81,66 @@ func SetupV1DocumentationRoutes(mux *http.ServeMux) {
 						Description:  "Get, update or delete specific incident (PUT/DELETE require auth)",
 						AuthRequired: false,
 					},
+					{
+						Path:         "/api/v1/incidents/{id}/revisions",
+						Methods:      []string{"GET"},
+						Description:  "Get the revision history of an incident (author, time and changed fields of every write)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/incidents/{id}/restore",
+						Methods:      []string{"POST"},
+						Description:  "Restore a deleted incident from the trash",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/incidents/{id}/transitions",
+						Methods:      []string{"GET", "POST"},
+						Description:  "Get the lifecycle timeline of an incident or move it to another status (investigating, identified, monitoring, resolved) with a public message (POST requires auth)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/incidents/{id}/updates",
+						Methods:      []string{"GET", "POST"},
+						Description:  "List the public updates of an incident, oldest first, or post a new one with a message (POST requires auth)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/incidents/{id}/acknowledge",
+						Methods:      []string{"POST"},
+						Description:  "Record the authenticated user taking charge of an open incident",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/incidents/{id}/resolve",
+						Methods:      []string{"POST"},
+						Description:  "Resolve an open incident, with an optional public message",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/incidents/{id}/reopen",
+						Methods:      []string{"POST"},
+						Description:  "Reopen a resolved incident as investigating, with an optional public message",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/incidents/{id}/postmortem",
+						Methods:      []string{"GET", "PUT", "DELETE"},
+						Description:  "Get, write or delete the postmortem of a resolved incident: summary, root cause, contributing factors, action items and published flag (PUT and DELETE require auth)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/incidents/missing-postmortems",
+						Methods:      []string{"GET"},
+						Description:  "List the resolved incidents without a published postmortem (filter: criticality, the minimum impact, major outage by default)",
+						AuthRequired: false,
+					},
 				},
 				"planned-maintenances": {
 					{
 						Path:         "/api/v1/planned-maintenances",
 						Methods:      []string{"GET", "POST"},
-						Description:  "List all planned maintenances or create new one (POST requires auth)",
+						Description:  "List planned maintenances (filters: status, type, criticality, component, from, to, q, selector, sort, limit, cursor) or create new one (POST requires auth)",
 						AuthRequired: false,
 					},
 					{
@@ -95,12 +149,114 @@ func SetupV1DocumentationRoutes(mux *http.ServeMux) {
 						Description:  "Get, update or delete specific planned maintenance (PUT/DELETE require auth)",
 						AuthRequired: false,
 					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/revisions",
+						Methods:      []string{"GET"},
+						Description:  "Get the revision history of a planned maintenance (author, time and changed fields of every write)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/restore",
+						Methods:      []string{"POST"},
+						Description:  "Restore a deleted planned maintenance from the trash",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/updates",
+						Methods:      []string{"GET", "POST"},
+						Description:  "List the public updates of a planned maintenance, oldest first, or post a new one with a message (POST requires auth)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/start",
+						Methods:      []string{"POST"},
+						Description:  "Start a planned maintenance now, an optional message is posted as an update",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/complete",
+						Methods:      []string{"POST"},
+						Description:  "Complete a started maintenance now, an optional message is posted as an update",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/cancel",
+						Methods:      []string{"POST"},
+						Description:  "Cancel a maintenance which did not start, an optional message is posted as an update",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/occurrences",
+						Methods:      []string{"GET"},
+						Description:  "List the occurrences of a recurring maintenance overlapping from to to (RFC3339, default: the next 90 days)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/planned-maintenances/{id}/occurrences/{recurrence_id}",
+						Methods:      []string{"PUT", "DELETE"},
+						Description:  "Cancel or move the occurrence generated at recurrence_id (RFC3339), or remove its override",
+						AuthRequired: true,
+					},
+				},
+				"trash": {
+					{
+						Path:         "/api/v1/trash",
+						Methods:      []string{"GET"},
+						Description:  "List deleted incidents and planned maintenances, most recently deleted first",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/trash/purge",
+						Methods:      []string{"POST"},
+						Description:  "Permanently remove events deleted longer ago than storage.trash.purge_after (override: older_than)",
+						AuthRequired: true,
+					},
+				},
+				"history": {
+					{
+						Path:         "/api/v1/history/incidents",
+						Methods:      []string{"GET"},
+						Description:  "List the incidents archived by the retention policy (same filters as the incident listing)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/history/incidents/{id}",
+						Methods:      []string{"GET"},
+						Description:  "Get a specific archived incident",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/history/planned-maintenances",
+						Methods:      []string{"GET"},
+						Description:  "List the planned maintenances archived by the retention policy (same filters as the planned maintenance listing)",
+						AuthRequired: false,
+					},
+					{
+						Path:         "/api/v1/history/planned-maintenances/{id}",
+						Methods:      []string{"GET"},
+						Description:  "Get a specific archived planned maintenance",
+						AuthRequired: false,
+					},
+				},
+				"admin": {
+					{
+						Path:         "/api/v1/admin/export",
+						Methods:      []string{"GET"},
+						Description:  "Export every incident and planned maintenance, deleted ones included, as a versioned NDJSON archive",
+						AuthRequired: true,
+					},
+					{
+						Path:         "/api/v1/admin/import",
+						Methods:      []string{"POST"},
+						Description:  "Import an NDJSON archive (mode: merge to add missing events and report conflicts, replace to make the storage match the archive)",
+						AuthRequired: true,
+					},
 				},
 				"weather": {
 					{
 						Path:         "/api/v1/weather",
 						Methods:      []string{"GET"},
-						Description:  "Get current service weather (status overview based on active incidents and maintenances)",
+						Description:  "Get current service weather (status overview based on active incidents and maintenances, filter: selector on their labels)",
 						AuthRequired: false,
 					},
 				},
@@ -31,10 +31,41 @@ func setupV1EventRoutes(mux *http.ServeMux, h *handlers.Handlers) {
 	// Incident endpoints
 	mux.HandleFunc("/api/v1/incidents", h.Incident.HandleIncidents)
 	mux.HandleFunc("/api/v1/incidents/{id}", h.Incident.HandleIncidentByID)
+	mux.HandleFunc("/api/v1/incidents/missing-postmortems", h.Incident.HandleMissingPostmortems)
+	mux.HandleFunc("/api/v1/incidents/{id}/revisions", h.Incident.HandleIncidentRevisions)
+	mux.HandleFunc("/api/v1/incidents/{id}/restore", h.Incident.HandleIncidentRestore)
+	mux.HandleFunc("/api/v1/incidents/{id}/transitions", h.Incident.HandleIncidentTransitions)
+	mux.HandleFunc("/api/v1/incidents/{id}/updates", h.Incident.HandleIncidentUpdates)
+	mux.HandleFunc("/api/v1/incidents/{id}/acknowledge", h.Incident.HandleIncidentAcknowledge)
+	mux.HandleFunc("/api/v1/incidents/{id}/resolve", h.Incident.HandleIncidentResolve)
+	mux.HandleFunc("/api/v1/incidents/{id}/reopen", h.Incident.HandleIncidentReopen)
+	mux.HandleFunc("/api/v1/incidents/{id}/postmortem", h.Incident.HandleIncidentPostmortem)
 
 	// Planned maintenance endpoints
 	mux.HandleFunc("/api/v1/planned-maintenances", h.PlannedMaintenance.HandlePlannedMaintenances)
 	mux.HandleFunc("/api/v1/planned-maintenances/{id}", h.PlannedMaintenance.HandlePlannedMaintenanceByID)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/revisions", h.PlannedMaintenance.HandlePlannedMaintenanceRevisions)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/restore", h.PlannedMaintenance.HandlePlannedMaintenanceRestore)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/updates", h.PlannedMaintenance.HandlePlannedMaintenanceUpdates)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/start", h.PlannedMaintenance.HandlePlannedMaintenanceStart)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/complete", h.PlannedMaintenance.HandlePlannedMaintenanceComplete)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/cancel", h.PlannedMaintenance.HandlePlannedMaintenanceCancel)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/occurrences", h.PlannedMaintenance.HandlePlannedMaintenanceOccurrences)
+	mux.HandleFunc("/api/v1/planned-maintenances/{id}/occurrences/{recurrence_id}", h.PlannedMaintenance.HandlePlannedMaintenanceOccurrence)
+
+	// Trash endpoints
+	mux.HandleFunc("/api/v1/trash", h.Trash.HandleTrash)
+	mux.HandleFunc("/api/v1/trash/purge", h.Trash.HandlePurge)
+
+	// History endpoints (events archived by the retention policy)
+	mux.HandleFunc("/api/v1/history/incidents", h.History.HandleIncidents)
+	mux.HandleFunc("/api/v1/history/incidents/{id}", h.History.HandleIncidentByID)
+	mux.HandleFunc("/api/v1/history/planned-maintenances", h.History.HandlePlannedMaintenances)
+	mux.HandleFunc("/api/v1/history/planned-maintenances/{id}", h.History.HandlePlannedMaintenanceByID)
+
+	// Admin endpoints
+	mux.HandleFunc("/api/v1/admin/export", h.Admin.HandleExport)
+	mux.HandleFunc("/api/v1/admin/import", h.Admin.HandleImport)
 }
 
 // setupV1WeatherRoutes configures weather/status endpoints for v1
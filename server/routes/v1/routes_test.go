@@ -34,7 +34,7 @@ func TestSetupV1Routes(t *testing.T) {
 			},
 		},
 	}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	// Setup routes
 	SetupV1Routes(mux, h)
@@ -95,7 +95,7 @@ func TestSetupV1WeatherRoutes(t *testing.T) {
 			},
 		},
 	}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	// Setup only weather routes
 	setupV1WeatherRoutes(mux, h)
@@ -131,7 +131,7 @@ func TestV1WeatherRoute_MethodNotAllowed(t *testing.T) {
 	mux := http.NewServeMux()
 	storage := drivers.NewRAMStorage()
 	cfg := &config.Config{}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	setupV1WeatherRoutes(mux, h)
 
@@ -177,7 +177,7 @@ func TestSetupV1ComponentRoutes(t *testing.T) {
 			},
 		},
 	}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	setupV1ComponentRoutes(mux, h)
 
@@ -217,7 +217,7 @@ func TestSetupV1EventRoutes(t *testing.T) {
 	mux := http.NewServeMux()
 	storage := drivers.NewRAMStorage()
 	cfg := &config.Config{}
-	h := handlers.New(storage, cfg)
+	h := handlers.New(storage, nil, cfg)
 
 	setupV1EventRoutes(mux, h)
 
@@ -2,6 +2,7 @@ package server_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -374,7 +375,7 @@ func BenchmarkServer_Weather(b *testing.B) {
 			[]*component.Component{comp},
 			event.CriticalityDegraded,
 		)
-		storage.CreateIncident(incident)
+		storage.CreateIncident(context.Background(), incident)
 	}
 
 	b.ResetTimer()